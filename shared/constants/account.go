@@ -0,0 +1,8 @@
+package constants
+
+const (
+	// Types of the transactions on a customer account, used by the statement of account and the AR aging report
+	AccountTransactionTypeInvoice = "INVOICE" // Increases the balance of the customer
+	AccountTransactionTypePayment = "PAYMENT" // Decreases the balance of the customer
+	AccountTransactionTypeCredit  = "CREDIT"  // Credit memo, decreases the balance of the customer
+)
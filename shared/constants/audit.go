@@ -0,0 +1,11 @@
+package constants
+
+const (
+	// Audit log actions
+	AuditActionMerge      = "MERGE"
+	AuditActionDeactivate = "DEACTIVATE"
+
+	// Audit log sources
+	AuditSourceQuickBooks = "QUICKBOOKS"
+	AuditSourceManual     = "MANUAL"
+)
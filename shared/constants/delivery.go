@@ -0,0 +1,31 @@
+package constants
+
+const (
+	// Results of the check of the location a delivery was captured at against the address of the customer
+	DeliveryLocationVerified      = "VERIFIED"       // Within the allowed radius of the customer address
+	DeliveryLocationOutsideRadius = "OUTSIDE_RADIUS" // Further than the allowed radius from the customer address
+	DeliveryLocationUnverified    = "UNVERIFIED"     // No capture location or the customer address is not geocoded
+
+	// Default max distance in meters between the capture location of a delivery and the customer address
+	DefaultDeliveryRadiusMeters = 300.0
+)
+
+const (
+	// Delivery route statuses
+	DeliveryRouteStatusPlanned    = "PLANNED"
+	DeliveryRouteStatusInProgress = "IN_PROGRESS"
+	DeliveryRouteStatusCompleted  = "COMPLETED"
+)
+
+const (
+	// Density of water in pounds per gallon, used for the products without a density
+	DefaultDensityLbsPerGallon = 8.34
+
+	// Aggregate weight of hazardous materials on a vehicle from which the vehicle must display placards
+	// (49 CFR 172.504, table 2)
+	DefaultPlacardThresholdLbs = 1001.0
+
+	// Severities of the issues found when checking the load of a vehicle
+	VehicleLoadIssueWarning  = "WARNING"  // The load can go out but the dispatcher must act, e.g. display placards
+	VehicleLoadIssueBlocking = "BLOCKING" // The load can not go out on the vehicle
+)
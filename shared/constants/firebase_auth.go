@@ -4,6 +4,7 @@ const (
 	RoleSuperAdmin = "super_admin"
 	RoleAdmin      = "admin"
 	RoleUser       = "user"
+	RoleDriver     = "driver" // Delivers the orders of the routes assigned to them
 )
 
 // Map of valid roles.
@@ -12,5 +13,65 @@ var (
 		RoleSuperAdmin: {},
 		RoleAdmin:      {},
 		RoleUser:       {},
+		RoleDriver:     {},
+	}
+)
+
+const (
+	// Custom claims set on the firebase users
+	ClaimRole        = "role"
+	ClaimPermissions = "permissions" // Permissions of the role with the overrides of the account
+
+	// Permissions checked by the handlers, named <resource>:<action>
+	PermissionOrderCreate       = "order:create"
+	PermissionOrderApprove      = "order:approve"
+	PermissionOrderCancel       = "order:cancel"
+	PermissionOrderDeliver      = "order:deliver"
+	PermissionPriceEdit         = "price:edit"
+	PermissionProductEdit       = "product:edit"
+	PermissionCustomerEdit      = "customer:edit"
+	PermissionRoutePlan         = "route:plan"
+	PermissionReportView        = "report:view"
+	PermissionUserManage        = "user:manage"
+	PermissionQuickBooksConnect = "quickbooks:connect"
+)
+
+// Map of valid permissions.
+var (
+	Permissions = map[string]struct{}{
+		PermissionOrderCreate:       {},
+		PermissionOrderApprove:      {},
+		PermissionOrderCancel:       {},
+		PermissionOrderDeliver:      {},
+		PermissionPriceEdit:         {},
+		PermissionProductEdit:       {},
+		PermissionCustomerEdit:      {},
+		PermissionRoutePlan:         {},
+		PermissionReportView:        {},
+		PermissionUserManage:        {},
+		PermissionQuickBooksConnect: {},
+	}
+)
+
+// Permissions granted by each role before the overrides of the account. The super admin has every permission.
+var (
+	RolePermissions = map[string][]string{
+		RoleAdmin: {
+			PermissionOrderCreate,
+			PermissionOrderApprove,
+			PermissionOrderCancel,
+			PermissionOrderDeliver,
+			PermissionPriceEdit,
+			PermissionProductEdit,
+			PermissionCustomerEdit,
+			PermissionRoutePlan,
+			PermissionReportView,
+		},
+		RoleUser: {
+			PermissionOrderCreate,
+		},
+		RoleDriver: {
+			PermissionOrderDeliver,
+		},
 	}
 )
@@ -2,12 +2,33 @@ package constants
 
 // Firestore Collection Constants
 const (
-	OrdersCollection                = "orders"
-	ProductsCollection              = "products"
-	CustomersCollection             = "customers"
-	UsersCollection                 = "users"
-	ProductsPricesPerCustCollection = "products_prices_per_customer"
-	QuickBooksStateCollection       = "quickbooks_states"
-	QuickBooksTokenCollection       = "quickbooks_tokens"
-	ContactUsCollection             = "contact_us"
+	OrdersCollection                  = "orders"
+	ProductsCollection                = "products"
+	CustomersCollection               = "customers"
+	UsersCollection                   = "users"
+	ProductsPricesPerCustCollection   = "products_prices_per_customer"
+	QuickBooksStateCollection         = "quickbooks_states"
+	QuickBooksTokenCollection         = "quickbooks_tokens"
+	ContactUsCollection               = "contact_us"
+	QuickBooksWebhookEntityCollection = "quickbooks_webhook_entities"
+	AuditLogsCollection               = "audit_logs"
+	PriceRulesCollection              = "price_rules"
+	PriceChangeNoticesCollection      = "price_change_notices"
+	ProofsOfDeliveryCollection        = "proofs_of_delivery"
+	DeliveryRoutesCollection          = "delivery_routes"
+	VehiclesCollection                = "vehicles"
+	ProductAttributesCollection       = "product_attributes"
+	ProductSyncReportsCollection      = "product_sync_reports"
+	BrandsCollection                  = "brands"
+	InvitesCollection                 = "invites"
+)
+
+// Firestore Subcollection Constants
+const (
+	PriceHistorySubcollection = "price_history" // Under products and products_prices_per_customer documents
+)
+
+// Cloud Storage Folder Constants
+const (
+	DeliveryImagesFolder = "delivery_images" // Under the orders/{orderID} prefix
 )
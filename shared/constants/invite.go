@@ -0,0 +1,12 @@
+package constants
+
+import "time"
+
+const (
+	// Status of a user invitation
+	InviteStatusPending  = "PENDING"
+	InviteStatusAccepted = "ACCEPTED"
+	InviteStatusRevoked  = "REVOKED"
+
+	InviteExpiry = 7 * 24 * time.Hour // An invite can be resent once expired
+)
@@ -0,0 +1,15 @@
+package constants
+
+const (
+	// Price rule types, from the least to the most specific
+	PriceRuleTypeList             = "LIST" // No rule matched, the list price of the product is used
+	PriceRuleTypeBrandDiscount    = "BRAND_DISCOUNT"
+	PriceRuleTypeCategoryDiscount = "CATEGORY_DISCOUNT"
+	PriceRuleTypeContract         = "CONTRACT" // Fixed price of a product for a customer
+)
+
+const (
+	// Sources of a price change recorded in the price history
+	PriceChangeSourceQuickBooks = "QUICKBOOKS" // List price synced from quickbooks
+	PriceChangeSourceManual     = "MANUAL"     // Price rule changed by an admin
+)
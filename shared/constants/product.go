@@ -0,0 +1,12 @@
+package constants
+
+const (
+	// Sources of the structured attributes of a product, from the highest to the lowest priority
+	ProductAttributeSourceOverride    = "FIRESTORE_OVERRIDE"      // Document of the product in the product_attributes collection
+	ProductAttributeSourceCustomField = "QUICKBOOKS_CUSTOM_FIELD" // Custom fields of the quickbooks item
+	ProductAttributeSourceLegacy      = "QUICKBOOKS_NAME_SKU"     // `Brand - Name` and `SKU - Size - SizeUnit - PackOf` strings of the quickbooks item
+
+	// Severities of the issues found while syncing the products
+	ProductSyncIssueWarning = "WARNING" // The product is synced with a default value
+	ProductSyncIssueError   = "ERROR"   // The product is not created, or keeps its stored attributes
+)
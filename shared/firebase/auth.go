@@ -2,9 +2,12 @@ package firebase_shared
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
+	"firebase.google.com/go/v4/auth"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
 )
 
 // IsAuthorized verifies the Firebase ID token provided in the Authorization header
@@ -23,17 +26,7 @@ import (
 //   - string: The Firebase user's UID if token verification is successful.
 //   - error:  If the Authorization header is malformed or token verification fails.
 func IsAuthorized(request *http.Request) (string, error) {
-	ctx := request.Context()
-
-	authHeader := request.Header.Get("Authorization")
-	parts := strings.SplitN(authHeader, " ", 2)
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		return "", errors.New("invalid Authorization header format")
-	}
-
-	idToken := parts[1]
-
-	token, err := AuthClient.VerifyIDToken(ctx, idToken)
+	token, err := verifyRequestToken(request)
 	if err != nil {
 		return "", err
 	}
@@ -65,23 +58,14 @@ func IsAuthorized(request *http.Request) (string, error) {
 //   - Token verification fails via Firebase Admin SDK.
 //   - The `admin` claim is missing or not set to `true`.
 func CheckAuthorizationByRoles(request *http.Request, roles ...string) (string, error) {
-	ctx := request.Context()
-
-	authHeader := request.Header.Get("Authorization")
-	parts := strings.SplitN(authHeader, " ", 2)
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		return "", errors.New("invalid Authorization header format")
-	}
-
-	idToken := parts[1]
-	token, err := AuthClient.VerifyIDToken(ctx, idToken)
+	token, err := verifyRequestToken(request)
 	if err != nil {
 		return "", err
 	}
 
 	// Check if user has at least one of the allowed roles
 	for _, role := range roles {
-		claimValue, ok := token.Claims["role"].(string)
+		claimValue, ok := token.Claims[constants.ClaimRole].(string)
 		if ok && claimValue != "" && claimValue == role {
 			return token.UID, nil
 		}
@@ -89,3 +73,60 @@ func CheckAuthorizationByRoles(request *http.Request, roles ...string) (string,
 
 	return "", errors.New("Unauthorized: you do not have permission to access this resource")
 }
+
+// RequirePermission verifies the Firebase ID token provided in the Authorization header of an HTTP request
+// and returns the user's UID if the `permissions` custom claim contains the permission.
+//
+// The super admin has every permission. Tokens issued before the `permissions` claim was set fall back to the
+// permissions of their `role` claim, see constants.RolePermissions.
+//
+// Parameters:
+//   - request (*http.Request): The incoming HTTP request expected to contain the Authorization header.
+//   - permission (string): One of the Permission constants, e.g. constants.PermissionOrderApprove.
+//
+// Returns:
+//   - (string): The UID (User ID) of the authenticated Firebase user.
+//   - (error): If the token is missing or invalid, or the user does not have the permission.
+func RequirePermission(request *http.Request, permission string) (string, error) {
+	token, err := verifyRequestToken(request)
+	if err != nil {
+		return "", err
+	}
+
+	role, _ := token.Claims[constants.ClaimRole].(string)
+	if role == constants.RoleSuperAdmin {
+		return token.UID, nil
+	}
+	for _, granted := range getPermissionClaims(token, role) {
+		if granted == permission {
+			return token.UID, nil
+		}
+	}
+	return "", fmt.Errorf("Unauthorized: you do not have the %s permission", permission)
+}
+
+// verifyRequestToken verifies the Bearer token of the Authorization header.
+func verifyRequestToken(request *http.Request) (*auth.Token, error) {
+	authHeader := request.Header.Get("Authorization")
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, errors.New("invalid Authorization header format")
+	}
+	return AuthClient.VerifyIDToken(request.Context(), parts[1])
+}
+
+// getPermissionClaims returns the permissions of the token. The claims are decoded from json so the list is
+// a []any.
+func getPermissionClaims(token *auth.Token, role string) []string {
+	claim, ok := token.Claims[constants.ClaimPermissions].([]any)
+	if !ok {
+		return constants.RolePermissions[role]
+	}
+	permissions := make([]string, 0, len(claim))
+	for _, value := range claim {
+		if permission, ok := value.(string); ok {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
+}
@@ -69,3 +69,15 @@ func LoadSecretsHelper(projectID string, secretName string) string {
 	}
 	return secret
 }
+
+// LoadOptionalSecretHelper loads a secret that is not required to start, an empty string is returned when it
+// cannot be loaded and the feature using it reports the error instead.
+func LoadOptionalSecretHelper(projectID string, secretName string) string {
+	path := fmt.Sprintf("projects/%s/secrets/%s/versions/latest", projectID, secretName)
+	secret, err := getSecretFromGCP(path)
+	if err != nil {
+		log.Printf("Optional secret %s is not loaded: %v", secretName, err)
+		return ""
+	}
+	return secret
+}
@@ -0,0 +1,54 @@
+package mocks
+
+import (
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+// CreateMockAccountTransactions returns two invoices, a partial payment and a credit of a customer over the
+// two months before MockTime.
+func CreateMockAccountTransactions(customerID string) []*models.AccountTransaction {
+	return []*models.AccountTransaction{
+		{
+			ID:          "inv-1",
+			CustomerID:  customerID,
+			Type:        constants.AccountTransactionTypeInvoice,
+			Number:      "1001",
+			Description: "Invoice for order 1",
+			Date:        MockTime.AddDate(0, -2, 0),
+			DueDate:     MockTime.AddDate(0, -1, 0),
+			Amount:      500,
+			OpenBalance: 200,
+		},
+		{
+			ID:          "pay-1",
+			CustomerID:  customerID,
+			Type:        constants.AccountTransactionTypePayment,
+			Number:      "5001",
+			Description: "Payment for invoice 1001",
+			Date:        MockTime.AddDate(0, -1, -10),
+			Amount:      300,
+		},
+		{
+			ID:          "inv-2",
+			CustomerID:  customerID,
+			Type:        constants.AccountTransactionTypeInvoice,
+			Number:      "1002",
+			Description: "Invoice for order 2",
+			Date:        MockTime.AddDate(0, 0, -10),
+			DueDate:     MockTime.AddDate(0, 0, 20),
+			Amount:      250.5,
+			OpenBalance: 250.5,
+		},
+		{
+			ID:          "cm-1",
+			CustomerID:  customerID,
+			Type:        constants.AccountTransactionTypeCredit,
+			Number:      "7001",
+			Description: "Credit for a damaged item",
+			Date:        MockTime.AddDate(0, 0, -5),
+			Amount:      25,
+			OpenBalance: 25,
+		},
+	}
+}
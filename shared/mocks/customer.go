@@ -2,8 +2,6 @@
 package mocks
 
 import (
-	"time"
-
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
 )
 
@@ -19,8 +17,8 @@ func CreateMockCustomer() *models.Customer {
 		Phone: "702-555-5555",
 		IsActive: true,
 		Country: "US",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		CreatedAt: MockTime,
+		UpdatedAt: MockTime,
 	}
 }
 
@@ -1,6 +1,11 @@
 package mocks
 
 import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
 	"mime/multipart"
 
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
@@ -35,4 +40,31 @@ func createMockDeliveryImages() []multipart.File{
 		files = append(files, file)
 	}
 	return files
-}
\ No newline at end of file
+}
+
+// CreateMockDelivery returns a delivery of a mock order with a generated signature and two delivery images,
+// so it does not depend on any file.
+func CreateMockDelivery(itemLength int) *models.Delivery {
+	return &models.Delivery{
+		Order:          CreateMockOrder(itemLength),
+		ReceivedBy:     "Harsh Mohan",
+		DeliveredBy:    "Harsh",
+		Signature:      createMockImage(120, 40),
+		DeliveryImages: [][]byte{createMockImage(80, 60), createMockImage(60, 80)},
+		DeliveredAt:    MockTime,
+	}
+}
+
+// createMockImage returns a png with a diagonal line so the orientation of the image is visible.
+func createMockImage(width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	for x := range width {
+		img.Set(x, x*height/width, color.Black)
+	}
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		panic(err)
+	}
+	return buffer.Bytes()
+}
@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"fmt"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+// CreateMockDeliveryRoute returns a route of approved orders whose customers are spread north of the depot,
+// given in the reverse order of the stops.
+func CreateMockDeliveryRoute(stops int) *models.DeliveryRoute {
+	orders := make([]*models.Order, 0, stops)
+	for i := stops; i > 0; i-- {
+		order := CreateMockOrder(2)
+		order.ID = fmt.Sprintf("order-%d", i)
+		order.Status = constants.OrderStatusApproved
+		order.Customer.ID = fmt.Sprintf("%d", i)
+		order.Customer.Name = fmt.Sprintf("Customer %d", i)
+		order.Customer.Latitude = 36.1716 + 0.01*float64(i)
+		order.Customer.Longitude = -115.1391
+		orders = append(orders, order)
+	}
+	depot := &models.GeoLocation{Latitude: 36.1716, Longitude: -115.1391}
+	route, err := models.NewDeliveryRoute("route-1", "driver-1", "Harsh", CreateMockVehicle(), MockTime, depot, orders)
+	if err != nil {
+		panic(err)
+	}
+	return route
+}
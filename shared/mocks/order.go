@@ -1,8 +1,6 @@
 package mocks
 
 import (
-	"time"
-
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
 )
 
@@ -15,8 +13,8 @@ func CreateMockOrder(itemLength int) *models.Order{
 		Total: 100,
 		SubTotal: 200,
 		Status: "PENDING",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		CreatedAt: MockTime,
+		UpdatedAt: MockTime,
 		TaxRate: 0.0124,
 		TaxAmount: 20,
 	}
@@ -1,8 +1,6 @@
 package mocks
 
 import (
-	"time"
-
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
 )
 
@@ -24,8 +22,8 @@ func CreateMockProduct() *models.Product {
 		Slug:          "industrial-cleaner-500ml",
 		NameKey:       "industrialcleaner",
 		Quantity:      200,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
+		CreatedAt:     MockTime,
+		UpdatedAt:     MockTime,
 	}
 }
 
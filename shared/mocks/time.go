@@ -0,0 +1,7 @@
+package mocks
+
+import "time"
+
+// MockTime is used for all the dates of the mock data so the data, and the pdfs rendered from it, are the
+// same on every run.
+var MockTime = time.Date(2025, time.March, 14, 17, 30, 0, 0, time.UTC)
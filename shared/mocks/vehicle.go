@@ -0,0 +1,15 @@
+package mocks
+
+import "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+
+// CreateMockVehicle returns an active placarded truck without capacity limits.
+func CreateMockVehicle() *models.Vehicle {
+	return &models.Vehicle{
+		ID:        "vehicle-1",
+		Name:      "Truck 7",
+		IsActive:  true,
+		Placarded: true,
+		CreatedAt: MockTime,
+		UpdatedAt: MockTime,
+	}
+}
@@ -0,0 +1,345 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+)
+
+// ErrAccessDenied is wrapped by every error returned when the caller is not assigned to a customer or a brand,
+// use errors.Is to tell it apart from the other errors.
+var ErrAccessDenied = errors.New("Access denied")
+
+// AccessScope is what the caller of a request can read and write: the customers and the brands assigned to
+// their account. The super admin can access everything, a nil scope nothing.
+type AccessScope struct {
+	UID       string
+	Role      string
+	customers map[string]bool
+	brands    map[string]bool // Lower case names, the brands of old accounts are not normalized
+}
+
+// NewAccessScope creates the scope of a caller.
+//
+// Parameters:
+//   - uid: firebase uid of the caller
+//   - role: role of the caller from the custom claims of the token
+//   - account: account of the caller, can be nil for the super admin
+func NewAccessScope(uid, role string, account *UserAccount) *AccessScope {
+	scope := &AccessScope{
+		UID:       uid,
+		Role:      role,
+		customers: make(map[string]bool),
+		brands:    make(map[string]bool),
+	}
+	if account == nil {
+		return scope
+	}
+	for _, customerID := range account.Customers {
+		scope.customers[customerID] = true
+	}
+	for _, brand := range account.Brands {
+		scope.brands[strings.ToLower(strings.TrimSpace(brand))] = true
+	}
+	return scope
+}
+
+func (s *AccessScope) IsSuperAdmin() bool {
+	return s != nil && s.Role == constants.RoleSuperAdmin
+}
+
+// GetCustomerIDs returns the sorted ids of the assigned customers.
+func (s *AccessScope) GetCustomerIDs() []string {
+	if s == nil {
+		return []string{}
+	}
+	customerIDs := make([]string, 0, len(s.customers))
+	for customerID := range s.customers {
+		customerIDs = append(customerIDs, customerID)
+	}
+	sort.Strings(customerIDs)
+	return customerIDs
+}
+
+func (s *AccessScope) CanAccessCustomer(customerID string) bool {
+	if s.IsSuperAdmin() {
+		return true
+	}
+	return s != nil && s.customers[customerID]
+}
+
+// CanAccessBrand reports whether the brand is assigned to the caller, ignoring the case.
+func (s *AccessScope) CanAccessBrand(brand string) bool {
+	if s.IsSuperAdmin() {
+		return true
+	}
+	return s != nil && s.brands[strings.ToLower(strings.TrimSpace(brand))]
+}
+
+// AuthorizeCustomer returns an error wrapping ErrAccessDenied if the customer is not assigned to the caller.
+func (s *AccessScope) AuthorizeCustomer(customerID string) error {
+	if !s.CanAccessCustomer(customerID) {
+		return fmt.Errorf("%w, the customer %s is not assigned to your account", ErrAccessDenied, customerID)
+	}
+	return nil
+}
+
+// AuthorizeProduct returns an error wrapping ErrAccessDenied if the brand of the product is not assigned to
+// the caller.
+func (s *AccessScope) AuthorizeProduct(product *Product) error {
+	if !s.CanAccessBrand(product.Brand) {
+		return fmt.Errorf("%w, the brand %s of the product %s is not assigned to your account", ErrAccessDenied, product.Brand, product.Name)
+	}
+	return nil
+}
+
+// AuthorizeOrder checks the customer of the order and the brand of every item are assigned to the caller.
+// The items must be complete, see Order.ToCompleteOrderItemsFromMinimal.
+func (s *AccessScope) AuthorizeOrder(order *Order) error {
+	if order.Customer == nil {
+		return errors.New("Customer of the order cannot be empty")
+	}
+	if err := s.AuthorizeCustomer(order.Customer.ID); err != nil {
+		return err
+	}
+	for _, item := range order.Items {
+		if err := s.AuthorizeProduct(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orderUpdatePathsForCaller are the fields of an order a scoped caller can update. The status has its own
+// checks and the bill is computed from the items, see services.UpdateOrderForCaller.
+var orderUpdatePathsForCaller = map[string]bool{
+	"customerId":          true,
+	"items":               true,
+	"specialInstructions": true,
+}
+
+// AuthorizeOrderUpdates checks the updates of an order do not move it out of the scope of the caller: a new
+// customer must be assigned to the caller and the products of new items must be of assigned brands. A scoped
+// caller can only update the customer, the items and the special instructions of an order, the embedded
+// customer and single items cannot be updated.
+//
+// Parameters:
+//   - updates: the updates of the order, the items are minimal maps, see Order.ToMapItems
+//   - products: the stored products of the updated items, see GetOrderUpdateProductIDs
+func (s *AccessScope) AuthorizeOrderUpdates(updates []firestore.Update, products map[string]*Product) error {
+	if s.IsSuperAdmin() {
+		return nil
+	}
+	for _, update := range updates {
+		path := GetOrderUpdatePath(update)
+		switch {
+		case path == "customerId":
+			customerID, ok := update.Value.(string)
+			if !ok {
+				return errors.New("Customer of the order is not valid")
+			}
+			if err := s.AuthorizeCustomer(customerID); err != nil {
+				return err
+			}
+		case path == "customer" || strings.HasPrefix(path, "customer."):
+			return fmt.Errorf("%w, the customer of an order is changed through its customerId", ErrAccessDenied)
+		case path == "items":
+			productIDs, err := getItemProductIDs(update.Value)
+			if err != nil {
+				return err
+			}
+			for _, productID := range productIDs {
+				product, ok := products[productID]
+				if !ok {
+					return fmt.Errorf("Product %s of the order does not exist", productID)
+				}
+				if err := s.AuthorizeProduct(product); err != nil {
+					return err
+				}
+			}
+		case strings.HasPrefix(path, "items."):
+			return fmt.Errorf("%w, the items of an order are updated all at once", ErrAccessDenied)
+		case !orderUpdatePathsForCaller[path]:
+			return fmt.Errorf("%w, the field %s of an order cannot be changed", ErrAccessDenied, path)
+		}
+	}
+	return nil
+}
+
+// GetOrderUpdateProductIDs returns the ids of the products of the items set by the updates of an order.
+func GetOrderUpdateProductIDs(updates []firestore.Update) ([]string, error) {
+	productIDs := make([]string, 0)
+	for _, update := range updates {
+		if GetOrderUpdatePath(update) != "items" {
+			continue
+		}
+		ids, err := getItemProductIDs(update.Value)
+		if err != nil {
+			return nil, err
+		}
+		productIDs = append(productIDs, ids...)
+	}
+	return productIDs, nil
+}
+
+// GetOrderUpdateItems returns the items set by the updates of an order with only their id and quantity, since
+// the prices sent by a caller cannot be trusted. It returns nil if the items are not updated.
+func GetOrderUpdateItems(updates []firestore.Update) ([]*Product, error) {
+	var items []*Product
+	for _, update := range updates {
+		if GetOrderUpdatePath(update) != "items" {
+			continue
+		}
+		minimalItems, err := getMinimalItems(update.Value)
+		if err != nil {
+			return nil, err
+		}
+		items = make([]*Product, 0, len(minimalItems))
+		for _, minimalItem := range minimalItems {
+			item := &Product{ID: minimalItem["id"].(string)}
+			switch quantity := minimalItem["quantity"].(type) {
+			case int:
+				item.SetQuantity(quantity)
+			case int64:
+				item.SetQuantity(int(quantity))
+			case float64:
+				item.SetQuantity(int(quantity))
+			}
+			if item.Quantity < 1 {
+				return nil, fmt.Errorf("Quantity of the product %s must be at least 1", item.ID)
+			}
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// GetOrderUpdatePath returns the path of an update of an order, set as a path or a field path.
+func GetOrderUpdatePath(update firestore.Update) string {
+	if len(update.FieldPath) > 0 {
+		return strings.Join(update.FieldPath, ".")
+	}
+	return update.Path
+}
+
+// getItemProductIDs returns the product ids of the minimal items of an order, decoded from json or not.
+func getItemProductIDs(value any) ([]string, error) {
+	items, err := getMinimalItems(value)
+	if err != nil {
+		return nil, err
+	}
+	productIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, item["id"].(string))
+	}
+	return productIDs, nil
+}
+
+// getMinimalItems returns the minimal items of an order, decoded from json or not, every item has an id.
+func getMinimalItems(value any) ([]map[string]any, error) {
+	var items []map[string]any
+	switch v := value.(type) {
+	case []map[string]any:
+		items = v
+	case []any:
+		for _, item := range v {
+			itemMap, ok := item.(map[string]any)
+			if !ok {
+				return nil, errors.New("Items of the order are not valid")
+			}
+			items = append(items, itemMap)
+		}
+	default:
+		return nil, errors.New("Items of the order are not valid")
+	}
+	for _, item := range items {
+		if productID, ok := item["id"].(string); !ok || productID == "" {
+			return nil, errors.New("Items of the order are not valid")
+		}
+	}
+	return items, nil
+}
+
+// AuthorizeCategory checks every product of the category is of a brand assigned to the caller, since a
+// category discount prices the products of every brand of the category.
+//
+// Parameters:
+//   - category: the category of the discount
+//   - products: the active products
+func (s *AccessScope) AuthorizeCategory(category string, products []*Product) error {
+	if s.IsSuperAdmin() {
+		return nil
+	}
+	for _, product := range products {
+		if product.Category == category && !s.CanAccessBrand(product.Brand) {
+			return fmt.Errorf("%w, the category %s has products of the brand %s which is not assigned to your account", ErrAccessDenied, category, product.Brand)
+		}
+	}
+	return nil
+}
+
+// AuthorizePriceRule checks the customer and the brand of the rule are assigned to the caller. A rule without
+// a customer changes the prices of every customer so only the super admin can write it. The product of a
+// contract and the products of a category discount are checked by the caller, see AuthorizeProduct and
+// AuthorizeCategory.
+func (s *AccessScope) AuthorizePriceRule(rule *PriceRule) error {
+	if s.IsSuperAdmin() {
+		return nil
+	}
+	if rule.CustomerID == "" {
+		return fmt.Errorf("%w, only the super admin can change the prices of every customer", ErrAccessDenied)
+	}
+	if err := s.AuthorizeCustomer(rule.CustomerID); err != nil {
+		return err
+	}
+	if rule.Brand != "" && !s.CanAccessBrand(rule.Brand) {
+		return fmt.Errorf("%w, the brand %s is not assigned to your account", ErrAccessDenied, rule.Brand)
+	}
+	return nil
+}
+
+// FilterProducts returns the products of the brands assigned to the caller.
+func (s *AccessScope) FilterProducts(products []*Product) []*Product {
+	filtered := make([]*Product, 0, len(products))
+	for _, product := range products {
+		if s.CanAccessBrand(product.Brand) {
+			filtered = append(filtered, product)
+		}
+	}
+	return filtered
+}
+
+// FilterCustomers returns the customers assigned to the caller.
+func (s *AccessScope) FilterCustomers(customers []*Customer) []*Customer {
+	filtered := make([]*Customer, 0, len(customers))
+	for _, customer := range customers {
+		if s.CanAccessCustomer(customer.ID) {
+			filtered = append(filtered, customer)
+		}
+	}
+	return filtered
+}
+
+// FilterOrders returns the orders the caller can access, see AuthorizeOrder.
+func (s *AccessScope) FilterOrders(orders []*Order) []*Order {
+	filtered := make([]*Order, 0, len(orders))
+	for _, order := range orders {
+		if s.AuthorizeOrder(order) == nil {
+			filtered = append(filtered, order)
+		}
+	}
+	return filtered
+}
+
+// FilterPriceList returns a copy of the price list with the products of the brands assigned to the caller.
+func (s *AccessScope) FilterPriceList(priceList *PriceList) *PriceList {
+	products := make([]*Product, 0, len(priceList.Products))
+	for _, product := range priceList.Products {
+		products = append(products, product)
+	}
+	return NewPriceList(priceList.CustomerID, s.FilterProducts(products), priceList.Rules)
+}
@@ -0,0 +1,242 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+)
+
+// AccountTransaction is an invoice, payment or credit memo on a customer account, usually mapped from
+// quickbooks.
+//
+// Amount is always positive, the Type decides if it increases or decreases the balance. OpenBalance is the
+// part of the Amount that is still open: the unpaid amount of an invoice or the unapplied amount of a payment
+// or credit. DueDate is only used by invoices, a zero DueDate uses Date.
+type AccountTransaction struct {
+	ID          string    `json:"id"`
+	CustomerID  string    `json:"customerId"`
+	Type        string    `json:"type"` // constants.AccountTransactionType...
+	Number      string    `json:"number"`
+	Description string    `json:"description"`
+	Date        time.Time `json:"date"`
+	DueDate     time.Time `json:"dueDate"`
+	Amount      float64   `json:"amount"`
+	OpenBalance float64   `json:"openBalance"`
+}
+
+// GetSignedAmount returns the change of the customer balance caused by the transaction.
+func (t *AccountTransaction) GetSignedAmount() float64 {
+	if t.Type == constants.AccountTransactionTypeInvoice {
+		return t.Amount
+	}
+	return -t.Amount
+}
+
+// GetDaysPastDue returns the number of whole days the transaction is past its due date at the given time.
+// Zero or negative means it is not due yet.
+func (t *AccountTransaction) GetDaysPastDue(asOf time.Time) int {
+	dueDate := t.DueDate
+	if dueDate.IsZero() {
+		dueDate = t.Date
+	}
+	return int(truncateToDay(asOf).Sub(truncateToDay(dueDate)).Hours() / 24)
+}
+
+// StatementLine is a transaction on the statement of account with the balance after it.
+type StatementLine struct {
+	Transaction    *AccountTransaction
+	RunningBalance float64
+}
+
+// AccountStatement is the statement of account of a customer over a date range. The opening balance is the
+// balance of all the transactions before From.
+type AccountStatement struct {
+	Customer       *Customer
+	From           time.Time
+	To             time.Time
+	OpeningBalance float64
+	Lines          []*StatementLine
+	TotalInvoiced  float64
+	TotalPaid      float64 // Payments and credits
+	ClosingBalance float64
+	Aging          ARAgingBuckets // Aging of the open transactions at To
+}
+
+// NewAccountStatement creates the statement of account of a customer from all of its transactions. The
+// transactions are sorted by date, the ones after To are ignored. From and To are inclusive days.
+func NewAccountStatement(customer *Customer, transactions []*AccountTransaction, from, to time.Time) *AccountStatement {
+	from, to = truncateToDay(from), truncateToDay(to).AddDate(0, 0, 1)
+
+	sorted := make([]*AccountTransaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		if transaction.Date.Before(to) {
+			sorted = append(sorted, transaction)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+
+	statement := &AccountStatement{
+		Customer: customer,
+		From:     from,
+		To:       to.AddDate(0, 0, -1),
+		Lines:    make([]*StatementLine, 0),
+	}
+	balance := 0.0
+	for _, transaction := range sorted {
+		balance = roundToCents(balance + transaction.GetSignedAmount())
+		if transaction.Date.Before(from) {
+			statement.OpeningBalance = balance
+			continue
+		}
+		if transaction.Type == constants.AccountTransactionTypeInvoice {
+			statement.TotalInvoiced += transaction.Amount
+		} else {
+			statement.TotalPaid += transaction.Amount
+		}
+		statement.Lines = append(statement.Lines, &StatementLine{Transaction: transaction, RunningBalance: balance})
+	}
+	statement.TotalInvoiced = roundToCents(statement.TotalInvoiced)
+	statement.TotalPaid = roundToCents(statement.TotalPaid)
+	statement.ClosingBalance = balance
+	statement.Aging = NewARAgingBuckets(sorted, statement.To)
+	return statement
+}
+
+// ARAgingBuckets is the open balance of a customer split by the number of days past due.
+type ARAgingBuckets struct {
+	Current    float64 `json:"current"` // Not due yet, unapplied payments and credits are subtracted here
+	Days1To30  float64 `json:"days1To30"`
+	Days31To60 float64 `json:"days31To60"`
+	Days61To90 float64 `json:"days61To90"`
+	Over90     float64 `json:"over90"`
+}
+
+// NewARAgingBuckets ages the open balance of the transactions at the given time.
+func NewARAgingBuckets(transactions []*AccountTransaction, asOf time.Time) ARAgingBuckets {
+	var buckets ARAgingBuckets
+	for _, transaction := range transactions {
+		buckets.Add(transaction, asOf)
+	}
+	return buckets
+}
+
+// Add adds the open balance of the transaction to its bucket.
+func (b *ARAgingBuckets) Add(transaction *AccountTransaction, asOf time.Time) {
+	if transaction.OpenBalance == 0 {
+		return
+	}
+	if transaction.Type != constants.AccountTransactionTypeInvoice {
+		b.Current = roundToCents(b.Current - transaction.OpenBalance)
+		return
+	}
+	days := transaction.GetDaysPastDue(asOf)
+	switch {
+	case days <= 0:
+		b.Current = roundToCents(b.Current + transaction.OpenBalance)
+	case days <= 30:
+		b.Days1To30 = roundToCents(b.Days1To30 + transaction.OpenBalance)
+	case days <= 60:
+		b.Days31To60 = roundToCents(b.Days31To60 + transaction.OpenBalance)
+	case days <= 90:
+		b.Days61To90 = roundToCents(b.Days61To90 + transaction.OpenBalance)
+	default:
+		b.Over90 = roundToCents(b.Over90 + transaction.OpenBalance)
+	}
+}
+
+// Merge adds the buckets of another customer, used for the report totals.
+func (b *ARAgingBuckets) Merge(other ARAgingBuckets) {
+	b.Current = roundToCents(b.Current + other.Current)
+	b.Days1To30 = roundToCents(b.Days1To30 + other.Days1To30)
+	b.Days31To60 = roundToCents(b.Days31To60 + other.Days31To60)
+	b.Days61To90 = roundToCents(b.Days61To90 + other.Days61To90)
+	b.Over90 = roundToCents(b.Over90 + other.Over90)
+}
+
+func (b *ARAgingBuckets) GetTotal() float64 {
+	return roundToCents(b.Current + b.Days1To30 + b.Days31To60 + b.Days61To90 + b.Over90)
+}
+
+// GetOverdue returns the balance past its due date, all the buckets except Current.
+func (b *ARAgingBuckets) GetOverdue() float64 {
+	return roundToCents(b.Days1To30 + b.Days31To60 + b.Days61To90 + b.Over90)
+}
+
+// GetFormattedValues returns the buckets followed by the total, in the order of the report columns.
+func (b *ARAgingBuckets) GetFormattedValues() []string {
+	return []string{
+		FormatAmount(b.Current),
+		FormatAmount(b.Days1To30),
+		FormatAmount(b.Days31To60),
+		FormatAmount(b.Days61To90),
+		FormatAmount(b.Over90),
+		FormatAmount(b.GetTotal()),
+	}
+}
+
+// ARAgingRow is the aging of a single customer in the AR aging report.
+type ARAgingRow struct {
+	Customer *Customer
+	Buckets  ARAgingBuckets
+}
+
+// ARAgingReport is the open balance of all the customers split by the number of days past due.
+type ARAgingReport struct {
+	AsOf   time.Time
+	Rows   []*ARAgingRow
+	Totals ARAgingBuckets
+}
+
+// NewARAgingReport ages the transactions of all the customers at the given time. The rows are sorted by
+// customer name, customers without an open balance are left out. Transactions of a customer not in
+// customers are grouped under a customer with only the id.
+func NewARAgingReport(customers []*Customer, transactions []*AccountTransaction, asOf time.Time) *ARAgingReport {
+	customerMap := make(map[string]*Customer, len(customers))
+	for _, customer := range customers {
+		customerMap[customer.ID] = customer
+	}
+
+	rowMap := make(map[string]*ARAgingRow)
+	for _, transaction := range transactions {
+		row, ok := rowMap[transaction.CustomerID]
+		if !ok {
+			customer, ok := customerMap[transaction.CustomerID]
+			if !ok {
+				customer = &Customer{ID: transaction.CustomerID, Name: transaction.CustomerID}
+			}
+			row = &ARAgingRow{Customer: customer}
+			rowMap[transaction.CustomerID] = row
+		}
+		row.Buckets.Add(transaction, asOf)
+	}
+
+	report := &ARAgingReport{AsOf: asOf, Rows: make([]*ARAgingRow, 0, len(rowMap))}
+	for _, row := range rowMap {
+		if row.Buckets == (ARAgingBuckets{}) {
+			continue
+		}
+		report.Rows = append(report.Rows, row)
+		report.Totals.Merge(row.Buckets)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if report.Rows[i].Customer.Name != report.Rows[j].Customer.Name {
+			return report.Rows[i].Customer.Name < report.Rows[j].Customer.Name
+		}
+		return report.Rows[i].Customer.ID < report.Rows[j].Customer.ID
+	})
+	return report
+}
+
+// FormatAmount formats a dollar amount, negative amounts are formatted as -$10.00
+func FormatAmount(amount float64) string {
+	if amount < 0 {
+		return fmt.Sprintf("-$%.2f", -amount)
+	}
+	return fmt.Sprintf("$%.2f", amount)
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
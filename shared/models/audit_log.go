@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// AuditEntry records a change made to a firestore document that is not visible from the document itself,
+// such as a quickbooks merge or delete.
+type AuditEntry struct {
+	Action     string         `json:"action" firestore:"action"`         // e.g. "MERGE", "DEACTIVATE"
+	Collection string         `json:"collection" firestore:"collection"` // Collection of the affected document
+	DocumentID string         `json:"documentId" firestore:"documentId"`
+	TargetID   string         `json:"targetId" firestore:"targetId"` // Surviving document id on a merge
+	Source     string         `json:"source" firestore:"source"`     // e.g. "QUICKBOOKS"
+	Details    map[string]any `json:"details" firestore:"details"`
+	CreatedAt  time.Time      `json:"createdAt" firestore:"createdAt"`
+}
+
+func NewAuditEntry(action, collection, documentID, source string) *AuditEntry {
+	return &AuditEntry{
+		Action:     action,
+		Collection: collection,
+		DocumentID: documentID,
+		Source:     source,
+		Details:    make(map[string]any),
+	}
+}
+
+func (a *AuditEntry) SetTargetID(targetID string) {
+	a.TargetID = targetID
+}
+
+func (a *AuditEntry) AddDetail(key string, value any) {
+	a.Details[key] = value
+}
+
+func (a *AuditEntry) ToMap() map[string]any {
+	return map[string]any{
+		"action":     a.Action,
+		"collection": a.Collection,
+		"documentId": a.DocumentID,
+		"targetId":   a.TargetID,
+		"source":     a.Source,
+		"details":    a.Details,
+		"createdAt":  firestore.ServerTimestamp,
+	}
+}
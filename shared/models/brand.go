@@ -0,0 +1,135 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/utils"
+)
+
+var brandIDSanitizer = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Brand is a brand of the catalog stored in firestore collection ('brands'). Products and users refer to a
+// brand by its display name, the aliases are the other spellings found in the quickbooks item names.
+type Brand struct {
+	ID        string    `json:"id" firestore:"id"` // Slug of the name, e.g. "micro-tech"
+	Name      string    `json:"name" firestore:"name"`
+	Aliases   []string  `json:"aliases" firestore:"aliases"`
+	LogoURL   string    `json:"logoUrl" firestore:"logoUrl"`
+	IsActive  bool      `json:"isActive" firestore:"isActive"`
+	CreatedAt time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// NewBrand creates an active brand with the id derived from its name.
+func NewBrand(name string, aliases []string, logoURL string) (*Brand, error) {
+	now := time.Now().UTC()
+	brand := &Brand{
+		ID:        GetBrandID(name),
+		Name:      strings.TrimSpace(name),
+		Aliases:   aliases,
+		LogoURL:   logoURL,
+		IsActive:  true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := brand.Validate(); err != nil {
+		return nil, err
+	}
+	return brand, nil
+}
+
+// GetBrandID returns the document id of a brand name, e.g. "Micro Tech" is "micro-tech".
+func GetBrandID(name string) string {
+	return strings.Trim(brandIDSanitizer.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+func (b *Brand) Validate() error {
+	if b.ID == "" || b.Name == "" {
+		return errors.New("Name of the brand cannot be empty")
+	}
+	if utils.HasDuplicateStrings(b.Aliases) {
+		return errors.New("Aliases of the brand cannot have duplicates")
+	}
+	for _, alias := range b.Aliases {
+		if strings.TrimSpace(alias) == "" {
+			return errors.New("Aliases of the brand cannot be empty")
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the name is the name, the id or an alias of the brand, ignoring the case and the
+// surrounding spaces.
+func (b *Brand) Matches(name string) bool {
+	name = strings.TrimSpace(name)
+	if strings.EqualFold(name, b.Name) || strings.EqualFold(name, b.ID) {
+		return true
+	}
+	for _, alias := range b.Aliases {
+		if strings.EqualFold(name, strings.TrimSpace(alias)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Brand) ToMap() map[string]any {
+	return map[string]any{
+		"id":        b.ID,
+		"name":      b.Name,
+		"aliases":   b.Aliases,
+		"logoUrl":   b.LogoURL,
+		"isActive":  b.IsActive,
+		"createdAt": firestore.ServerTimestamp,
+		"updatedAt": firestore.ServerTimestamp,
+	}
+}
+
+// BrandCatalog looks up the brands by their name, id or aliases. A nil catalog has no brands.
+type BrandCatalog struct {
+	brands []*Brand
+}
+
+func NewBrandCatalog(brands []*Brand) *BrandCatalog {
+	return &BrandCatalog{brands: brands}
+}
+
+// Find returns the active brand matching the name, see Brand.Matches.
+func (c *BrandCatalog) Find(name string) (*Brand, bool) {
+	if c == nil {
+		return nil, false
+	}
+	for _, brand := range c.brands {
+		if brand.IsActive && brand.Matches(name) {
+			return brand, true
+		}
+	}
+	return nil, false
+}
+
+// ResolveNames returns the display names of the brands, in the same order.
+//
+// Returns:
+//   - []string: the display names
+//   - error: naming the brands that are not active brands of the catalog
+func (c *BrandCatalog) ResolveNames(names []string) ([]string, error) {
+	resolved := make([]string, 0, len(names))
+	unknown := make([]string, 0)
+	for _, name := range names {
+		brand, ok := c.Find(name)
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		resolved = append(resolved, brand.Name)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("Unknown brands: %s", strings.Join(unknown, ", "))
+	}
+	return resolved, nil
+}
@@ -20,10 +20,17 @@ type Customer struct {
 	State     string    `json:"state" firestore:"state"`
 	Zip       string    `json:"zip" firestore:"zip"`
 	Country   string    `json:"country" firestore:"country"`
+	Latitude  float64   `json:"latitude" firestore:"latitude"`   // Geocoded address from quickbooks, 0 if unknown
+	Longitude float64   `json:"longitude" firestore:"longitude"` // Geocoded address from quickbooks, 0 if unknown
 	CreatedAt time.Time `json:"created_at" firestore:"createdAt"`
 	UpdatedAt time.Time `json:"updated_at" firestore:"updatedAt"`
 }
 
+// HasLocation reports whether the address of the customer has been geocoded.
+func (c *Customer) HasLocation() bool {
+	return c.Latitude != 0 || c.Longitude != 0
+}
+
 // FormatAddress2 returns City, State, Zip
 func (c *Customer) FormatAddress2() string {
 	return fmt.Sprintf("%s, %s %s", c.City, c.State, c.Zip)
@@ -45,6 +52,8 @@ func (c *Customer) ToMap() map[string]any {
 		"state":     c.State,
 		"zip":       c.Zip,
 		"country":   c.Country,
+		"latitude":  c.Latitude,
+		"longitude": c.Longitude,
 		"createdAt": firestore.ServerTimestamp,
 		"updatedAt": firestore.ServerTimestamp,
 	}
@@ -84,6 +93,10 @@ func GetUpdatedCustomerDetails(updated, oldCustomer *Customer) map[string]any {
 	if updated.Country != oldCustomer.Country {
 		changedValues["country"] = updated.Country
 	}
+	if updated.Latitude != oldCustomer.Latitude || updated.Longitude != oldCustomer.Longitude {
+		changedValues["latitude"] = updated.Latitude
+		changedValues["longitude"] = updated.Longitude
+	}
 
 	if len(changedValues) == 0 {
 		return nil
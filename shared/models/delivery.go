@@ -19,6 +19,9 @@ type DeliveryInput struct {
 	DeliveredBy string
 	Signature   multipart.File
 	Images      []multipart.File
+	DriverUID   string       // Firebase uid of the driver submitting the delivery
+	Location    *GeoLocation // Optional GPS position of the device, nil if the driver denied the location permission
+	CapturedAt  time.Time    // Time on the device when the delivery was captured
 }
 
 func (d *DeliveryInput) SetOrderID(orderID string) {
@@ -36,6 +39,15 @@ func (d *DeliveryInput) SetSignature(signature multipart.File) {
 func (d *DeliveryInput) SetImages(images []multipart.File) {
 	d.Images = images
 }
+func (d *DeliveryInput) SetDriverUID(driverUID string) {
+	d.DriverUID = driverUID
+}
+func (d *DeliveryInput) SetLocation(location *GeoLocation) {
+	d.Location = location
+}
+func (d *DeliveryInput) SetCapturedAt(capturedAt time.Time) {
+	d.CapturedAt = capturedAt
+}
 
 func (d *DeliveryInput) Validate() error {
 	if d.OrderID == "" {
@@ -53,6 +65,11 @@ func (d *DeliveryInput) Validate() error {
 	if len(d.Images) == 0 {
 		return errors.New("No images were found when saving delivery. At least one image required. Please retry submission again")
 	}
+	if d.Location != nil {
+		if err := d.Location.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -62,9 +79,27 @@ type Delivery struct {
 	DeliveredBy    string
 	Signature      []byte
 	DeliveryImages [][]byte
+	ImageURLs      []string // Links to the full resolution originals in storage, set once they are uploaded
 	DeliveredAt    time.Time
+	DriverUID      string
+	Location       *GeoLocation     // GPS position of the device, nil if unknown
+	CapturedAt     time.Time        // Time on the device when the delivery was captured
+	Proof          *ProofOfDelivery // Set once the proof of delivery is recorded
 }
 
+// ImageCompression configures how the delivery images are downscaled and encoded before they are embedded in
+// a document.
+type ImageCompression struct {
+	MaxDimension int // Max width and height in pixels, larger images are downscaled keeping their aspect ratio. 0 keeps the size
+	Quality      int // JPEG quality between 1 and 100
+}
+
+var (
+	// Images embedded in the shipping manifest. Large enough for the contact sheet, small enough to keep the
+	// manifest well under the email attachment limits
+	DeliveryThumbnailCompression = ImageCompression{MaxDimension: 800, Quality: 75}
+)
+
 func (d *Delivery) GetDeliveredAtLocalTime() time.Time {
 	localTime, err := utils.ConvertUTCToLocalTimeZoneWithFormat(d.DeliveredAt, d.Order.TimeZone)
 	if err != nil {
@@ -73,28 +108,15 @@ func (d *Delivery) GetDeliveredAtLocalTime() time.Time {
 	return localTime
 }
 
+// GetCorrectlyRotatedImages returns the delivery images at their original size, rotated according to their
+// exif orientation and encoded as PNG. See GetCompressedImages for the images embedded in a document.
 func (d *Delivery) GetCorrectlyRotatedImages() [][]byte {
 	deliveryImages := make([][]byte, 0)
 	for _, imageBytes := range d.DeliveryImages {
-		img, _, err := image.Decode(bytes.NewReader(imageBytes))
+		img, err := getCorrectlyRotatedImage(imageBytes)
 		if err != nil {
 			continue
 		}
-
-		//Get orientation of the image
-		x, err := exif.Decode(bytes.NewReader(imageBytes))
-		orientation := 1 // default
-		if err == nil {
-			tag, err := x.Get(exif.Orientation)
-			if err == nil {
-				o, err := tag.Int(0)
-				if err == nil {
-					orientation = o
-				}
-			}
-		}
-
-		img = utils.FixImageOrientation(img, orientation)
 		img = imaging.Clone(img) // Force 8-bit NRGBA
 		buf := new(bytes.Buffer)
 		err = png.Encode(buf, img)
@@ -108,3 +130,53 @@ func (d *Delivery) GetCorrectlyRotatedImages() [][]byte {
 	}
 	return deliveryImages
 }
+
+// GetCompressedImages rotates the delivery images according to their exif orientation, downscales and encodes
+// them as JPEG.
+//
+// Parameters:
+//   - compression: the max size and the JPEG quality of the images
+//
+// Returns:
+//   - [][]byte: the compressed images. Images that can not be decoded are skipped, the original images are
+//     returned if none can be decoded
+func (d *Delivery) GetCompressedImages(compression ImageCompression) [][]byte {
+	deliveryImages := make([][]byte, 0)
+	for _, imageBytes := range d.DeliveryImages {
+		img, err := getCorrectlyRotatedImage(imageBytes)
+		if err != nil {
+			continue
+		}
+		compressed, err := utils.CompressImage(img, compression.MaxDimension, compression.Quality)
+		if err != nil {
+			continue
+		}
+		deliveryImages = append(deliveryImages, compressed)
+	}
+	if len(deliveryImages) == 0 {
+		return d.DeliveryImages
+	}
+	return deliveryImages
+}
+
+// getCorrectlyRotatedImage decodes the image and rotates it according to its exif orientation.
+func getCorrectlyRotatedImage(imageBytes []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(imageBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	//Get orientation of the image
+	x, err := exif.Decode(bytes.NewReader(imageBytes))
+	orientation := 1 // default
+	if err == nil {
+		tag, err := x.Get(exif.Orientation)
+		if err == nil {
+			o, err := tag.Int(0)
+			if err == nil {
+				orientation = o
+			}
+		}
+	}
+	return utils.FixImageOrientation(img, orientation), nil
+}
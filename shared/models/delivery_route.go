@@ -0,0 +1,219 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/utils"
+)
+
+// DeliverySchedule is the assignment of an order to a driver, a delivery date and a stop of a route. Stored
+// on the order so the orders of a driver or a day can be queried.
+type DeliverySchedule struct {
+	RouteID      string    `json:"routeId" firestore:"routeId"`
+	DriverUID    string    `json:"driverUid" firestore:"driverUid"`
+	DriverName   string    `json:"driverName" firestore:"driverName"`
+	DeliveryDate time.Time `json:"deliveryDate" firestore:"deliveryDate"` // Midnight UTC of the delivery day
+	Stop         int       `json:"stop" firestore:"stop"`                 // Position of the order in the route, starting at 1
+}
+
+// RouteStop is an order delivered on a route.
+type RouteStop struct {
+	Sequence       int     `json:"sequence" firestore:"sequence"` // Starting at 1
+	OrderID        string  `json:"orderId" firestore:"orderId"`
+	DistanceMeters float64 `json:"distanceMeters" firestore:"distanceMeters"` // From the previous stop or the depot, -1 if unknown
+	Order          *Order  `json:"-" firestore:"-"`                           // Loaded with the route, not stored
+}
+
+// DeliveryRoute is the list of orders a driver delivers on a day, in the order of the stops.
+type DeliveryRoute struct {
+	ID           string       `json:"id" firestore:"id"`
+	DriverUID    string       `json:"driverUid" firestore:"driverUid"`
+	DriverName   string       `json:"driverName" firestore:"driverName"`
+	VehicleID    string       `json:"vehicleId" firestore:"vehicleId"`
+	Vehicle      string       `json:"vehicle" firestore:"vehicle"`           // Name of the vehicle, e.g. the plate of the truck
+	DeliveryDate time.Time    `json:"deliveryDate" firestore:"deliveryDate"` // Midnight UTC of the delivery day
+	Depot        *GeoLocation `json:"depot" firestore:"depot"`               // Where the route starts, nil to start at the first stop
+	Stops        []*RouteStop `json:"stops" firestore:"stops"`
+	Status       string       `json:"status" firestore:"status"`
+	LoadWarnings []string     `json:"loadWarnings" firestore:"loadWarnings"` // Warnings of the vehicle load check when the route was planned
+	CreatedAt    time.Time    `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt    time.Time    `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// NewDeliveryRoute plans the route of a driver for a day. The stops are ordered with a nearest neighbour
+// heuristic over the geocoded customer addresses, starting at the depot. Orders whose customer address is
+// not geocoded are delivered last, in the order they were given.
+//
+// Parameters:
+//   - id: id of the route document
+//   - driverUID, driverName: the driver assigned to the route
+//   - vehicle: the vehicle used for the route, its load is checked separately with NewVehicleLoad
+//   - deliveryDate: the day of the deliveries, only the date is kept
+//   - depot: where the route starts, nil to start at the first geocoded stop
+//   - orders: the approved orders to deliver
+//
+// Returns:
+//   - *DeliveryRoute: the planned route, its orders have their Schedule set
+//   - error: if there are no orders or an order is not approved
+func NewDeliveryRoute(id, driverUID, driverName string, vehicle *Vehicle, deliveryDate time.Time, depot *GeoLocation, orders []*Order) (*DeliveryRoute, error) {
+	if len(orders) == 0 {
+		return nil, errors.New("No orders were selected for the delivery route")
+	}
+	for _, order := range orders {
+		if order.Status != constants.OrderStatusApproved {
+			return nil, fmt.Errorf("Order %s is %s, only approved orders can be scheduled for delivery", order.ID, order.Status)
+		}
+	}
+
+	now := time.Now().UTC()
+	route := &DeliveryRoute{
+		ID:           id,
+		DriverUID:    driverUID,
+		DriverName:   driverName,
+		VehicleID:    vehicle.ID,
+		Vehicle:      vehicle.Name,
+		DeliveryDate: time.Date(deliveryDate.Year(), deliveryDate.Month(), deliveryDate.Day(), 0, 0, 0, 0, time.UTC),
+		Depot:        depot,
+		Status:       constants.DeliveryRouteStatusPlanned,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	route.orderStops(orders)
+	for _, stop := range route.Stops {
+		stop.Order.Schedule = &DeliverySchedule{
+			RouteID:      route.ID,
+			DriverUID:    route.DriverUID,
+			DriverName:   route.DriverName,
+			DeliveryDate: route.DeliveryDate,
+			Stop:         stop.Sequence,
+		}
+	}
+	return route, nil
+}
+
+// orderStops sets the stops of the route with the nearest neighbour heuristic: from the depot, or the first
+// geocoded stop without a depot, the next stop is always the closest one not delivered yet.
+func (r *DeliveryRoute) orderStops(orders []*Order) {
+	geocoded := make([]*Order, 0, len(orders))
+	notGeocoded := make([]*Order, 0)
+	for _, order := range orders {
+		if order.Customer != nil && order.Customer.HasLocation() {
+			geocoded = append(geocoded, order)
+		} else {
+			notGeocoded = append(notGeocoded, order)
+		}
+	}
+
+	r.Stops = make([]*RouteStop, 0, len(orders))
+	var latitude, longitude float64
+	hasPosition := r.Depot != nil
+	if hasPosition {
+		latitude, longitude = r.Depot.Latitude, r.Depot.Longitude
+	}
+	for len(geocoded) > 0 {
+		nearest, nearestDistance := 0, -1.0
+		if hasPosition {
+			for i, order := range geocoded {
+				distance := utils.GetDistanceInMeters(latitude, longitude, order.Customer.Latitude, order.Customer.Longitude)
+				if nearestDistance < 0 || distance < nearestDistance {
+					nearest, nearestDistance = i, distance
+				}
+			}
+		}
+		order := geocoded[nearest]
+		r.addStop(order, nearestDistance)
+		latitude, longitude, hasPosition = order.Customer.Latitude, order.Customer.Longitude, true
+		geocoded = append(geocoded[:nearest], geocoded[nearest+1:]...)
+	}
+	for _, order := range notGeocoded {
+		r.addStop(order, -1)
+	}
+}
+
+func (r *DeliveryRoute) addStop(order *Order, distanceMeters float64) {
+	r.Stops = append(r.Stops, &RouteStop{
+		Sequence:       len(r.Stops) + 1,
+		OrderID:        order.ID,
+		DistanceMeters: distanceMeters,
+		Order:          order,
+	})
+}
+
+// GetOrderIDs returns the ids of the orders of the stops, in the order of the stops.
+func (r *DeliveryRoute) GetOrderIDs() []string {
+	orderIDs := make([]string, len(r.Stops))
+	for i, stop := range r.Stops {
+		orderIDs[i] = stop.OrderID
+	}
+	return orderIDs
+}
+
+// GetTotalDistanceMeters returns the distance between the known stops, from the depot when it is set.
+func (r *DeliveryRoute) GetTotalDistanceMeters() float64 {
+	total := 0.0
+	for _, stop := range r.Stops {
+		if stop.DistanceMeters > 0 {
+			total += stop.DistanceMeters
+		}
+	}
+	return total
+}
+
+// GetTotalPacks returns the number of packs loaded on the vehicle. Stops without a loaded order are skipped.
+func (r *DeliveryRoute) GetTotalPacks() int {
+	total := 0
+	for _, stop := range r.Stops {
+		if stop.Order == nil {
+			continue
+		}
+		for _, item := range stop.Order.Items {
+			total += item.Quantity
+		}
+	}
+	return total
+}
+
+// GetOrders returns the loaded orders of the stops. Stops without a loaded order are skipped.
+func (r *DeliveryRoute) GetOrders() []*Order {
+	orders := make([]*Order, 0, len(r.Stops))
+	for _, stop := range r.Stops {
+		if stop.Order != nil {
+			orders = append(orders, stop.Order)
+		}
+	}
+	return orders
+}
+
+// GetHazardousWeightInPounds returns the weight of the hazardous items loaded on the vehicle in pounds.
+func (r *DeliveryRoute) GetHazardousWeightInPounds() float64 {
+	total := 0.0
+	for _, order := range r.GetOrders() {
+		total += order.GetNetHazardousWeightInPounds()
+	}
+	return total
+}
+
+// GetHazardousWeight returns the weight of the hazardous items loaded on the vehicle in gallons.
+func (r *DeliveryRoute) GetHazardousWeight() float64 {
+	total := 0.0
+	for _, stop := range r.Stops {
+		if stop.Order != nil {
+			total += stop.Order.GetNetHazardousWeight()
+		}
+	}
+	return total
+}
+
+// GetNonHazardousWeight returns the weight of the non hazardous items loaded on the vehicle in gallons.
+func (r *DeliveryRoute) GetNonHazardousWeight() float64 {
+	total := 0.0
+	for _, stop := range r.Stops {
+		if stop.Order != nil {
+			total += stop.Order.GetNetNonHazardousWeight()
+		}
+	}
+	return total
+}
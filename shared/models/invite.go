@@ -0,0 +1,163 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/utils"
+)
+
+// Invite is an invitation of a new user stored in firestore collection ('invites'). The firebase user is
+// created without a password when the user is invited, the user sets it from the emailed link and the
+// account is created from the invite when they first sign in.
+type Invite struct {
+	ID        string   `json:"id" firestore:"id"`
+	UID       string   `json:"uid" firestore:"uid"` // Firebase uid of the invited user
+	Name      string   `json:"name" firestore:"name"`
+	Email     string   `json:"email" firestore:"email"`
+	Customers []string `json:"customers" firestore:"customers"`
+	Brands    []string `json:"brands" firestore:"brands"`
+	Role      string   `json:"role" firestore:"role"`
+
+	GrantedPermissions []string `json:"grantedPermissions" firestore:"grantedPermissions"`
+	RevokedPermissions []string `json:"revokedPermissions" firestore:"revokedPermissions"`
+
+	Status     string    `json:"status" firestore:"status"`         // See the InviteStatus constants
+	InvitedBy  string    `json:"invitedBy" firestore:"invitedBy"`   // Uid of the super admin who sent the invite
+	SentCount  int       `json:"sentCount" firestore:"sentCount"`   // Number of times the invite was emailed
+	ExpiresAt  time.Time `json:"expiresAt" firestore:"expiresAt"`   // Moved forward when the invite is resent
+	AcceptedAt time.Time `json:"acceptedAt" firestore:"acceptedAt"` // Zero until accepted
+	CreatedAt  time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// Validate validates the invite details sent by the super admin.
+func (i *Invite) Validate() error {
+	if strings.TrimSpace(i.Name) == "" {
+		return errors.New("Name of the user cannot be empty")
+	}
+	if strings.TrimSpace(i.Email) == "" {
+		return errors.New("Email of the user cannot be empty")
+	}
+	if err := validateUserAssignments(i.Customers, i.Brands, i.Role); err != nil {
+		return err
+	}
+	return ValidatePermissionOverrides(i.GrantedPermissions, i.RevokedPermissions)
+}
+
+// ValidateBrands checks the brands of the invite are active brands of the catalog and replaces them with
+// their display names, see UserAccountCreate.ValidateBrands.
+func (i *Invite) ValidateBrands(catalog *BrandCatalog) error {
+	brands, err := resolveUserBrands(catalog, i.Brands)
+	if err != nil {
+		return err
+	}
+	i.Brands = brands
+	return nil
+}
+
+// SetPending marks the invite as pending from now, it is called when the invite is created and when it is
+// resent so the previous links stop working.
+func (i *Invite) SetPending(now time.Time) {
+	if i.CreatedAt.IsZero() {
+		i.CreatedAt = now
+	}
+	i.Status = constants.InviteStatusPending
+	i.ExpiresAt = now.Add(constants.InviteExpiry)
+	i.UpdatedAt = now
+	i.SentCount++
+}
+
+func (i *Invite) IsExpired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
+
+// CanResend returns an error if the invite was already accepted or revoked.
+func (i *Invite) CanResend() error {
+	switch i.Status {
+	case constants.InviteStatusAccepted:
+		return errors.New("Invite was already accepted")
+	case constants.InviteStatusRevoked:
+		return errors.New("Invite was revoked, please send a new invite")
+	}
+	return nil
+}
+
+// CanRevoke returns an error if the invite is not pending, an accepted invite is an account to deactivate.
+func (i *Invite) CanRevoke() error {
+	if i.Status != constants.InviteStatusPending {
+		return fmt.Errorf("Only a pending invite can be revoked, the invite is %s", strings.ToLower(i.Status))
+	}
+	return nil
+}
+
+// CanAccept returns an error if the invite cannot be accepted by the signed in user.
+func (i *Invite) CanAccept(uid string, now time.Time) error {
+	if i.UID != uid {
+		return errors.New("Invite was sent to another user")
+	}
+	if err := i.CanRevoke(); err != nil {
+		return errors.New("Invite is no longer valid, please ask for a new invite")
+	}
+	if i.IsExpired(now) {
+		return errors.New("Invite has expired, please ask for the invite to be resent")
+	}
+	return nil
+}
+
+// GetToken returns the signed token of the invite, sent in the link of the invite email. The token is bound
+// to the expiry of the invite so resending the invite replaces it.
+func (i *Invite) GetToken(secret []byte) string {
+	return utils.SignToken(fmt.Sprintf("%s.%d", i.ID, i.ExpiresAt.Unix()), secret)
+}
+
+// ParseInviteToken returns the invite id of a token created by Invite.GetToken.
+//
+// Parameters:
+//   - token: the token sent back by the user
+//   - secret: the secret the token was signed with
+//   - now: the current time
+//
+// Returns:
+//   - string: the id of the invite
+//   - time.Time: the expiry the token was issued for, see Invite.MatchesToken
+//   - error: if the token is not valid or has expired
+func ParseInviteToken(token string, secret []byte, now time.Time) (string, time.Time, error) {
+	payload, err := utils.VerifySignedToken(token, secret)
+	if err != nil {
+		return "", time.Time{}, errors.New("Invite link is not valid")
+	}
+	inviteID, expiresAtStr, ok := strings.Cut(payload, ".")
+	expiresAtUnix, err := strconv.ParseInt(expiresAtStr, 10, 64)
+	if !ok || inviteID == "" || err != nil {
+		return "", time.Time{}, errors.New("Invite link is not valid")
+	}
+	expiresAt := time.Unix(expiresAtUnix, 0)
+	if !now.Before(expiresAt) {
+		return "", time.Time{}, errors.New("Invite has expired, please ask for the invite to be resent")
+	}
+	return inviteID, expiresAt, nil
+}
+
+// MatchesToken reports whether the token was issued for the current expiry of the invite, the tokens of the
+// previous emails no longer match once the invite is resent.
+func (i *Invite) MatchesToken(expiresAt time.Time) bool {
+	return i.ExpiresAt.Unix() == expiresAt.Unix()
+}
+
+// ToUserAccount returns the account created when the invite is accepted.
+func (i *Invite) ToUserAccount() *UserAccount {
+	return &UserAccount{
+		Name:               i.Name,
+		Email:              i.Email,
+		Customers:          i.Customers,
+		Brands:             i.Brands,
+		Role:               i.Role,
+		GrantedPermissions: i.GrantedPermissions,
+		RevokedPermissions: i.RevokedPermissions,
+	}
+}
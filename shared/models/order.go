@@ -11,19 +11,22 @@ import (
 )
 
 type Order struct {
-	ID                  string     `json:"id"`
-	Customer            *Customer  `json:"customer" firestore:"customer"`
-	Uid                 string     `json:"uid" firestore:"uid"` // User ID of placed the order
-	SpecialInstructions string     `json:"specialInstructions" firestore:"specialInstructions"`
-	Items               []*Product `json:"items" firestore:"items"`
-	TaxRate             float64    `json:"taxRate" firestore:"taxRate"`
-	TaxAmount           float64    `json:"taxAmount" firestore:"taxAmount"`
-	SubTotal            float64    `json:"subTotal" firestore:"subTotal"`
-	Total               float64    `json:"total" firestore:"total"`
-	Status              string     `json:"status" firestore:"status"`
-	CreatedAt           time.Time  `json:"createdAt" firestore:"createdAt"`
-	UpdatedAt           time.Time  `json:"updatedAt" firestore:"updatedAt"`
-	TimeZone            string     `json:"timeZone" firestore:"timeZone"`
+	ID                  string            `json:"id"`
+	Customer            *Customer         `json:"customer" firestore:"customer"`
+	Uid                 string            `json:"uid" firestore:"uid"` // User ID of placed the order
+	SpecialInstructions string            `json:"specialInstructions" firestore:"specialInstructions"`
+	Items               []*Product        `json:"items" firestore:"items"`
+	TaxRate             float64           `json:"taxRate" firestore:"taxRate"`
+	TaxAmount           float64           `json:"taxAmount" firestore:"taxAmount"`
+	SubTotal            float64           `json:"subTotal" firestore:"subTotal"`
+	Total               float64           `json:"total" firestore:"total"`
+	Status              string            `json:"status" firestore:"status"`
+	CreatedAt           time.Time         `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt           time.Time         `json:"updatedAt" firestore:"updatedAt"`
+	TimeZone            string            `json:"timeZone" firestore:"timeZone"`
+	QBInvoiceID         string            `json:"qbInvoiceId" firestore:"qbInvoiceId"` // Set once the quickbooks invoice of the order is created
+	Paid                bool              `json:"paid" firestore:"paid"`               // Set once the quickbooks invoice of the order is fully paid
+	Schedule            *DeliverySchedule `json:"schedule" firestore:"schedule"`       // Set once the order is assigned to a delivery route
 }
 
 // Small struct to fetch order if only order id is passed form frontend
@@ -69,10 +72,23 @@ func (o *Order) SetTaxRate(taxRate float64) {
 	o.TaxRate = taxRate
 }
 
-func (o *Order) SetItemPrices(correctPrices map[string]float64) {
+// SetItemPrices prices every item of the order from the customer's price list and records the rule that
+// priced each item. Items are priced at the order creation time, or now if the order is not created yet.
+//
+// Returns an error if an item is not in the price list.
+func (o *Order) SetItemPrices(priceList *PriceList) error {
+	at := o.CreatedAt
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
 	for i, item := range o.Items {
-		o.Items[i].SetPrice(correctPrices[item.ID])
+		resolvedPrice, ok := priceList.Resolve(item.ID, item.Quantity, at)
+		if !ok {
+			return fmt.Errorf("No price found for product %s", item.ID)
+		}
+		o.Items[i].SetResolvedPrice(resolvedPrice)
 	}
+	return nil
 }
 
 func (o *Order) setCreatedAt(createdAt time.Time) {
@@ -134,32 +150,79 @@ func (o *Order) GetFormattedTotalItems() string {
 	return strconv.Itoa(totalUnits)
 }
 
-func (o *Order) GetFormattedNetWeight() string {
-	weight := 0.0
+// GetFormattedTotalUnits returns the number of single units in all the ordered packs
+func (o *Order) GetFormattedTotalUnits() string {
+	totalUnits := 0
 	for _, item := range o.Items {
-		weight += item.GetCorrectWeightInGallons()
+		totalUnits += item.GetTotalUnits()
 	}
-	return fmt.Sprintf("%.2f gal", weight)
+	return strconv.Itoa(totalUnits)
 }
 
-func (o *Order) GetFormattedNetNonHazardousWeight() string {
-	weight := 0.0
+// GetNetWeight returns the weight of all the items in gallons. Items with an unknown unit are skipped, see
+// ValidateUnits
+func (o *Order) GetNetWeight() float64 {
+	return o.sumItems((*Product).GetVolumeInGallons, func(*Product) bool { return true })
+}
+
+// GetNetHazardousWeight returns the weight of the hazardous items in gallons
+func (o *Order) GetNetHazardousWeight() float64 {
+	return o.sumItems((*Product).GetVolumeInGallons, func(item *Product) bool { return item.Hazardous })
+}
+
+// GetNetNonHazardousWeight returns the weight of the non hazardous items in gallons
+func (o *Order) GetNetNonHazardousWeight() float64 {
+	return o.sumItems((*Product).GetVolumeInGallons, func(item *Product) bool { return !item.Hazardous })
+}
+
+// GetNetWeightInPounds returns the weight of all the items in pounds
+func (o *Order) GetNetWeightInPounds() float64 {
+	return o.sumItems((*Product).GetWeightInPounds, func(*Product) bool { return true })
+}
+
+// GetNetHazardousWeightInPounds returns the weight of the hazardous items in pounds
+func (o *Order) GetNetHazardousWeightInPounds() float64 {
+	return o.sumItems((*Product).GetWeightInPounds, func(item *Product) bool { return item.Hazardous })
+}
+
+func (o *Order) sumItems(convert func(*Product) (float64, error), include func(*Product) bool) float64 {
+	total := 0.0
 	for _, item := range o.Items {
-		if !item.Hazardous {
-			weight += item.GetCorrectWeightInGallons()
+		if !include(item) {
+			continue
+		}
+		if value, err := convert(item); err == nil {
+			total += value
 		}
 	}
-	return fmt.Sprintf("%.2f gal", weight)
+	return total
 }
 
-func (o *Order) GetFormattedNetHazardousWeight() string {
-	weight := 0.0
+// ValidateUnits returns an error naming the items whose size unit is not known, they are left out of the
+// weights of the order.
+func (o *Order) ValidateUnits() error {
+	invalid := make([]string, 0)
 	for _, item := range o.Items {
-		if item.Hazardous {
-			weight += item.GetCorrectWeightInGallons()
+		if _, err := item.GetSizeUnit(); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s (%q)", item.SKU, item.SizeUnit))
 		}
 	}
-	return fmt.Sprintf("%.2f gal", weight)
+	if len(invalid) > 0 {
+		return fmt.Errorf("Order %s has items with an unknown unit of measure: %s", o.ID, strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+func (o *Order) GetFormattedNetWeight() string {
+	return fmt.Sprintf("%.2f gal", o.GetNetWeight())
+}
+
+func (o *Order) GetFormattedNetNonHazardousWeight() string {
+	return fmt.Sprintf("%.2f gal", o.GetNetNonHazardousWeight())
+}
+
+func (o *Order) GetFormattedNetHazardousWeight() string {
+	return fmt.Sprintf("%.2f gal", o.GetNetHazardousWeight())
 }
 
 func (o *Order) GetFormattedCOG() string {
@@ -246,6 +309,7 @@ func (o *Order) ToCompleteOrderItemsFromMinimal(products map[string]*Product) {
 		o.Items[i].SetSizeUnit(products[item.ID].SizeUnit)
 		o.Items[i].SetPackOf(products[item.ID].PackOf)
 		o.Items[i].SetHazardous(products[item.ID].Hazardous)
+		o.Items[i].SetDensity(products[item.ID].Density)
 		o.Items[i].SetCategory(products[item.ID].Category)
 		o.Items[i].SetDesc(products[item.ID].Desc)
 		o.Items[i].SetSlug(products[item.ID].Slug)
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// PriceChange is a single entry of the price history subcollection ('price_history'). It is stored under
+// the product document for list price changes (empty CustomerID) and under the products prices per customer
+// document for customer price changes.
+type PriceChange struct {
+	ProductID   string    `json:"productId" firestore:"productId"`
+	ProductName string    `json:"productName" firestore:"productName"`
+	CustomerID  string    `json:"customerId" firestore:"customerId"`
+	OldPrice    float64   `json:"oldPrice" firestore:"oldPrice"`
+	NewPrice    float64   `json:"newPrice" firestore:"newPrice"`
+	RuleID      string    `json:"ruleId" firestore:"ruleId"` // Rule that priced the new price, empty for list price
+	Source      string    `json:"source" firestore:"source"` // constants.PriceChangeSourceQuickBooks or constants.PriceChangeSourceManual
+	ChangedAt   time.Time `json:"changedAt" firestore:"changedAt"`
+}
+
+func NewPriceChange(product *Product, customerID string, oldPrice, newPrice float64, source string) *PriceChange {
+	return &PriceChange{
+		ProductID:   product.ID,
+		ProductName: product.Name,
+		CustomerID:  customerID,
+		OldPrice:    oldPrice,
+		NewPrice:    newPrice,
+		Source:      source,
+	}
+}
+
+func (pc *PriceChange) SetRuleID(ruleID string) {
+	pc.RuleID = ruleID
+}
+
+func (pc *PriceChange) ToMap() map[string]any {
+	return map[string]any{
+		"productId":   pc.ProductID,
+		"productName": pc.ProductName,
+		"customerId":  pc.CustomerID,
+		"oldPrice":    pc.OldPrice,
+		"newPrice":    pc.NewPrice,
+		"ruleId":      pc.RuleID,
+		"source":      pc.Source,
+		"changedAt":   firestore.ServerTimestamp,
+	}
+}
+
+func (pc *PriceChange) GetFormattedOldPrice() string {
+	return fmt.Sprintf("$%.2f", pc.OldPrice)
+}
+
+func (pc *PriceChange) GetFormattedNewPrice() string {
+	return fmt.Sprintf("$%.2f", pc.NewPrice)
+}
+
+// GetProductPriceChange returns the list price change between the old and the new product, nil if the
+// price did not change. GetUpdatedProductDetails only returns the new values so this is used to keep the
+// old price in the price history.
+func GetProductPriceChange(newProduct, oldProduct *Product, source string) *PriceChange {
+	if newProduct == nil || oldProduct == nil || newProduct.Price == oldProduct.Price {
+		return nil
+	}
+	return NewPriceChange(newProduct, "", oldProduct.Price, newProduct.Price, source)
+}
@@ -0,0 +1,125 @@
+package models
+
+// PriceRecomputeOptions limits which per customer prices are recomputed. Only the prices of the changed
+// products or customers need to be recomputed, an empty list means all of them.
+type PriceRecomputeOptions struct {
+	ProductIDs  []string
+	CustomerIDs []string
+	Source      string       // Source recorded in the price history, see constants.PriceChangeSource*
+	DryRun      bool         // Only report what would change without writing to firestore
+	Rules       []*PriceRule // Rules not stored yet, e.g. the rule of a dry run, they replace the stored rules with the same id
+}
+
+// GetRules returns the stored rules with the rules of the options added, a stored rule is replaced by the rule
+// of the options with the same id.
+func (o *PriceRecomputeOptions) GetRules(stored []*PriceRule) []*PriceRule {
+	if len(o.Rules) == 0 {
+		return stored
+	}
+	replaced := make(map[string]bool)
+	for _, rule := range o.Rules {
+		if rule.ID != "" {
+			replaced[rule.ID] = true
+		}
+	}
+	rules := make([]*PriceRule, 0, len(stored)+len(o.Rules))
+	for _, rule := range stored {
+		if !replaced[rule.ID] {
+			rules = append(rules, rule)
+		}
+	}
+	return append(rules, o.Rules...)
+}
+
+// IsFullRecompute returns true if every product of every customer is recomputed.
+func (o *PriceRecomputeOptions) IsFullRecompute() bool {
+	return len(o.ProductIDs) == 0 && len(o.CustomerIDs) == 0
+}
+
+// PriceRecomputeReport summarizes a recompute of the per customer prices. In dry run mode it reports what
+// would change.
+type PriceRecomputeReport struct {
+	DryRun    bool           `json:"dryRun"`
+	Customers int            `json:"customers"` // Number of customers recomputed
+	Created   int            `json:"created"`   // New per customer prices
+	Updated   int            `json:"updated"`   // Prices whose price or rule changed
+	Unchanged int            `json:"unchanged"`
+	Changes   []*PriceChange `json:"changes"` // Price changes of the updated prices
+}
+
+func NewPriceRecomputeReport(dryRun bool) *PriceRecomputeReport {
+	return &PriceRecomputeReport{
+		DryRun:  dryRun,
+		Changes: make([]*PriceChange, 0),
+	}
+}
+
+// AddPrice counts the recomputed price of a product in the report.
+//
+// Parameters:
+//   - product: the recomputed product
+//   - customerID: the customer of the price
+//   - original: the stored price, nil if the customer has no price for the product yet
+//   - resolved: the recomputed price, see PriceList.Resolve
+//   - source: source of the change, see constants.PriceChangeSource*
+//
+// Returns:
+//   - *PriceChange: the change to record in the price history, nil if the price did not change
+//   - bool: true if the price must be written
+func (r *PriceRecomputeReport) AddPrice(product *Product, customerID string, original *ProductPricePerCustomer, resolved *ResolvedPrice, source string) (*PriceChange, bool) {
+	switch {
+	case original == nil:
+		r.Created++
+		return nil, true
+	case original.Price == resolved.Price && original.RuleID == resolved.RuleID:
+		r.Unchanged++
+		return nil, false
+	}
+	r.Updated++
+	if original.Price == resolved.Price {
+		return nil, true
+	}
+	priceChange := NewPriceChange(product, customerID, original.Price, resolved.Price, source)
+	priceChange.SetRuleID(resolved.RuleID)
+	r.Changes = append(r.Changes, priceChange)
+	return priceChange, true
+}
+
+// NewPriceRecomputeOptionsForRules returns the options to recompute the prices affected by the given rules,
+// e.g. the old and the new version of an updated rule. A rule for all customers recomputes all customers and a
+// brand or category discount recomputes all products.
+func NewPriceRecomputeOptionsForRules(source string, rules ...*PriceRule) *PriceRecomputeOptions {
+	options := &PriceRecomputeOptions{Source: source}
+	allCustomers, allProducts := false, false
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		if rule.CustomerID == "" {
+			allCustomers = true
+		} else {
+			options.CustomerIDs = appendUnique(options.CustomerIDs, rule.CustomerID)
+		}
+		if rule.ProductID == "" {
+			allProducts = true
+		} else {
+			options.ProductIDs = appendUnique(options.ProductIDs, rule.ProductID)
+		}
+	}
+	if allCustomers {
+		options.CustomerIDs = nil
+	}
+	if allProducts {
+		options.ProductIDs = nil
+	}
+	return options
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
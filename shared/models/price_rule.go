@@ -0,0 +1,310 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+)
+
+// PriceRule is a single pricing rule stored in firestore collection ('price_rules').
+//
+// Supported rule types:
+//   - CONTRACT: fixed Price for ProductID
+//   - BRAND_DISCOUNT: DiscountPercent off the list price of every product of Brand
+//   - CATEGORY_DISCOUNT: DiscountPercent off the list price of every product of Category
+//
+// An empty CustomerID applies the rule to all customers. Tiers are optional quantity breaks, the tier
+// with the highest MinQuantity not above the ordered quantity overrides the base Price/DiscountPercent.
+// A zero EffectiveFrom or EffectiveTo leaves that side of the date range open.
+type PriceRule struct {
+	ID              string      `json:"id" firestore:"id"`
+	Type            string      `json:"type" firestore:"type"`
+	CustomerID      string      `json:"customerId" firestore:"customerId"`
+	ProductID       string      `json:"productId" firestore:"productId"`
+	Brand           string      `json:"brand" firestore:"brand"`
+	Category        string      `json:"category" firestore:"category"`
+	Price           float64     `json:"price" firestore:"price"`
+	DiscountPercent float64     `json:"discountPercent" firestore:"discountPercent"`
+	Tiers           []PriceTier `json:"tiers" firestore:"tiers"`
+	Priority        int         `json:"priority" firestore:"priority"` // Higher priority wins over specificity
+	EffectiveFrom   time.Time   `json:"effectiveFrom" firestore:"effectiveFrom"`
+	EffectiveTo     time.Time   `json:"effectiveTo" firestore:"effectiveTo"`
+	IsActive        bool        `json:"isActive" firestore:"isActive"`
+	CreatedAt       time.Time   `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt       time.Time   `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// PriceTier is a quantity break of a price rule. Only one of Price or DiscountPercent is used depending
+// on the type of the rule.
+type PriceTier struct {
+	MinQuantity     int     `json:"minQuantity" firestore:"minQuantity"`
+	Price           float64 `json:"price" firestore:"price"`
+	DiscountPercent float64 `json:"discountPercent" firestore:"discountPercent"`
+}
+
+// ResolvedPrice is the price of a product for a customer and the rule that priced it.
+type ResolvedPrice struct {
+	ProductID string  `json:"productId"`
+	Price     float64 `json:"price"`
+	RuleID    string  `json:"ruleId"`   // Empty when the list price is used
+	RuleType  string  `json:"ruleType"` // constants.PriceRuleTypeList when no rule matched
+}
+
+func (r *PriceRule) ToMap() map[string]any {
+	tiers := make([]map[string]any, len(r.Tiers))
+	for i, tier := range r.Tiers {
+		tiers[i] = map[string]any{
+			"minQuantity":     tier.MinQuantity,
+			"price":           tier.Price,
+			"discountPercent": tier.DiscountPercent,
+		}
+	}
+	return map[string]any{
+		"id":              r.ID,
+		"type":            r.Type,
+		"customerId":      r.CustomerID,
+		"productId":       r.ProductID,
+		"brand":           r.Brand,
+		"category":        r.Category,
+		"price":           r.Price,
+		"discountPercent": r.DiscountPercent,
+		"tiers":           tiers,
+		"priority":        r.Priority,
+		"effectiveFrom":   r.EffectiveFrom,
+		"effectiveTo":     r.EffectiveTo,
+		"isActive":        r.IsActive,
+		"createdAt":       firestore.ServerTimestamp,
+		"updatedAt":       firestore.ServerTimestamp,
+	}
+}
+
+// WithDetails returns a copy of the rule with the fields to update applied, used to check the updated rule
+// before it is written.
+//
+// Parameters:
+//   - details: fields to update keyed like the firestore fields of the rule
+//
+// Returns:
+//   - *PriceRule: the updated copy, the rule itself is not changed
+//   - error: if a field does not have the type of the rule field
+func (r *PriceRule) WithDetails(details map[string]any) (*PriceRule, error) {
+	//The json and firestore keys of the rule are the same
+	merged := make(map[string]any)
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	for key, value := range details {
+		merged[key] = value
+	}
+	if data, err = json.Marshal(merged); err != nil {
+		return nil, err
+	}
+	var updated PriceRule
+	if err := json.Unmarshal(data, &updated); err != nil {
+		return nil, errors.New("Invalid value for a price rule field")
+	}
+	return &updated, nil
+}
+
+// Validate checks that the rule has the fields required by its type.
+func (r *PriceRule) Validate() error {
+	switch r.Type {
+	case constants.PriceRuleTypeContract:
+		if r.ProductID == "" || r.CustomerID == "" {
+			return errors.New("A contract price requires a product and a customer")
+		}
+		//Without a base price a single unit is priced by a tier starting at 1
+		if r.Price < 0 || (r.Price == 0 && !r.hasTierFrom(1)) {
+			return errors.New("A contract price must be greater than 0")
+		}
+	case constants.PriceRuleTypeBrandDiscount:
+		if r.Brand == "" {
+			return errors.New("A brand discount requires a brand")
+		}
+	case constants.PriceRuleTypeCategoryDiscount:
+		if r.Category == "" {
+			return errors.New("A category discount requires a category")
+		}
+	default:
+		return errors.New("Invalid price rule type")
+	}
+	if r.DiscountPercent < 0 || r.DiscountPercent > 100 {
+		return errors.New("Discount must be between 0 and 100 percent")
+	}
+	for _, tier := range r.Tiers {
+		if tier.MinQuantity < 1 {
+			return errors.New("Tier minimum quantity must be at least 1")
+		}
+		if r.Type == constants.PriceRuleTypeContract && tier.Price <= 0 {
+			return errors.New("Tier price must be greater than 0")
+		}
+		if r.Type != constants.PriceRuleTypeContract && (tier.DiscountPercent <= 0 || tier.DiscountPercent > 100) {
+			return errors.New("Tier discount must be between 0 and 100 percent")
+		}
+	}
+	if !r.EffectiveFrom.IsZero() && !r.EffectiveTo.IsZero() && !r.EffectiveTo.After(r.EffectiveFrom) {
+		return errors.New("Effective to date must be after the effective from date")
+	}
+	return nil
+}
+
+// IsEffective returns true if the rule is active at the given time. EffectiveTo is exclusive.
+func (r *PriceRule) IsEffective(at time.Time) bool {
+	if !r.IsActive {
+		return false
+	}
+	if !r.EffectiveFrom.IsZero() && at.Before(r.EffectiveFrom) {
+		return false
+	}
+	if !r.EffectiveTo.IsZero() && !at.Before(r.EffectiveTo) {
+		return false
+	}
+	return true
+}
+
+// Matches returns true if the rule applies to the product for the customer.
+func (r *PriceRule) Matches(product *Product, customerID string) bool {
+	if r.CustomerID != "" && r.CustomerID != customerID {
+		return false
+	}
+	switch r.Type {
+	case constants.PriceRuleTypeContract:
+		return r.ProductID == product.ID
+	case constants.PriceRuleTypeBrandDiscount:
+		return r.Brand == product.Brand
+	case constants.PriceRuleTypeCategoryDiscount:
+		return r.Category == product.Category
+	default:
+		return false
+	}
+}
+
+// GetPrice returns the price of the product for the quantity under this rule. The quantity decides the tier.
+func (r *PriceRule) GetPrice(product *Product, quantity int) float64 {
+	price, discount := r.Price, r.DiscountPercent
+	if tier := r.getTier(quantity); tier != nil {
+		price, discount = tier.Price, tier.DiscountPercent
+	}
+	if r.Type == constants.PriceRuleTypeContract {
+		return price
+	}
+	return roundToCents(product.Price * (1 - discount/100))
+}
+
+// hasTierFrom returns true if a tier starts at the quantity.
+func (r *PriceRule) hasTierFrom(quantity int) bool {
+	for _, tier := range r.Tiers {
+		if tier.MinQuantity == quantity {
+			return true
+		}
+	}
+	return false
+}
+
+// getTier returns the tier with the highest minimum quantity that the quantity reaches, nil if none.
+func (r *PriceRule) getTier(quantity int) *PriceTier {
+	var best *PriceTier
+	for i, tier := range r.Tiers {
+		if quantity >= tier.MinQuantity && (best == nil || tier.MinQuantity > best.MinQuantity) {
+			best = &r.Tiers[i]
+		}
+	}
+	return best
+}
+
+// getSpecificity ranks the rules when their priority is equal. Contract prices beat category discounts,
+// category discounts beat brand discounts, and a rule for the customer beats a rule for all customers.
+func (r *PriceRule) getSpecificity() int {
+	specificity := 0
+	switch r.Type {
+	case constants.PriceRuleTypeContract:
+		specificity = 30
+	case constants.PriceRuleTypeCategoryDiscount:
+		specificity = 20
+	case constants.PriceRuleTypeBrandDiscount:
+		specificity = 10
+	}
+	if r.CustomerID != "" {
+		specificity++
+	}
+	return specificity
+}
+
+// PriceList contains everything needed to price products for a single customer.
+type PriceList struct {
+	CustomerID string
+	Products   map[string]*Product // Keyed by product id, holds the list prices
+	Rules      []*PriceRule
+}
+
+func NewPriceList(customerID string, products []*Product, rules []*PriceRule) *PriceList {
+	productMap := make(map[string]*Product, len(products))
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+	return &PriceList{
+		CustomerID: customerID,
+		Products:   productMap,
+		Rules:      rules,
+	}
+}
+
+// Resolve returns the price of a product for the quantity at the given time.
+//
+// The winning rule is picked deterministically from the effective and matching rules by:
+//  1. higher Priority
+//  2. higher specificity (see getSpecificity)
+//  3. lower price
+//  4. lower rule ID
+//
+// The list price of the product is used when no rule matches. Returns false if the product is not
+// in the price list.
+func (pl *PriceList) Resolve(productID string, quantity int, at time.Time) (*ResolvedPrice, bool) {
+	product, ok := pl.Products[productID]
+	if !ok {
+		return nil, false
+	}
+
+	type candidate struct {
+		rule  *PriceRule
+		price float64
+	}
+	candidates := make([]candidate, 0)
+	for _, rule := range pl.Rules {
+		if rule.IsEffective(at) && rule.Matches(product, pl.CustomerID) {
+			candidates = append(candidates, candidate{rule: rule, price: rule.GetPrice(product, quantity)})
+		}
+	}
+	if len(candidates) == 0 {
+		return &ResolvedPrice{ProductID: productID, Price: product.Price, RuleType: constants.PriceRuleTypeList}, true
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.rule.Priority != b.rule.Priority {
+			return a.rule.Priority > b.rule.Priority
+		}
+		if a.rule.getSpecificity() != b.rule.getSpecificity() {
+			return a.rule.getSpecificity() > b.rule.getSpecificity()
+		}
+		if a.price != b.price {
+			return a.price < b.price
+		}
+		return a.rule.ID < b.rule.ID
+	})
+	winner := candidates[0]
+	return &ResolvedPrice{ProductID: productID, Price: winner.price, RuleID: winner.rule.ID, RuleType: winner.rule.Type}, true
+}
+
+func roundToCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
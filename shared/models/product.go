@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/units"
 )
 
 type Product struct {
@@ -18,6 +20,7 @@ type Product struct {
 	SizeUnit      string    `json:"sizeUnit" firestore:"sizeUnit"`
 	PackOf        int       `json:"packOf" firestore:"packOf"`
 	Hazardous     bool      `json:"hazardous" firestore:"hazardous"`
+	Density       float64   `json:"density" firestore:"density"` // Pounds per gallon, 0 for the density of water
 	Category      string    `json:"category" firestore:"category"`
 	Price         float64   `json:"price" firestore:"price"`
 	PurchasePrice float64   `json:"purchasePrice" firestore:"purchasePrice"`
@@ -25,6 +28,8 @@ type Product struct {
 	Slug          string    `json:"slug" firestore:"slug"`
 	NameKey       string    `json:"nameKey" firestore:"nameKey"`
 	Quantity      int       `json:"quantity" firestore:"quantity"`
+	PriceRuleID   string    `json:"priceRuleId" firestore:"priceRuleId"`     // Rule that priced the order item, empty for list price
+	PriceRuleType string    `json:"priceRuleType" firestore:"priceRuleType"` // Type of the rule that priced the order item
 	CreatedAt     time.Time `json:"created_at" firestore:"createdAt"`
 	UpdatedAt     time.Time `json:"updated_at" firestore:"updatedAt"`
 }
@@ -40,6 +45,7 @@ func (p *Product) ToMap() map[string]any {
 		"sizeUnit":      p.SizeUnit,
 		"packOf":        p.PackOf,
 		"hazardous":     p.Hazardous,
+		"density":       p.Density,
 		"category":      p.Category,
 		"price":         p.Price,
 		"purchasePrice": p.PurchasePrice,
@@ -58,9 +64,11 @@ func (p *Product) ToMap() map[string]any {
 // big writes to one document
 func (p *Product) ToMinimalMap() map[string]any {
 	return map[string]any{
-		"id":       p.ID,
-		"quantity": p.Quantity,
-		"price":    p.Price,
+		"id":            p.ID,
+		"quantity":      p.Quantity,
+		"price":         p.Price,
+		"priceRuleId":   p.PriceRuleID,
+		"priceRuleType": p.PriceRuleType,
 	}
 }
 
@@ -102,6 +110,10 @@ func (p *Product) SetHazardous(hazardous bool) {
 	p.Hazardous = hazardous
 }
 
+func (p *Product) SetDensity(density float64) {
+	p.Density = density
+}
+
 func (p *Product) SetCategory(category string) {
 	p.Category = category
 }
@@ -110,6 +122,13 @@ func (p *Product) SetPrice(price float64) {
 	p.Price = price
 }
 
+// SetResolvedPrice sets the price of the product and the rule that priced it.
+func (p *Product) SetResolvedPrice(resolvedPrice *ResolvedPrice) {
+	p.Price = resolvedPrice.Price
+	p.PriceRuleID = resolvedPrice.RuleID
+	p.PriceRuleType = resolvedPrice.RuleType
+}
+
 func (p *Product) SetPurchasePrice(purchasePrice float64) {
 	p.PurchasePrice = purchasePrice
 }
@@ -144,6 +163,12 @@ func (p *Product) GetTotalPrice() float64 {
 	return p.Price * float64(p.Quantity)
 }
 
+// GetTotalUnits returns the number of single units in the ordered packs. A missing pack size counts as a
+// pack of 1.
+func (p *Product) GetTotalUnits() int {
+	return p.Quantity * max(p.PackOf, 1)
+}
+
 func (p *Product) GetTotalPurchasePrice() float64 {
 	return p.PurchasePrice * float64(p.Quantity)
 }
@@ -152,22 +177,51 @@ func (p *Product) GetTotalRevenue() float64 {
 	return p.GetTotalPrice() - p.GetTotalPurchasePrice()
 }
 
-func (p *Product) GetCorrectWeightInGallons() float64 {
-	unit := strings.ToUpper(p.SizeUnit)
-	switch unit {
-	case "OZ", "OUNCE", "OUNCES":
-		return (p.Size / 128) * float64(p.Quantity)
-	case "LB", "LBS", "POUND", "POUNDS":
-		return (p.Size * 0.125) * float64(p.Quantity)
-	case "QT", "QUART", "QUARTS":
-		return (p.Size * 0.25) * float64(p.Quantity)
-	case "GAL", "GALLON", "GALLONS":
-		return p.Size * float64(p.Quantity)
-	case "SHEETS": //Fabric softener sheets. Its a rough estimate
-		return float64(p.Quantity) * 0.15 / 128
-	default:
-		return p.Size * float64(p.Quantity)
+// GetDensity returns the density of the product in pounds per gallon, the density of water when it is not set.
+func (p *Product) GetDensity() float64 {
+	if p.Density > 0 {
+		return p.Density
+	}
+	return constants.DefaultDensityLbsPerGallon
+}
+
+// Counted products like fabric softener sheets have no volume of their own. Rough estimate of the volume of an
+// ordered pack of them
+const countedPackVolumeGallons = 0.15 / 128
+
+// GetSizeUnit returns the parsed unit of the size of the product.
+func (p *Product) GetSizeUnit() (units.Unit, error) {
+	return units.Parse(p.SizeUnit)
+}
+
+// ConvertTotalSize converts the size of the ordered quantity to a unit of volume or mass, with the density of
+// the product between the two.
+//
+// Parameters:
+//   - to: the unit to convert to
+//
+// Returns:
+//   - float64: the total size in the unit
+//   - error: if the size unit of the product is not known or can not be converted to the unit
+func (p *Product) ConvertTotalSize(to units.Unit) (float64, error) {
+	unit, err := p.GetSizeUnit()
+	if err != nil {
+		return 0, err
+	}
+	if unit.Dimension == units.DimensionCount {
+		return units.Convert(countedPackVolumeGallons*float64(p.Quantity), units.Gallon, to, p.GetDensity())
 	}
+	return units.Convert(p.Size*float64(p.Quantity), unit, to, p.GetDensity())
+}
+
+// GetVolumeInGallons returns the volume of the ordered quantity in gallons.
+func (p *Product) GetVolumeInGallons() (float64, error) {
+	return p.ConvertTotalSize(units.Gallon)
+}
+
+// GetWeightInPounds returns the weight of the ordered quantity in pounds.
+func (p *Product) GetWeightInPounds() (float64, error) {
+	return p.ConvertTotalSize(units.Pound)
 }
 
 func AreEqualPrices(a, b []*Product) bool {
@@ -228,6 +282,10 @@ func (p *Product) GetFormattedQuantity() string {
 	return fmt.Sprintf("%d", p.Quantity)
 }
 
+func (p *Product) GetFormattedTotalUnits() string {
+	return fmt.Sprintf("%d", p.GetTotalUnits())
+}
+
 func (p *Product) GetFormattedIsHazardous() string {
 	if p.Hazardous {
 		return "Yes"
@@ -236,23 +294,30 @@ func (p *Product) GetFormattedIsHazardous() string {
 }
 
 func (p *Product) GetFormattedTotalWeight() string {
-	return fmt.Sprintf("%.2f gal", p.GetCorrectWeightInGallons())
+	return formatGallons(p.GetVolumeInGallons())
 }
 
 func (p *Product) GetFormattedTotalHazardousWeight() string {
 	if p.Hazardous {
-		return fmt.Sprintf("%.2f gal", p.GetCorrectWeightInGallons())
+		return formatGallons(p.GetVolumeInGallons())
 	}
 	return "N/A"
 }
 
 func (p *Product) GetFormattedTotalNonHazardousWeight() string {
 	if !p.Hazardous {
-		return fmt.Sprintf("%.2f gal", p.GetCorrectWeightInGallons())
+		return formatGallons(p.GetVolumeInGallons())
 	}
 	return "N/A"
 }
 
+func formatGallons(gallons float64, err error) string {
+	if err != nil {
+		return "Unknown unit"
+	}
+	return fmt.Sprintf("%.2f gal", gallons)
+}
+
 func GetUpdatedProductDetails(newProduct, oldProduct *Product) map[string]any {
 	if newProduct == nil || oldProduct == nil {
 		return nil
@@ -284,6 +349,9 @@ func GetUpdatedProductDetails(newProduct, oldProduct *Product) map[string]any {
 	if newProduct.Hazardous != oldProduct.Hazardous {
 		changedValues["hazardous"] = newProduct.Hazardous
 	}
+	if newProduct.Density != oldProduct.Density {
+		changedValues["density"] = newProduct.Density
+	}
 	if newProduct.Category != oldProduct.Category {
 		changedValues["category"] = newProduct.Category
 	}
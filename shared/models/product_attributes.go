@@ -0,0 +1,237 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/units"
+)
+
+// Fields of the structured attributes of a product, named like the product fields in firestore
+const (
+	ProductAttributeBrand     = "brand"
+	ProductAttributeSKU       = "sku"
+	ProductAttributeSize      = "size"
+	ProductAttributeSizeUnit  = "sizeUnit"
+	ProductAttributePackOf    = "packOf"
+	ProductAttributeHazardous = "hazardous"
+	ProductAttributeCategory  = "category"
+	ProductAttributeDensity   = "density"
+)
+
+// ProductAttributes are the structured attributes of a product from one source, see the
+// ProductAttributeSource constants. Nil fields are not set by the source and fall back to the next source.
+// The override documents of the product_attributes collection are keyed by the product id.
+type ProductAttributes struct {
+	ProductID string    `json:"productId" firestore:"productId"`
+	Source    string    `json:"source" firestore:"source"`
+	Brand     *string   `json:"brand" firestore:"brand"`
+	SKU       *string   `json:"sku" firestore:"sku"`
+	Size      *float64  `json:"size" firestore:"size"`
+	SizeUnit  *string   `json:"sizeUnit" firestore:"sizeUnit"`
+	PackOf    *int      `json:"packOf" firestore:"packOf"`
+	Hazardous *bool     `json:"hazardous" firestore:"hazardous"`
+	Category  *string   `json:"category" firestore:"category"`
+	Density   *float64  `json:"density" firestore:"density"` // Pounds per gallon
+	UpdatedAt time.Time `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// IsSet reports whether the source sets the field, see the ProductAttribute constants.
+func (a *ProductAttributes) IsSet(field string) bool {
+	if a == nil {
+		return false
+	}
+	switch field {
+	case ProductAttributeBrand:
+		return a.Brand != nil
+	case ProductAttributeSKU:
+		return a.SKU != nil
+	case ProductAttributeSize:
+		return a.Size != nil
+	case ProductAttributeSizeUnit:
+		return a.SizeUnit != nil
+	case ProductAttributePackOf:
+		return a.PackOf != nil
+	case ProductAttributeHazardous:
+		return a.Hazardous != nil
+	case ProductAttributeCategory:
+		return a.Category != nil
+	case ProductAttributeDensity:
+		return a.Density != nil
+	}
+	return false
+}
+
+// applyTo sets the fields the source sets on the product, the fields already set by a previous source are
+// skipped.
+func (a *ProductAttributes) applyTo(product *Product, set map[string]bool) {
+	apply := func(field string, setValue func()) {
+		if a.IsSet(field) && !set[field] {
+			setValue()
+			set[field] = true
+		}
+	}
+	apply(ProductAttributeBrand, func() { product.Brand = *a.Brand })
+	apply(ProductAttributeSKU, func() { product.SKU = *a.SKU })
+	apply(ProductAttributeSize, func() { product.Size = *a.Size })
+	apply(ProductAttributeSizeUnit, func() { product.SizeUnit = *a.SizeUnit })
+	apply(ProductAttributePackOf, func() { product.PackOf = *a.PackOf })
+	apply(ProductAttributeHazardous, func() { product.Hazardous = *a.Hazardous })
+	apply(ProductAttributeCategory, func() { product.Category = *a.Category })
+	apply(ProductAttributeDensity, func() { product.Density = *a.Density })
+}
+
+// ApplyProductAttributes sets the attributes of the sources on the product, the first source that sets a field
+// wins. The issues found while parsing a source are kept only for the fields the source is used for, then the
+// resolved attributes are validated.
+//
+// Parameters:
+//   - product: the product the attributes are set on
+//   - sources: the attributes from the highest to the lowest priority, nil sources are skipped
+//   - issues: the parse issues of every source, see ProductSyncIssue.Source
+//
+// Returns:
+//   - []*ProductSyncIssue: the issues of the product, see HasProductSyncErrors
+func ApplyProductAttributes(product *Product, sources []*ProductAttributes, issues []*ProductSyncIssue) []*ProductSyncIssue {
+	set := make(map[string]bool)
+	usedSources := make(map[string]map[string]bool) // Fields set by each source before it
+	for _, source := range sources {
+		if source == nil {
+			continue
+		}
+		usedSources[source.Source] = make(map[string]bool)
+		for field := range set {
+			usedSources[source.Source][field] = true
+		}
+		source.applyTo(product, set)
+	}
+
+	resolved := make([]*ProductSyncIssue, 0)
+	for _, issue := range issues {
+		if setBefore, ok := usedSources[issue.Source]; ok && !setBefore[issue.Field] {
+			resolved = append(resolved, issue)
+		}
+	}
+
+	newIssue := func(field, severity, value, message string) {
+		for _, issue := range resolved {
+			if issue.Field == field {
+				return // The parse issue already explains it
+			}
+		}
+		resolved = append(resolved, NewProductSyncIssue(product, field, "", severity, value, message))
+	}
+	if !set[ProductAttributeSize] || product.Size <= 0 {
+		newIssue(ProductAttributeSize, constants.ProductSyncIssueError, fmt.Sprint(product.Size), "Size is missing or not positive")
+	}
+	if !set[ProductAttributeSizeUnit] {
+		newIssue(ProductAttributeSizeUnit, constants.ProductSyncIssueError, "", "Size unit is missing")
+	} else if unit, err := units.Parse(product.SizeUnit); err != nil {
+		newIssue(ProductAttributeSizeUnit, constants.ProductSyncIssueError, product.SizeUnit, err.Error())
+	} else {
+		product.SizeUnit = unit.Name
+	}
+	if !set[ProductAttributePackOf] || product.PackOf < 1 {
+		newIssue(ProductAttributePackOf, constants.ProductSyncIssueError, fmt.Sprint(product.PackOf), "Pack size is missing or less than 1")
+	}
+	if set[ProductAttributeDensity] && product.Density < 0 {
+		newIssue(ProductAttributeDensity, constants.ProductSyncIssueError, fmt.Sprint(product.Density), "Density can not be negative")
+	}
+	if product.Brand == "" {
+		newIssue(ProductAttributeBrand, constants.ProductSyncIssueWarning, "", "Brand is missing")
+	}
+	if !set[ProductAttributeHazardous] {
+		newIssue(ProductAttributeHazardous, constants.ProductSyncIssueWarning, "", fmt.Sprintf("Hazmat is missing, defaults to %t", product.Hazardous))
+	}
+	return resolved
+}
+
+// CopyProductAttributes copies the structured attributes of a stored product, used to keep them when the
+// attributes from quickbooks are invalid.
+func CopyProductAttributes(to, from *Product) {
+	to.Brand = from.Brand
+	to.SKU = from.SKU
+	to.Size = from.Size
+	to.SizeUnit = from.SizeUnit
+	to.PackOf = from.PackOf
+	to.Hazardous = from.Hazardous
+	to.Category = from.Category
+	to.Density = from.Density
+}
+
+// ProductSyncIssue is an attribute of a product that could not be parsed or is missing.
+type ProductSyncIssue struct {
+	ProductID   string `json:"productId" firestore:"productId"`
+	ProductName string `json:"productName" firestore:"productName"`
+	Field       string `json:"field" firestore:"field"`       // See the ProductAttribute constants
+	Source      string `json:"source" firestore:"source"`     // Source of the value, empty for a missing value
+	Severity    string `json:"severity" firestore:"severity"` // See the ProductSyncIssue constants
+	Value       string `json:"value" firestore:"value"`       // Raw value, empty if missing
+	Message     string `json:"message" firestore:"message"`
+}
+
+func NewProductSyncIssue(product *Product, field, source, severity, value, message string) *ProductSyncIssue {
+	return &ProductSyncIssue{
+		ProductID:   product.ID,
+		ProductName: product.Name,
+		Field:       field,
+		Source:      source,
+		Severity:    severity,
+		Value:       value,
+		Message:     message,
+	}
+}
+
+// HasProductSyncErrors reports whether any of the issues prevents the attributes from being synced.
+func HasProductSyncErrors(issues []*ProductSyncIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == constants.ProductSyncIssueError {
+			return true
+		}
+	}
+	return false
+}
+
+// ProductSyncReport is the result of a sync of the products from quickbooks, stored in the
+// product_sync_reports collection.
+type ProductSyncReport struct {
+	ID         string              `json:"id" firestore:"id"`
+	StartedAt  time.Time           `json:"startedAt" firestore:"startedAt"`
+	FinishedAt time.Time           `json:"finishedAt" firestore:"finishedAt"`
+	Created    int                 `json:"created" firestore:"created"`
+	Updated    int                 `json:"updated" firestore:"updated"`
+	Unchanged  int                 `json:"unchanged" firestore:"unchanged"`
+	Rejected   int                 `json:"rejected" firestore:"rejected"`     // New products not created because of an error
+	KeptStored int                 `json:"keptStored" firestore:"keptStored"` // Existing products that kept their stored attributes because of an error
+	Failed     int                 `json:"failed" firestore:"failed"`         // Products whose write to firestore failed
+	Issues     []*ProductSyncIssue `json:"issues" firestore:"issues"`
+}
+
+func NewProductSyncReport() *ProductSyncReport {
+	return &ProductSyncReport{
+		StartedAt: time.Now().UTC(),
+		Issues:    make([]*ProductSyncIssue, 0),
+	}
+}
+
+func (r *ProductSyncReport) AddIssues(issues []*ProductSyncIssue) {
+	r.Issues = append(r.Issues, issues...)
+}
+
+// HasErrors reports whether a product was rejected or kept its stored attributes.
+func (r *ProductSyncReport) HasErrors() bool {
+	return HasProductSyncErrors(r.Issues)
+}
+
+// ShouldSave reports whether the report is worth saving. A full sync is always saved, the sync of single
+// products only when something needs a look.
+//
+// Parameters:
+//   - fullSync: whether all the products were synced
+//
+// Returns:
+//   - bool
+func (r *ProductSyncReport) ShouldSave(fullSync bool) bool {
+	return fullSync || len(r.Issues) > 0 || r.Rejected > 0 || r.Failed > 0
+}
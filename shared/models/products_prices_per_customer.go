@@ -1,19 +1,22 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"cloud.google.com/go/firestore"
 )
 
 type ProductPricePerCustomer struct {
-	ProductName string    `json:"productName" firsetore:"productName"`
-	Brand       string    `json:"brand" firsetore:"brand"`
-	ProductID   string    `json:"productId" firsetore:"productId"`
-	CustomerID  string    `json:"customerId" firsetore:"customerId"`
-	Price       float64   `json:"price" firsetore:"price"`
-	CreatedAt   time.Time `json:"createdAt" firsetore:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt" firsetore:"updatedAt"`
+	ProductName string    `json:"productName" firestore:"productName"`
+	Brand       string    `json:"brand" firestore:"brand"`
+	ProductID   string    `json:"productId" firestore:"productId"`
+	CustomerID  string    `json:"customerId" firestore:"customerId"`
+	Price       float64   `json:"price" firestore:"price"`
+	RuleID      string    `json:"ruleId" firestore:"ruleId"`     // Rule that priced the product, empty for list price
+	RuleType    string    `json:"ruleType" firestore:"ruleType"` // Type of the rule that priced the product
+	CreatedAt   time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt" firestore:"updatedAt"`
 }
 
 func (ppc *ProductPricePerCustomer) ToMap() map[string]any {
@@ -23,17 +26,43 @@ func (ppc *ProductPricePerCustomer) ToMap() map[string]any {
 		"productId":   ppc.ProductID,
 		"customerId":  ppc.CustomerID,
 		"price":       ppc.Price,
+		"ruleId":      ppc.RuleID,
+		"ruleType":    ppc.RuleType,
 		"createdAt":   firestore.ServerTimestamp,
 		"updatedAt":   firestore.ServerTimestamp,
 	}
 }
 
-func CreateProductPricePerCustomer(product *Product, customerID string) *ProductPricePerCustomer {
+// ReassignProductPricePerCustomer moves a stored per customer price to another customer, used when customers
+// are merged.
+//
+// Parameters:
+//   - data: the stored price, it is not modified
+//   - customerID: the customer the price is moved to
+//
+// Returns:
+//   - string: the document id of the price of the customer
+//   - map[string]any: the price of the customer
+func ReassignProductPricePerCustomer(data map[string]any, customerID string) (string, map[string]any) {
+	reassigned := make(map[string]any, len(data))
+	for key, value := range data {
+		reassigned[key] = value
+	}
+	reassigned["customerId"] = customerID
+	reassigned["updatedAt"] = firestore.ServerTimestamp
+	productID, _ := data["productId"].(string)
+	return fmt.Sprintf("%s|%s", productID, customerID), reassigned
+}
+
+// CreateProductPricePerCustomer creates the per customer price row of a product from its resolved price.
+func CreateProductPricePerCustomer(product *Product, customerID string, resolvedPrice *ResolvedPrice) *ProductPricePerCustomer {
 	return &ProductPricePerCustomer{
 		ProductName: product.Name,
 		Brand:       product.Brand,
 		ProductID:   product.ID,
 		CustomerID:  customerID,
-		Price:       product.Price,
+		Price:       resolvedPrice.Price,
+		RuleID:      resolvedPrice.RuleID,
+		RuleType:    resolvedPrice.RuleType,
 	}
 }
@@ -0,0 +1,204 @@
+package models
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/utils"
+	"github.com/rwcarlsen/goexif/exif"
+)
+
+// GeoLocation is a GPS position, either reported by the device of the driver or stored in the exif of a photo.
+type GeoLocation struct {
+	Latitude  float64 `json:"latitude" firestore:"latitude"`
+	Longitude float64 `json:"longitude" firestore:"longitude"`
+	Accuracy  float64 `json:"accuracy" firestore:"accuracy"` // Radius of uncertainty in meters, 0 if unknown
+}
+
+func (g *GeoLocation) Validate() error {
+	if g.Latitude < -90 || g.Latitude > 90 || g.Longitude < -180 || g.Longitude > 180 {
+		return fmt.Errorf("Invalid delivery location %f, %f. Please retry submission again", g.Latitude, g.Longitude)
+	}
+	if g.Accuracy < 0 {
+		return errors.New("Invalid delivery location accuracy. Please retry submission again")
+	}
+	return nil
+}
+
+// GetFormatted returns the coordinates with 6 decimals (about 10 cm) and the accuracy when it is known.
+func (g *GeoLocation) GetFormatted() string {
+	if g.Accuracy > 0 {
+		return fmt.Sprintf("%.6f, %.6f (±%.0f m)", g.Latitude, g.Longitude, g.Accuracy)
+	}
+	return fmt.Sprintf("%.6f, %.6f", g.Latitude, g.Longitude)
+}
+
+// PhotoMetadata is what the exif of a delivery photo tells about when and where it was taken.
+type PhotoMetadata struct {
+	URL      string       `json:"url" firestore:"url"`           // Full resolution original in storage, empty if not uploaded
+	TakenAt  time.Time    `json:"takenAt" firestore:"takenAt"`   // Zero if the photo has no exif date
+	Location *GeoLocation `json:"location" firestore:"location"` // Nil if the photo has no exif GPS position
+}
+
+// ProofOfDelivery is the persisted record of a delivery: who delivered it, who received it, when and where.
+// The capture location is checked against the geocoded address of the customer.
+type ProofOfDelivery struct {
+	OrderID        string           `json:"orderId" firestore:"orderId"`
+	CustomerID     string           `json:"customerId" firestore:"customerId"`
+	DriverUID      string           `json:"driverUid" firestore:"driverUid"`
+	DeliveredBy    string           `json:"deliveredBy" firestore:"deliveredBy"`
+	ReceivedBy     string           `json:"receivedBy" firestore:"receivedBy"`
+	Location       *GeoLocation     `json:"location" firestore:"location"`     // GPS position of the device, nil if unknown
+	CapturedAt     time.Time        `json:"capturedAt" firestore:"capturedAt"` // Time on the device
+	DeliveredAt    time.Time        `json:"deliveredAt" firestore:"deliveredAt"`
+	Photos         []*PhotoMetadata `json:"photos" firestore:"photos"`
+	LocationStatus string           `json:"locationStatus" firestore:"locationStatus"` // See the DeliveryLocation constants
+	DistanceMeters float64          `json:"distanceMeters" firestore:"distanceMeters"` // From the customer address, -1 if unknown
+	RadiusMeters   float64          `json:"radiusMeters" firestore:"radiusMeters"`     // Max distance allowed from the customer address
+}
+
+// NewProofOfDelivery builds the proof of delivery of a delivery, reading the exif of the photos and checking
+// the capture location against the customer address.
+//
+// Parameters:
+//   - d: the delivery, its images must be the originals so the exif is still there
+//   - radiusMeters: max distance allowed between the capture location and the customer address
+//
+// Returns:
+//   - *ProofOfDelivery: the proof of delivery, not persisted yet
+func NewProofOfDelivery(d *Delivery, radiusMeters float64) *ProofOfDelivery {
+	proof := &ProofOfDelivery{
+		OrderID:      d.Order.ID,
+		DriverUID:    d.DriverUID,
+		DeliveredBy:  d.DeliveredBy,
+		ReceivedBy:   d.ReceivedBy,
+		Location:     d.Location,
+		CapturedAt:   d.CapturedAt,
+		DeliveredAt:  d.DeliveredAt,
+		Photos:       make([]*PhotoMetadata, 0, len(d.DeliveryImages)),
+		RadiusMeters: radiusMeters,
+	}
+	if d.Order.Customer != nil {
+		proof.CustomerID = d.Order.Customer.ID
+	}
+	location, err := time.LoadLocation(d.Order.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	for i, imageBytes := range d.DeliveryImages {
+		photo := getPhotoMetadata(imageBytes, location)
+		if i < len(d.ImageURLs) {
+			photo.URL = d.ImageURLs[i]
+		}
+		proof.Photos = append(proof.Photos, photo)
+	}
+	proof.verifyLocation(d.Order.Customer)
+	return proof
+}
+
+// getPhotoMetadata reads the date and the GPS position from the exif of a photo. Phones store the date as the
+// local wall clock without a zone, so it is read in the time zone of the order.
+func getPhotoMetadata(imageBytes []byte, location *time.Location) *PhotoMetadata {
+	photo := &PhotoMetadata{}
+	x, err := exif.Decode(bytes.NewReader(imageBytes))
+	if err != nil {
+		return photo
+	}
+	if takenAt, err := x.DateTime(); err == nil {
+		if takenAt.Location() == time.Local {
+			takenAt = time.Date(takenAt.Year(), takenAt.Month(), takenAt.Day(), takenAt.Hour(), takenAt.Minute(), takenAt.Second(), 0, location)
+		}
+		photo.TakenAt = takenAt.UTC()
+	}
+	if latitude, longitude, err := x.LatLong(); err == nil && !math.IsNaN(latitude) && !math.IsNaN(longitude) {
+		photo.Location = &GeoLocation{Latitude: latitude, Longitude: longitude}
+	}
+	return photo
+}
+
+// GetCheckedLocation returns the location checked against the customer address: the device position, or the
+// position of the first photo that has one when the device did not report any.
+func (p *ProofOfDelivery) GetCheckedLocation() *GeoLocation {
+	if p.Location != nil {
+		return p.Location
+	}
+	for _, photo := range p.Photos {
+		if photo.Location != nil {
+			return photo.Location
+		}
+	}
+	return nil
+}
+
+// verifyLocation sets the distance between the checked location and the customer address, and whether it is
+// within the radius. The accuracy of the device is taken in account so a poor GPS fix next to the address
+// still passes.
+func (p *ProofOfDelivery) verifyLocation(customer *Customer) {
+	location := p.GetCheckedLocation()
+	if location == nil || customer == nil || !customer.HasLocation() {
+		p.LocationStatus = constants.DeliveryLocationUnverified
+		p.DistanceMeters = -1
+		return
+	}
+	p.DistanceMeters = utils.GetDistanceInMeters(location.Latitude, location.Longitude, customer.Latitude, customer.Longitude)
+	if p.DistanceMeters-location.Accuracy <= p.RadiusMeters {
+		p.LocationStatus = constants.DeliveryLocationVerified
+	} else {
+		p.LocationStatus = constants.DeliveryLocationOutsideRadius
+	}
+}
+
+// GetFormattedLocationCheck returns the result of the location check for the documents, e.g.
+// "Verified, 120 m from the customer address (max 300 m)".
+func (p *ProofOfDelivery) GetFormattedLocationCheck() string {
+	switch p.LocationStatus {
+	case constants.DeliveryLocationVerified:
+		return fmt.Sprintf("Verified, %s from the customer address (max %.0f m)", formatDistance(p.DistanceMeters), p.RadiusMeters)
+	case constants.DeliveryLocationOutsideRadius:
+		return fmt.Sprintf("Outside radius, %s from the customer address (max %.0f m)", formatDistance(p.DistanceMeters), p.RadiusMeters)
+	}
+	return "Unverified, no capture location or customer address location"
+}
+
+// GetFormattedPhotosTakenAt returns the exif dates of the photos in the time zone, "Unknown" if none has one.
+func (p *ProofOfDelivery) GetFormattedPhotosTakenAt(timeZone string) string {
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	var first, last time.Time
+	for _, photo := range p.Photos {
+		if photo.TakenAt.IsZero() {
+			continue
+		}
+		if first.IsZero() || photo.TakenAt.Before(first) {
+			first = photo.TakenAt
+		}
+		if photo.TakenAt.After(last) {
+			last = photo.TakenAt
+		}
+	}
+	switch {
+	case first.IsZero():
+		return "Unknown"
+	case first.Equal(last):
+		return first.In(location).Format("January 2, 2006 at 3:04 PM")
+	}
+	first, last = first.In(location), last.In(location)
+	lastLayout := "3:04 PM"
+	if first.YearDay() != last.YearDay() || first.Year() != last.Year() {
+		lastLayout = "January 2, 2006 at 3:04 PM"
+	}
+	return fmt.Sprintf("%s - %s", first.Format("January 2, 2006 at 3:04 PM"), last.Format(lastLayout))
+}
+
+func formatDistance(meters float64) string {
+	if meters >= 1000 {
+		return fmt.Sprintf("%.1f km", meters/1000)
+	}
+	return fmt.Sprintf("%.0f m", meters)
+}
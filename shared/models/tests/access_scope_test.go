@@ -0,0 +1,184 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+var scopeTestAccount = &models.UserAccount{
+	Customers: []string{"1", "2"},
+	Brands:    []string{"Microtech"},
+}
+
+func createScopeTestOrder(customerID string, brands ...string) *models.Order {
+	order := &models.Order{Customer: &models.Customer{ID: customerID}}
+	for _, brand := range brands {
+		order.Items = append(order.Items, &models.Product{Name: "Cleaner", Brand: brand})
+	}
+	return order
+}
+
+func TestAccessScopeAuthorizeOrder(t *testing.T) {
+	scope := models.NewAccessScope("uid", constants.RoleAdmin, scopeTestAccount)
+	tests := []struct {
+		name    string
+		order   *models.Order
+		allowed bool
+	}{
+		{"assigned customer and brand", createScopeTestOrder("1", "Microtech"), true},
+		{"brand in another case", createScopeTestOrder("2", "microtech "), true},
+		{"customer not assigned", createScopeTestOrder("3", "Microtech"), false},
+		{"brand not assigned", createScopeTestOrder("1", "Microtech", "Acme"), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := scope.AuthorizeOrder(test.order)
+			if (err == nil) != test.allowed {
+				t.Errorf("expected allowed to be %t, got %v", test.allowed, err)
+			}
+			if err != nil && !errors.Is(err, models.ErrAccessDenied) {
+				t.Errorf("expected the error to wrap ErrAccessDenied, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAccessScopeSuperAdminAndNil(t *testing.T) {
+	superAdmin := models.NewAccessScope("uid", constants.RoleSuperAdmin, nil)
+	if err := superAdmin.AuthorizeOrder(createScopeTestOrder("3", "Acme")); err != nil {
+		t.Errorf("expected the super admin to access every order, got %v", err)
+	}
+	if err := superAdmin.AuthorizePriceRule(&models.PriceRule{}); err != nil {
+		t.Errorf("expected the super admin to write global rules, got %v", err)
+	}
+
+	var scope *models.AccessScope
+	if scope.CanAccessCustomer("1") || scope.CanAccessBrand("Microtech") {
+		t.Error("expected a nil scope to access nothing")
+	}
+}
+
+func TestAccessScopeAuthorizePriceRule(t *testing.T) {
+	scope := models.NewAccessScope("uid", constants.RoleAdmin, scopeTestAccount)
+	tests := []struct {
+		name    string
+		rule    *models.PriceRule
+		allowed bool
+	}{
+		{"rule of an assigned customer", &models.PriceRule{CustomerID: "1"}, true},
+		{"rule of an assigned brand", &models.PriceRule{CustomerID: "1", Brand: "Microtech"}, true},
+		{"rule of every customer", &models.PriceRule{Brand: "Microtech"}, false},
+		{"rule of another customer", &models.PriceRule{CustomerID: "3"}, false},
+		{"rule of another brand", &models.PriceRule{CustomerID: "1", Brand: "Acme"}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := scope.AuthorizePriceRule(test.rule); (err == nil) != test.allowed {
+				t.Errorf("expected allowed to be %t, got %v", test.allowed, err)
+			}
+		})
+	}
+}
+
+func TestAccessScopeFilters(t *testing.T) {
+	scope := models.NewAccessScope("uid", constants.RoleUser, scopeTestAccount)
+	products := []*models.Product{{ID: "a", Brand: "Microtech"}, {ID: "b", Brand: "Acme"}}
+	if filtered := scope.FilterProducts(products); len(filtered) != 1 || filtered[0].ID != "a" {
+		t.Errorf("expected only the Microtech product, got %v", filtered)
+	}
+	customers := []*models.Customer{{ID: "1"}, {ID: "3"}}
+	if filtered := scope.FilterCustomers(customers); len(filtered) != 1 || filtered[0].ID != "1" {
+		t.Errorf("expected only the customer 1, got %v", filtered)
+	}
+	orders := []*models.Order{createScopeTestOrder("1", "Microtech"), createScopeTestOrder("3", "Microtech")}
+	if filtered := scope.FilterOrders(orders); len(filtered) != 1 || filtered[0].Customer.ID != "1" {
+		t.Errorf("expected only the order of the customer 1, got %d orders", len(filtered))
+	}
+	priceList := scope.FilterPriceList(models.NewPriceList("1", products, nil))
+	if _, ok := priceList.Products["b"]; ok || len(priceList.Products) != 1 {
+		t.Errorf("expected the price list to only have the Microtech product, got %v", priceList.Products)
+	}
+}
+
+func TestAccessScopeAuthorizeOrderUpdates(t *testing.T) {
+	scope := models.NewAccessScope("uid", constants.RoleAdmin, scopeTestAccount)
+	products := map[string]*models.Product{"a": {ID: "a", Brand: "Microtech"}, "b": {ID: "b", Brand: "Acme"}}
+	tests := []struct {
+		name    string
+		updates []firestore.Update
+		allowed bool
+	}{
+		{"special instructions", []firestore.Update{{Path: "specialInstructions", Value: "Leave at the door"}}, true},
+		{"status", []firestore.Update{{Path: "status", Value: constants.OrderStatusApproved}}, false},
+		{"total", []firestore.Update{{Path: "total", Value: 0.0}}, false},
+		{"price of the items", []firestore.Update{{FieldPath: []string{"subTotal"}, Value: 0.0}}, false},
+		{"assigned customer", []firestore.Update{{Path: "customerId", Value: "2"}}, true},
+		{"customer not assigned", []firestore.Update{{Path: "customerId", Value: "3"}}, false},
+		{"embedded customer", []firestore.Update{{Path: "customer", Value: map[string]any{"id": "3"}}}, false},
+		{"items of assigned brands", []firestore.Update{{Path: "items", Value: []map[string]any{{"id": "a", "quantity": 2}}}}, true},
+		{"items decoded from json", []firestore.Update{{Path: "items", Value: []any{map[string]any{"id": "a"}}}}, true},
+		{"item of another brand", []firestore.Update{{Path: "items", Value: []map[string]any{{"id": "a"}, {"id": "b"}}}}, false},
+		{"unknown product", []firestore.Update{{Path: "items", Value: []map[string]any{{"id": "c"}}}}, false},
+		{"single item", []firestore.Update{{FieldPath: []string{"items", "0"}, Value: map[string]any{"id": "b"}}}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := scope.AuthorizeOrderUpdates(test.updates, products); (err == nil) != test.allowed {
+				t.Errorf("expected allowed to be %t, got %v", test.allowed, err)
+			}
+		})
+	}
+	productIDs, err := models.GetOrderUpdateProductIDs([]firestore.Update{{Path: "items", Value: []map[string]any{{"id": "a"}, {"id": "b"}}}})
+	if err != nil || len(productIDs) != 2 {
+		t.Errorf("expected the 2 products of the items, got %v (%v)", productIDs, err)
+	}
+}
+
+func TestGetOrderUpdateItems(t *testing.T) {
+	items, err := models.GetOrderUpdateItems([]firestore.Update{{Path: "items", Value: []any{
+		map[string]any{"id": "a", "quantity": 2.0, "price": 0.01},
+		map[string]any{"id": "b", "quantity": 1},
+	}}})
+	if err != nil || len(items) != 2 {
+		t.Fatalf("expected the 2 items, got %v (%v)", items, err)
+	}
+	if items[0].ID != "a" || items[0].Quantity != 2 || items[0].Price != 0 || items[1].Quantity != 1 {
+		t.Errorf("expected only the ids and quantities of the items, got %+v %+v", items[0], items[1])
+	}
+
+	if items, err := models.GetOrderUpdateItems([]firestore.Update{{Path: "specialInstructions", Value: ""}}); err != nil || items != nil {
+		t.Errorf("expected no items when they are not updated, got %v (%v)", items, err)
+	}
+	if _, err := models.GetOrderUpdateItems([]firestore.Update{{Path: "items", Value: []map[string]any{{"id": "a"}}}}); err == nil {
+		t.Error("expected an error for an item without a quantity")
+	}
+}
+
+func TestAccessScopeAuthorizeCategoryRuleUpdate(t *testing.T) {
+	scope := models.NewAccessScope("uid", constants.RoleAdmin, scopeTestAccount)
+	products := []*models.Product{
+		{ID: "a", Brand: "Microtech", Category: "Cleaners"},
+		{ID: "b", Brand: "Acme", Category: "Degreasers"},
+		{ID: "c", Brand: "Microtech", Category: "Degreasers"},
+	}
+	rule := &models.PriceRule{Type: constants.PriceRuleTypeCategoryDiscount, CustomerID: "1", Category: "Cleaners", DiscountPercent: 10}
+	if err := scope.AuthorizeCategory(rule.Category, products); err != nil {
+		t.Errorf("expected a category of assigned brands only to be allowed, got %v", err)
+	}
+
+	updated, err := rule.WithDetails(map[string]any{"category": "Degreasers"})
+	if err != nil || updated.Category != "Degreasers" || updated.DiscountPercent != 10 || rule.Category != "Cleaners" {
+		t.Fatalf("expected a copy with the new category, got %+v (%v)", updated, err)
+	}
+	err = scope.AuthorizeCategory(updated.Category, products)
+	if err == nil || !errors.Is(err, models.ErrAccessDenied) {
+		t.Errorf("expected a category with products of another brand to be denied, got %v", err)
+	}
+	if _, err := rule.WithDetails(map[string]any{"discountPercent": "ten"}); err == nil {
+		t.Error("expected an error for a value of the wrong type")
+	}
+}
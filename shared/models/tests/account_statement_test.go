@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestNewAccountStatement(t *testing.T) {
+	transactions := []*models.AccountTransaction{
+		{Type: constants.AccountTransactionTypePayment, Date: date(2025, 3, 10), Amount: 40},
+		{Type: constants.AccountTransactionTypeInvoice, Date: date(2025, 2, 15), Amount: 100},
+		{Type: constants.AccountTransactionTypeInvoice, Date: date(2025, 3, 5), Amount: 50},
+		{Type: constants.AccountTransactionTypeCredit, Date: date(2025, 3, 31), Amount: 10},
+		{Type: constants.AccountTransactionTypeInvoice, Date: date(2025, 4, 2), Amount: 999}, // After the range
+	}
+
+	statement := models.NewAccountStatement(&models.Customer{ID: "1"}, transactions, date(2025, 3, 1), date(2025, 3, 31))
+	if statement.OpeningBalance != 100 {
+		t.Errorf("expected opening balance 100, got %v", statement.OpeningBalance)
+	}
+	if len(statement.Lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(statement.Lines))
+	}
+	expectedBalances := []float64{150, 110, 100}
+	for i, line := range statement.Lines {
+		if line.RunningBalance != expectedBalances[i] {
+			t.Errorf("line %d: expected balance %v, got %v", i, expectedBalances[i], line.RunningBalance)
+		}
+	}
+	if statement.TotalInvoiced != 50 || statement.TotalPaid != 50 || statement.ClosingBalance != 100 {
+		t.Errorf("unexpected totals %+v", statement)
+	}
+}
+
+func TestNewARAgingReport(t *testing.T) {
+	asOf := date(2025, 6, 30)
+	transactions := []*models.AccountTransaction{
+		{CustomerID: "b", Type: constants.AccountTransactionTypeInvoice, DueDate: date(2025, 7, 15), OpenBalance: 10}, // Current
+		{CustomerID: "b", Type: constants.AccountTransactionTypeInvoice, DueDate: date(2025, 6, 30), OpenBalance: 5},  // Due today, current
+		{CustomerID: "b", Type: constants.AccountTransactionTypeInvoice, DueDate: date(2025, 5, 31), OpenBalance: 20}, // 30 days
+		{CustomerID: "a", Type: constants.AccountTransactionTypeInvoice, DueDate: date(2025, 5, 30), OpenBalance: 30}, // 31 days
+		{CustomerID: "a", Type: constants.AccountTransactionTypeInvoice, DueDate: date(2025, 4, 1), OpenBalance: 40},  // 90 days
+		{CustomerID: "a", Type: constants.AccountTransactionTypeInvoice, DueDate: date(2025, 3, 31), OpenBalance: 50}, // 91 days
+		{CustomerID: "a", Type: constants.AccountTransactionTypePayment, Date: date(2025, 6, 1), OpenBalance: 15},     // Unapplied
+		{CustomerID: "c", Type: constants.AccountTransactionTypeInvoice, DueDate: date(2025, 1, 1), OpenBalance: 0},   // Paid
+	}
+	customers := []*models.Customer{{ID: "a", Name: "Alpha"}, {ID: "b", Name: "Beta"}, {ID: "c", Name: "Gamma"}}
+
+	report := models.NewARAgingReport(customers, transactions, asOf)
+	if len(report.Rows) != 2 || report.Rows[0].Customer.Name != "Alpha" || report.Rows[1].Customer.Name != "Beta" {
+		t.Fatalf("unexpected rows %+v", report.Rows)
+	}
+	expectedAlpha := models.ARAgingBuckets{Current: -15, Days31To60: 30, Days61To90: 40, Over90: 50}
+	if report.Rows[0].Buckets != expectedAlpha {
+		t.Errorf("expected %+v, got %+v", expectedAlpha, report.Rows[0].Buckets)
+	}
+	expectedBeta := models.ARAgingBuckets{Current: 15, Days1To30: 20}
+	if report.Rows[1].Buckets != expectedBeta {
+		t.Errorf("expected %+v, got %+v", expectedBeta, report.Rows[1].Buckets)
+	}
+	if report.Totals.GetTotal() != 140 || report.Totals.GetOverdue() != 140 {
+		t.Errorf("unexpected totals %+v", report.Totals)
+	}
+}
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+func createTestBrandCatalog() *models.BrandCatalog {
+	return models.NewBrandCatalog([]*models.Brand{
+		{ID: "microtech", Name: "Microtech", Aliases: []string{"Micro Tech", "MT"}, IsActive: true},
+		{ID: "problend", Name: "ProBlend", IsActive: true},
+		{ID: "old-brand", Name: "Old Brand", IsActive: false},
+	})
+}
+
+func TestNewBrand(t *testing.T) {
+	brand, err := models.NewBrand(" Micro Tech ", []string{"MT"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if brand.ID != "micro-tech" || brand.Name != "Micro Tech" || !brand.IsActive {
+		t.Errorf("unexpected brand %+v", brand)
+	}
+	if _, err := models.NewBrand(" - ", nil, ""); err == nil {
+		t.Error("expected an error for a brand without a name")
+	}
+	if _, err := models.NewBrand("Acme", []string{"AC", "AC"}, ""); err == nil {
+		t.Error("expected an error for duplicate aliases")
+	}
+}
+
+func TestBrandCatalogFind(t *testing.T) {
+	catalog := createTestBrandCatalog()
+	for _, name := range []string{"Microtech", "microtech", " micro tech ", "mt"} {
+		if brand, ok := catalog.Find(name); !ok || brand.Name != "Microtech" {
+			t.Errorf("expected %q to be Microtech", name)
+		}
+	}
+	if _, ok := catalog.Find("Old Brand"); ok {
+		t.Error("expected an inactive brand not to be found")
+	}
+	var nilCatalog *models.BrandCatalog
+	if _, ok := nilCatalog.Find("Microtech"); ok {
+		t.Error("expected a nil catalog to have no brands")
+	}
+}
+
+func TestUserAccountCreateValidateBrands(t *testing.T) {
+	account := &models.UserAccountCreate{Brands: []string{"micro tech", "problend"}}
+	if err := account.ValidateBrands(createTestBrandCatalog()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(account.Brands, []string{"Microtech", "ProBlend"}) {
+		t.Errorf("expected the display names of the brands, got %v", account.Brands)
+	}
+
+	if err := (&models.UserAccountCreate{Brands: []string{"Microtech", "Old Brand", "Acme"}}).ValidateBrands(createTestBrandCatalog()); err == nil {
+		t.Error("expected an error for an inactive and an unknown brand")
+	}
+	if err := (&models.UserAccountCreate{Brands: []string{"Microtech", "MT"}}).ValidateBrands(createTestBrandCatalog()); err == nil {
+		t.Error("expected an error for two aliases of the same brand")
+	}
+}
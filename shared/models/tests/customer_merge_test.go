@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+func TestUserAccountReplaceCustomer(t *testing.T) {
+	tests := []struct {
+		name      string
+		customers []string
+		expected  []string
+	}{
+		{"merged customer", []string{"1", "2"}, []string{"1", "3"}},
+		{"surviving customer already assigned", []string{"2", "3", "4"}, []string{"4", "3"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			account := &models.UserAccount{Customers: test.customers}
+			customers := account.ReplaceCustomer("2", "3")
+			if len(customers) != len(test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, customers)
+			}
+			for i := range customers {
+				if customers[i] != test.expected[i] {
+					t.Errorf("expected %v, got %v", test.expected, customers)
+				}
+			}
+		})
+	}
+}
+
+func TestReassignProductPricePerCustomer(t *testing.T) {
+	data := map[string]any{"productId": "68", "customerId": "2", "price": 12.5}
+	key, reassigned := models.ReassignProductPricePerCustomer(data, "3")
+	if key != "68|3" {
+		t.Errorf("expected the id of the surviving customer price, got %s", key)
+	}
+	if reassigned["customerId"] != "3" || reassigned["price"] != 12.5 || reassigned["updatedAt"] != firestore.ServerTimestamp {
+		t.Errorf("expected the price to be moved to the surviving customer, got %v", reassigned)
+	}
+	if data["customerId"] != "2" {
+		t.Error("expected the stored price to be left unchanged")
+	}
+}
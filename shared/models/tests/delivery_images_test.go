@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+func createPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, image.NewNRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func TestGetCompressedImages(t *testing.T) {
+	delivery := &models.Delivery{DeliveryImages: [][]byte{createPNG(t, 2000, 1000), createPNG(t, 300, 600)}}
+	images := delivery.GetCompressedImages(models.ImageCompression{MaxDimension: 800, Quality: 75})
+	if len(images) != 2 {
+		t.Fatalf("expected 2 images, got %d", len(images))
+	}
+
+	// Large images are downscaled keeping their aspect ratio, small images keep their size
+	expected := []image.Point{{800, 400}, {300, 600}}
+	for i, imageBytes := range images {
+		config, format, err := image.DecodeConfig(bytes.NewReader(imageBytes))
+		if err != nil {
+			t.Fatalf("image %d: %v", i, err)
+		}
+		if format != "jpeg" {
+			t.Errorf("image %d: expected jpeg, got %s", i, format)
+		}
+		if config.Width != expected[i].X || config.Height != expected[i].Y {
+			t.Errorf("image %d: expected %v, got %dx%d", i, expected[i], config.Width, config.Height)
+		}
+	}
+}
+
+func TestGetCompressedImagesKeepsUndecodableImages(t *testing.T) {
+	original := [][]byte{[]byte("not an image")}
+	delivery := &models.Delivery{DeliveryImages: original}
+	images := delivery.GetCompressedImages(models.DeliveryThumbnailCompression)
+	if len(images) != 1 || !bytes.Equal(images[0], original[0]) {
+		t.Errorf("expected the original images when none can be decoded, got %d images", len(images))
+	}
+}
+
+func TestGetCorrectlyRotatedImagesKeepsPNG(t *testing.T) {
+	delivery := &models.Delivery{DeliveryImages: [][]byte{createPNG(t, 2000, 1000)}}
+	images := delivery.GetCorrectlyRotatedImages()
+	if len(images) != 1 {
+		t.Fatalf("expected 1 image, got %d", len(images))
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(images[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "png" || config.Width != 2000 || config.Height != 1000 {
+		t.Errorf("expected a 2000x1000 png, got a %dx%d %s", config.Width, config.Height, format)
+	}
+}
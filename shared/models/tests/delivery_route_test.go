@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+var routeTestVehicle = &models.Vehicle{ID: "truck", Name: "Truck", IsActive: true}
+
+func createRouteTestOrder(id string, latitude, longitude float64) *models.Order {
+	return &models.Order{
+		ID:       id,
+		Status:   constants.OrderStatusApproved,
+		Customer: &models.Customer{ID: id, Latitude: latitude, Longitude: longitude},
+		Items:    []*models.Product{{Quantity: 2, Hazardous: true, Size: 1, SizeUnit: "gal", PackOf: 1}},
+	}
+}
+
+func TestNewDeliveryRouteOrdersStopsByNearestNeighbour(t *testing.T) {
+	depot := &models.GeoLocation{Latitude: 36.0, Longitude: -115.0}
+	orders := []*models.Order{
+		createRouteTestOrder("far", 36.3, -115.0),
+		createRouteTestOrder("not-geocoded", 0, 0),
+		createRouteTestOrder("near", 36.1, -115.0),
+		// Closer to "near" than to "far" although it is further from the depot than "near"
+		createRouteTestOrder("middle", 36.15, -115.01),
+	}
+	deliveryDate := time.Date(2025, time.March, 14, 17, 30, 0, 0, time.UTC)
+	route, err := models.NewDeliveryRoute("route-1", "driver-1", "Driver", routeTestVehicle, deliveryDate, depot, orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"near", "middle", "far", "not-geocoded"}
+	if !reflect.DeepEqual(route.GetOrderIDs(), expected) {
+		t.Errorf("expected the stops %v, got %v", expected, route.GetOrderIDs())
+	}
+	if route.Stops[3].DistanceMeters != -1 {
+		t.Errorf("expected an unknown distance for the stop without location, got %f", route.Stops[3].DistanceMeters)
+	}
+	if !route.DeliveryDate.Equal(time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the delivery date to be the day only, got %v", route.DeliveryDate)
+	}
+	for i, stop := range route.Stops {
+		schedule := stop.Order.Schedule
+		if schedule == nil || schedule.RouteID != "route-1" || schedule.DriverUID != "driver-1" || schedule.Stop != i+1 {
+			t.Errorf("stop %d: expected the order to be scheduled on the route, got %+v", i+1, schedule)
+		}
+	}
+}
+
+func TestNewDeliveryRouteWithoutDepotStartsAtFirstStop(t *testing.T) {
+	orders := []*models.Order{
+		createRouteTestOrder("first", 36.0, -115.0),
+		createRouteTestOrder("far", 37.0, -115.0),
+		createRouteTestOrder("near", 36.1, -115.0),
+	}
+	route, err := models.NewDeliveryRoute("route-1", "driver-1", "Driver", routeTestVehicle, time.Now(), nil, orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"first", "near", "far"}
+	if !reflect.DeepEqual(route.GetOrderIDs(), expected) {
+		t.Errorf("expected the stops %v, got %v", expected, route.GetOrderIDs())
+	}
+}
+
+func TestNewDeliveryRouteRejectsUnapprovedOrders(t *testing.T) {
+	order := createRouteTestOrder("pending", 36.0, -115.0)
+	order.Status = constants.OrderStatusPending
+	if _, err := models.NewDeliveryRoute("route-1", "driver-1", "Driver", routeTestVehicle, time.Now(), nil, []*models.Order{order}); err == nil {
+		t.Error("expected an error for a pending order")
+	}
+	if _, err := models.NewDeliveryRoute("route-1", "driver-1", "Driver", routeTestVehicle, time.Now(), nil, nil); err == nil {
+		t.Error("expected an error for a route without orders")
+	}
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+var inviteTestSecret = []byte("secret")
+
+func createTestInvite(now time.Time) *models.Invite {
+	invite := &models.Invite{
+		ID:        "invite-1",
+		UID:       "uid-1",
+		Name:      "John",
+		Email:     "john@example.com",
+		Customers: []string{"1"},
+		Brands:    []string{"Microtech"},
+		Role:      constants.RoleUser,
+	}
+	invite.SetPending(now)
+	return invite
+}
+
+func TestInviteValidate(t *testing.T) {
+	invite := createTestInvite(time.Now())
+	if err := invite.Validate(); err != nil {
+		t.Errorf("expected the invite to be valid, got %v", err)
+	}
+	invite.Role = "owner"
+	if invite.Validate() == nil {
+		t.Error("expected an error for an unknown role")
+	}
+	invite = createTestInvite(time.Now())
+	invite.GrantedPermissions = []string{"order:delete"}
+	if invite.Validate() == nil {
+		t.Error("expected an error for an unknown permission")
+	}
+}
+
+func TestInviteToken(t *testing.T) {
+	now := time.Now()
+	invite := createTestInvite(now)
+	token := invite.GetToken(inviteTestSecret)
+
+	inviteID, expiresAt, err := models.ParseInviteToken(token, inviteTestSecret, now)
+	if err != nil || inviteID != invite.ID || !invite.MatchesToken(expiresAt) {
+		t.Fatalf("expected the token of %s, got %s (%v)", invite.ID, inviteID, err)
+	}
+	if _, _, err := models.ParseInviteToken(token, []byte("other secret"), now); err == nil {
+		t.Error("expected an error for a token signed with another secret")
+	}
+	if _, _, err := models.ParseInviteToken(token+"x", inviteTestSecret, now); err == nil {
+		t.Error("expected an error for a tampered token")
+	}
+	if _, _, err := models.ParseInviteToken(token, inviteTestSecret, now.Add(constants.InviteExpiry)); err == nil {
+		t.Error("expected an error for an expired token")
+	}
+
+	invite.SetPending(now.Add(time.Hour))
+	if invite.MatchesToken(expiresAt) || invite.SentCount != 2 {
+		t.Errorf("expected the resent invite to replace the token, sent %d times", invite.SentCount)
+	}
+}
+
+func TestInviteCanAccept(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		update  func(invite *models.Invite)
+		uid     string
+		at      time.Time
+		allowed bool
+	}{
+		{"pending invite", func(*models.Invite) {}, "uid-1", now, true},
+		{"another user", func(*models.Invite) {}, "uid-2", now, false},
+		{"expired invite", func(*models.Invite) {}, "uid-1", now.Add(constants.InviteExpiry), false},
+		{"revoked invite", func(invite *models.Invite) { invite.Status = constants.InviteStatusRevoked }, "uid-1", now, false},
+		{"accepted invite", func(invite *models.Invite) { invite.Status = constants.InviteStatusAccepted }, "uid-1", now, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			invite := createTestInvite(now)
+			test.update(invite)
+			if err := invite.CanAccept(test.uid, test.at); (err == nil) != test.allowed {
+				t.Errorf("expected allowed to be %t, got %v", test.allowed, err)
+			}
+		})
+	}
+
+	invite := createTestInvite(now)
+	invite.Status = constants.InviteStatusAccepted
+	if invite.CanResend() == nil || invite.CanRevoke() == nil {
+		t.Error("expected an accepted invite to not be resent or revoked")
+	}
+	account := createTestInvite(now).ToUserAccount()
+	if account.Email != "john@example.com" || account.Role != constants.RoleUser || !account.HasPermission(constants.PermissionOrderCreate) {
+		t.Errorf("unexpected account %+v", account)
+	}
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+func TestUserAccountPermissions(t *testing.T) {
+	tests := []struct {
+		name     string
+		account  *models.UserAccount
+		allowed  []string
+		disabled []string
+	}{
+		{
+			name:     "permissions of the role",
+			account:  &models.UserAccount{Role: constants.RoleUser},
+			allowed:  []string{constants.PermissionOrderCreate},
+			disabled: []string{constants.PermissionOrderApprove, constants.PermissionReportView},
+		},
+		{
+			name:     "granted and revoked permissions",
+			account:  &models.UserAccount{Role: constants.RoleAdmin, GrantedPermissions: []string{constants.PermissionQuickBooksConnect}, RevokedPermissions: []string{constants.PermissionPriceEdit}},
+			allowed:  []string{constants.PermissionQuickBooksConnect, constants.PermissionOrderApprove},
+			disabled: []string{constants.PermissionPriceEdit, constants.PermissionUserManage},
+		},
+		{
+			name:    "super admin keeps every permission",
+			account: &models.UserAccount{Role: constants.RoleSuperAdmin, RevokedPermissions: []string{constants.PermissionUserManage}},
+			allowed: []string{constants.PermissionUserManage, constants.PermissionQuickBooksConnect, constants.PermissionOrderDeliver},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, permission := range test.allowed {
+				if !test.account.HasPermission(permission) {
+					t.Errorf("expected %s in %v", permission, test.account.GetPermissions())
+				}
+			}
+			for _, permission := range test.disabled {
+				if test.account.HasPermission(permission) {
+					t.Errorf("expected no %s in %v", permission, test.account.GetPermissions())
+				}
+			}
+			claims := test.account.ToClaims()
+			if claims[constants.ClaimRole] != test.account.Role || !slices.Equal(claims[constants.ClaimPermissions].([]string), test.account.GetPermissions()) {
+				t.Errorf("unexpected claims %v", claims)
+			}
+		})
+	}
+}
+
+func TestValidatePermissionOverrides(t *testing.T) {
+	tests := []struct {
+		name    string
+		granted []string
+		revoked []string
+		valid   bool
+	}{
+		{"no overrides", nil, nil, true},
+		{"known permissions", []string{constants.PermissionReportView}, []string{constants.PermissionOrderCancel}, true},
+		{"unknown permission", []string{"order:delete"}, nil, false},
+		{"duplicate permission", []string{constants.PermissionReportView, constants.PermissionReportView}, nil, false},
+		{"granted and revoked", []string{constants.PermissionReportView}, []string{constants.PermissionReportView}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := models.ValidatePermissionOverrides(test.granted, test.revoked); (err == nil) != test.valid {
+				t.Errorf("expected valid to be %t, got %v", test.valid, err)
+			}
+		})
+	}
+}
+
+func TestUserAccountMergeClaims(t *testing.T) {
+	account := &models.UserAccount{Role: constants.RoleUser}
+	existing := map[string]any{constants.ClaimRole: constants.RoleAdmin, "tenant": "ahs"}
+
+	claims := account.MergeClaims(existing)
+	if claims["tenant"] != "ahs" {
+		t.Errorf("expected the other claims to be kept, got %v", claims)
+	}
+	if claims[constants.ClaimRole] != constants.RoleUser || claims[constants.ClaimPermissions] == nil {
+		t.Errorf("expected the role and the permissions of the account, got %v", claims)
+	}
+	if existing[constants.ClaimRole] != constants.RoleAdmin {
+		t.Error("expected the existing claims to be left unchanged")
+	}
+	if claims := account.MergeClaims(nil); len(claims) != 2 {
+		t.Errorf("expected only the claims of the account, got %v", claims)
+	}
+}
+
+func TestRemoveAccountClaims(t *testing.T) {
+	existing := map[string]any{constants.ClaimRole: constants.RoleAdmin, constants.ClaimPermissions: []string{}, "tenant": "ahs"}
+	claims := models.RemoveAccountClaims(existing)
+	if len(claims) != 1 || claims["tenant"] != "ahs" {
+		t.Errorf("expected only the other claims to be kept, got %v", claims)
+	}
+	if len(existing) != 3 {
+		t.Error("expected the existing claims to be left unchanged")
+	}
+}
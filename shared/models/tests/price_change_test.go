@@ -0,0 +1,24 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+func TestGetProductPriceChange(t *testing.T) {
+	oldProduct := &models.Product{ID: "1", Name: "Chlorine", Price: 10}
+	newProduct := &models.Product{ID: "1", Name: "Chlorine", Price: 12.5}
+
+	priceChange := models.GetProductPriceChange(newProduct, oldProduct, constants.PriceChangeSourceQuickBooks)
+	if priceChange == nil {
+		t.Fatal("expected a price change")
+	}
+	if priceChange.OldPrice != 10 || priceChange.NewPrice != 12.5 || priceChange.CustomerID != "" || priceChange.Source != constants.PriceChangeSourceQuickBooks {
+		t.Errorf("unexpected price change %+v", priceChange)
+	}
+	if models.GetProductPriceChange(oldProduct, oldProduct, constants.PriceChangeSourceQuickBooks) != nil {
+		t.Error("expected no price change for the same price")
+	}
+}
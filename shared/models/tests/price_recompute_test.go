@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+func TestNewPriceRecomputeOptionsForRules(t *testing.T) {
+	contract := &models.PriceRule{Type: constants.PriceRuleTypeContract, CustomerID: "c1", ProductID: "p1"}
+	updatedContract := &models.PriceRule{Type: constants.PriceRuleTypeContract, CustomerID: "c1", ProductID: "p2"}
+
+	options := models.NewPriceRecomputeOptionsForRules(constants.PriceChangeSourceManual, contract, updatedContract)
+	if len(options.CustomerIDs) != 1 || options.CustomerIDs[0] != "c1" || len(options.ProductIDs) != 2 {
+		t.Errorf("expected one customer and two products, got %+v", options)
+	}
+
+	brandDiscount := &models.PriceRule{Type: constants.PriceRuleTypeBrandDiscount, Brand: "Aquachem"}
+	options = models.NewPriceRecomputeOptionsForRules(constants.PriceChangeSourceManual, contract, brandDiscount)
+	if !options.IsFullRecompute() {
+		t.Errorf("expected a global brand discount to recompute everything, got %+v", options)
+	}
+}
+
+func TestPriceRecomputeDryRunReportsCandidateRule(t *testing.T) {
+	product := &models.Product{ID: "p1", Name: "Glass Cleaner", Price: 10}
+	stored := []*models.PriceRule{{ID: "r1", Type: constants.PriceRuleTypeContract, CustomerID: "c1", ProductID: "p1", Price: 9, IsActive: true}}
+	original := &models.ProductPricePerCustomer{ProductID: "p1", CustomerID: "c1", Price: 9, RuleID: "r1"}
+
+	//Without the candidate rule the stored price does not change
+	options := &models.PriceRecomputeOptions{Source: constants.PriceChangeSourceManual, DryRun: true}
+	report := recomputeTestPrice(options, stored, product, original)
+	if report.Unchanged != 1 || len(report.Changes) != 0 {
+		t.Fatalf("expected the price to be unchanged, got %+v", report)
+	}
+
+	candidate := &models.PriceRule{Type: constants.PriceRuleTypeContract, CustomerID: "c1", ProductID: "p1", Price: 8, Priority: 1, IsActive: true}
+	options.Rules = []*models.PriceRule{candidate}
+	report = recomputeTestPrice(options, stored, product, original)
+	if report.Updated != 1 || len(report.Changes) != 1 {
+		t.Fatalf("expected the candidate rule to change the price, got %+v", report)
+	}
+	if change := report.Changes[0]; change.OldPrice != 9 || change.NewPrice != 8 {
+		t.Errorf("expected the price to change from 9 to 8, got %+v", change)
+	}
+
+	//An updated rule replaces the stored rule with the same id
+	options.Rules = []*models.PriceRule{{ID: "r1", Type: constants.PriceRuleTypeContract, CustomerID: "c1", ProductID: "p1", Price: 7, IsActive: true}}
+	if rules := options.GetRules(stored); len(rules) != 1 || rules[0].Price != 7 {
+		t.Errorf("expected the stored rule to be replaced, got %v", rules)
+	}
+}
+
+func recomputeTestPrice(options *models.PriceRecomputeOptions, stored []*models.PriceRule, product *models.Product, original *models.ProductPricePerCustomer) *models.PriceRecomputeReport {
+	report := models.NewPriceRecomputeReport(options.DryRun)
+	priceList := models.NewPriceList(original.CustomerID, []*models.Product{product}, options.GetRules(stored))
+	resolved, _ := priceList.Resolve(product.ID, 1, time.Now())
+	report.AddPrice(product, original.CustomerID, original, resolved, options.Source)
+	return report
+}
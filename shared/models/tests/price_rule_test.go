@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+var pricingNow = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestPriceList(rules ...*models.PriceRule) *models.PriceList {
+	products := []*models.Product{
+		{ID: "1", Brand: "Aquachem", Category: "Pool", Price: 100},
+		{ID: "2", Brand: "Other", Category: "Spa", Price: 50},
+	}
+	return models.NewPriceList("c1", products, rules)
+}
+
+func TestResolveUsesListPriceWithoutRules(t *testing.T) {
+	resolved, ok := newTestPriceList().Resolve("1", 1, pricingNow)
+	if !ok || resolved.Price != 100 || resolved.RuleType != constants.PriceRuleTypeList || resolved.RuleID != "" {
+		t.Errorf("expected the list price, got %+v", resolved)
+	}
+	if _, ok := newTestPriceList().Resolve("missing", 1, pricingNow); ok {
+		t.Error("expected a missing product to not resolve")
+	}
+}
+
+func TestResolvePrefersMostSpecificRule(t *testing.T) {
+	priceList := newTestPriceList(
+		&models.PriceRule{ID: "brand", Type: constants.PriceRuleTypeBrandDiscount, Brand: "Aquachem", DiscountPercent: 50, IsActive: true},
+		&models.PriceRule{ID: "category", Type: constants.PriceRuleTypeCategoryDiscount, CustomerID: "c1", Category: "Pool", DiscountPercent: 10, IsActive: true},
+		&models.PriceRule{ID: "contract", Type: constants.PriceRuleTypeContract, CustomerID: "c1", ProductID: "1", Price: 80, IsActive: true},
+		&models.PriceRule{ID: "other", Type: constants.PriceRuleTypeContract, CustomerID: "c2", ProductID: "1", Price: 1, IsActive: true},
+	)
+	resolved, _ := priceList.Resolve("1", 1, pricingNow)
+	if resolved.RuleID != "contract" || resolved.Price != 80 {
+		t.Errorf("expected the contract price to win, got %+v", resolved)
+	}
+}
+
+func TestResolvePriorityAndTieBreak(t *testing.T) {
+	priceList := newTestPriceList(
+		&models.PriceRule{ID: "contract", Type: constants.PriceRuleTypeContract, CustomerID: "c1", ProductID: "1", Price: 80, IsActive: true},
+		&models.PriceRule{ID: "b", Type: constants.PriceRuleTypeBrandDiscount, Brand: "Aquachem", DiscountPercent: 10, Priority: 1, IsActive: true},
+		&models.PriceRule{ID: "a", Type: constants.PriceRuleTypeBrandDiscount, Brand: "Aquachem", DiscountPercent: 10, Priority: 1, IsActive: true},
+	)
+	for i := 0; i < 5; i++ {
+		resolved, _ := priceList.Resolve("1", 1, pricingNow)
+		if resolved.RuleID != "a" || resolved.Price != 90 {
+			t.Fatalf("expected rule a to win on priority and id, got %+v", resolved)
+		}
+	}
+}
+
+func TestResolveQuantityTiersAndEffectiveDates(t *testing.T) {
+	priceList := newTestPriceList(
+		&models.PriceRule{
+			ID: "contract", Type: constants.PriceRuleTypeContract, CustomerID: "c1", ProductID: "1", Price: 90, IsActive: true,
+			Tiers: []models.PriceTier{{MinQuantity: 10, Price: 85}, {MinQuantity: 50, Price: 75}},
+		},
+		&models.PriceRule{
+			ID: "expired", Type: constants.PriceRuleTypeContract, CustomerID: "c1", ProductID: "1", Price: 10, IsActive: true,
+			EffectiveTo: pricingNow,
+		},
+		&models.PriceRule{
+			ID: "future", Type: constants.PriceRuleTypeContract, CustomerID: "c1", ProductID: "1", Price: 10, IsActive: true,
+			EffectiveFrom: pricingNow.Add(time.Hour),
+		},
+	)
+	for quantity, expected := range map[int]float64{1: 90, 10: 85, 49: 85, 50: 75} {
+		resolved, _ := priceList.Resolve("1", quantity, pricingNow)
+		if resolved.Price != expected || resolved.RuleID != "contract" {
+			t.Errorf("quantity %d: expected %.2f from contract, got %+v", quantity, expected, resolved)
+		}
+	}
+}
+
+func TestOrderSetItemPricesRecordsRule(t *testing.T) {
+	order := &models.Order{
+		CreatedAt: pricingNow,
+		Items:     []*models.Product{{ID: "1", Quantity: 2}, {ID: "2", Quantity: 1}},
+	}
+	priceList := newTestPriceList(&models.PriceRule{ID: "pool", Type: constants.PriceRuleTypeCategoryDiscount, Category: "Pool", DiscountPercent: 15, IsActive: true})
+	if err := order.SetItemPrices(priceList); err != nil {
+		t.Fatal(err)
+	}
+	if order.Items[0].Price != 85 || order.Items[0].PriceRuleID != "pool" {
+		t.Errorf("unexpected first item %+v", order.Items[0])
+	}
+	if order.Items[1].Price != 50 || order.Items[1].PriceRuleType != constants.PriceRuleTypeList {
+		t.Errorf("unexpected second item %+v", order.Items[1])
+	}
+
+	order.Items = append(order.Items, &models.Product{ID: "missing", Quantity: 1})
+	if err := order.SetItemPrices(priceList); err == nil {
+		t.Error("expected an error for a product without a price")
+	}
+}
+
+func TestPriceRuleValidate(t *testing.T) {
+	contract := func(price float64, tiers ...models.PriceTier) *models.PriceRule {
+		return &models.PriceRule{Type: constants.PriceRuleTypeContract, CustomerID: "c1", ProductID: "1", Price: price, Tiers: tiers}
+	}
+	brandDiscount := func(discount float64, tiers ...models.PriceTier) *models.PriceRule {
+		return &models.PriceRule{Type: constants.PriceRuleTypeBrandDiscount, Brand: "Aquachem", DiscountPercent: discount, Tiers: tiers}
+	}
+	tests := []struct {
+		name  string
+		rule  *models.PriceRule
+		valid bool
+	}{
+		{"contract price", contract(90), true},
+		{"contract price with tiers", contract(90, models.PriceTier{MinQuantity: 10, Price: 80}), true},
+		{"contract without a price", contract(0), false},
+		{"negative contract price", contract(-1), false},
+		{"tiers from a single unit without a base price", contract(0, models.PriceTier{MinQuantity: 1, Price: 90}, models.PriceTier{MinQuantity: 10, Price: 80}), true},
+		{"tiers without a base price", contract(0, models.PriceTier{MinQuantity: 10, Price: 80}), false},
+		{"contract tier without a price", contract(90, models.PriceTier{MinQuantity: 10}), false},
+		{"contract tier with only a discount", contract(90, models.PriceTier{MinQuantity: 10, DiscountPercent: 10}), false},
+		{"tier below a single unit", contract(90, models.PriceTier{MinQuantity: 0, Price: 80}), false},
+		{"brand discount", brandDiscount(10), true},
+		{"brand discount with tiers", brandDiscount(0, models.PriceTier{MinQuantity: 10, DiscountPercent: 5}), true},
+		{"discount tier without a discount", brandDiscount(10, models.PriceTier{MinQuantity: 10, Price: 80}), false},
+		{"discount tier over 100 percent", brandDiscount(10, models.PriceTier{MinQuantity: 10, DiscountPercent: 101}), false},
+		{"discount over 100 percent", brandDiscount(101), false},
+		{"category discount without a category", &models.PriceRule{Type: constants.PriceRuleTypeCategoryDiscount, DiscountPercent: 10}, false},
+		{"unknown type", &models.PriceRule{Type: "Other"}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.rule.Validate(); (err == nil) != test.valid {
+				t.Errorf("expected valid to be %t, got %v", test.valid, err)
+			}
+		})
+	}
+}
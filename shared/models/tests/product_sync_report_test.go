@@ -0,0 +1,27 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+func TestProductSyncReportShouldSave(t *testing.T) {
+	tests := []struct {
+		name     string
+		report   *models.ProductSyncReport
+		fullSync bool
+		want     bool
+	}{
+		{"clean partial sync", &models.ProductSyncReport{Updated: 1}, false, false},
+		{"clean full sync", &models.ProductSyncReport{Updated: 1}, true, true},
+		{"partial sync with issues", &models.ProductSyncReport{Issues: []*models.ProductSyncIssue{{}}}, false, true},
+		{"partial sync with rejections", &models.ProductSyncReport{Rejected: 1}, false, true},
+		{"partial sync with failed writes", &models.ProductSyncReport{Failed: 1}, false, true},
+	}
+	for _, tt := range tests {
+		if got := tt.report.ShouldSave(tt.fullSync); got != tt.want {
+			t.Errorf("%s: ShouldSave(%v) = %v, want %v", tt.name, tt.fullSync, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+func TestGetTotalUnits(t *testing.T) {
+	product := &models.Product{Quantity: 3, PackOf: 6}
+	if product.GetTotalUnits() != 18 {
+		t.Errorf("expected 18 units, got %d", product.GetTotalUnits())
+	}
+	// A missing pack size counts as a pack of 1
+	product.PackOf = 0
+	if product.GetTotalUnits() != 3 {
+		t.Errorf("expected 3 units, got %d", product.GetTotalUnits())
+	}
+
+	order := &models.Order{Items: []*models.Product{{Quantity: 2, PackOf: 4}, {Quantity: 1, PackOf: 12}}}
+	if order.GetFormattedTotalUnits() != "20" {
+		t.Errorf("expected 20 total units, got %s", order.GetFormattedTotalUnits())
+	}
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/utils"
+)
+
+func TestGetDistanceInMeters(t *testing.T) {
+	// One degree of latitude is about 111.2 km
+	distance := utils.GetDistanceInMeters(36, -115, 37, -115)
+	if math.Abs(distance-111195) > 10 {
+		t.Errorf("expected about 111195 m, got %f", distance)
+	}
+	if utils.GetDistanceInMeters(36.1716, -115.1391, 36.1716, -115.1391) != 0 {
+		t.Error("expected 0 m between the same coordinates")
+	}
+}
+
+func createProofOfDeliveryTestDelivery(location *models.GeoLocation) *models.Delivery {
+	return &models.Delivery{
+		Order: &models.Order{
+			ID:       "order-1",
+			TimeZone: "America/Los_Angeles",
+			Customer: &models.Customer{ID: "1", Latitude: 36.1716, Longitude: -115.1391},
+		},
+		DriverUID: "driver-1",
+		Location:  location,
+	}
+}
+
+func TestNewProofOfDeliveryLocationStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		location *models.GeoLocation
+		expected string
+	}{
+		{"about 120 m away", &models.GeoLocation{Latitude: 36.1723, Longitude: -115.1401}, constants.DeliveryLocationVerified},
+		{"about 1.1 km away", &models.GeoLocation{Latitude: 36.1816, Longitude: -115.1391}, constants.DeliveryLocationOutsideRadius},
+		{"outside the radius by less than the accuracy", &models.GeoLocation{Latitude: 36.1750, Longitude: -115.1391, Accuracy: 100}, constants.DeliveryLocationVerified},
+		{"no location", nil, constants.DeliveryLocationUnverified},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			proof := models.NewProofOfDelivery(createProofOfDeliveryTestDelivery(test.location), 300)
+			if proof.LocationStatus != test.expected {
+				t.Errorf("expected %s, got %s (%f m)", test.expected, proof.LocationStatus, proof.DistanceMeters)
+			}
+			if proof.OrderID != "order-1" || proof.DriverUID != "driver-1" || proof.CustomerID != "1" {
+				t.Errorf("expected the order, driver and customer ids to be copied, got %+v", proof)
+			}
+		})
+	}
+}
+
+func TestNewProofOfDeliveryWithoutCustomerLocation(t *testing.T) {
+	delivery := createProofOfDeliveryTestDelivery(&models.GeoLocation{Latitude: 36.1723, Longitude: -115.1401})
+	delivery.Order.Customer.Latitude, delivery.Order.Customer.Longitude = 0, 0
+	proof := models.NewProofOfDelivery(delivery, 300)
+	if proof.LocationStatus != constants.DeliveryLocationUnverified || proof.DistanceMeters != -1 {
+		t.Errorf("expected an unverified location when the customer address is not geocoded, got %s (%f m)", proof.LocationStatus, proof.DistanceMeters)
+	}
+}
+
+func TestGeoLocationValidate(t *testing.T) {
+	if err := (&models.GeoLocation{Latitude: 36.17, Longitude: -115.14, Accuracy: 5}).Validate(); err != nil {
+		t.Errorf("expected a valid location, got %v", err)
+	}
+	if err := (&models.GeoLocation{Latitude: 91, Longitude: 0}).Validate(); err == nil {
+		t.Error("expected an error for a latitude above 90")
+	}
+	if err := (&models.GeoLocation{Latitude: 0, Longitude: 0, Accuracy: -1}).Validate(); err == nil {
+		t.Error("expected an error for a negative accuracy")
+	}
+}
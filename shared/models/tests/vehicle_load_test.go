@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+func TestProductDensityAwareConversion(t *testing.T) {
+	tests := []struct {
+		name    string
+		product *models.Product
+		gallons float64
+		pounds  float64
+	}{
+		{"gallons of water", &models.Product{Size: 5, SizeUnit: "GAL", Quantity: 2}, 10, 83.4},
+		{"gallons with a density", &models.Product{Size: 5, SizeUnit: "gal", Quantity: 2, Density: 10}, 10, 100},
+		{"pounds with a density", &models.Product{Size: 50, SizeUnit: "LB", Quantity: 2, Density: 12.5}, 8, 100},
+		{"pounds of water", &models.Product{Size: 8.34, SizeUnit: "lbs", Quantity: 1}, 1, 8.34},
+		{"quarts", &models.Product{Size: 4, SizeUnit: "QT", Quantity: 1, Density: 9}, 1, 9},
+		{"ounces are weight", &models.Product{Size: 32, SizeUnit: "OZ", Quantity: 1, Density: 10}, 0.2, 2},
+		{"fluid ounces are volume", &models.Product{Size: 64, SizeUnit: "fl. oz", Quantity: 2, Density: 10}, 1, 10},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gallons, err := test.product.GetVolumeInGallons()
+			if err != nil || math.Abs(gallons-test.gallons) > 1e-9 {
+				t.Errorf("expected %f gal, got %f (%v)", test.gallons, gallons, err)
+			}
+			pounds, err := test.product.GetWeightInPounds()
+			if err != nil || math.Abs(pounds-test.pounds) > 1e-9 {
+				t.Errorf("expected %f lb, got %f (%v)", test.pounds, pounds, err)
+			}
+		})
+	}
+}
+
+func TestProductUnknownUnit(t *testing.T) {
+	product := &models.Product{SKU: "ACM-1", Size: 5, SizeUnit: "BUCKET", Quantity: 2, Hazardous: true}
+	if _, err := product.GetWeightInPounds(); err == nil {
+		t.Error("expected an error for an unknown unit")
+	}
+	if weight := product.GetFormattedTotalWeight(); weight != "Unknown unit" {
+		t.Errorf("expected the weight to be an unknown unit, got %s", weight)
+	}
+
+	order := createLoadTestOrder("1", 10)
+	order.Items = append(order.Items, product)
+	if order.ValidateUnits() == nil {
+		t.Error("expected the order to have an item with an unknown unit")
+	}
+	load := models.NewVehicleLoad(&models.Vehicle{IsActive: true, Placarded: true}, time.Now(), []*models.Order{order})
+	if !load.IsBlocked() || load.HazardousWeightLbs != 10 {
+		t.Errorf("expected the load to be blocked with the known items only, got %f lb and %+v", load.HazardousWeightLbs, load.Issues)
+	}
+}
+
+func createLoadTestOrder(id string, hazardousPounds float64) *models.Order {
+	return &models.Order{
+		ID: id,
+		Items: []*models.Product{
+			{Size: hazardousPounds, SizeUnit: "LB", Quantity: 1, Hazardous: true},
+			{Size: 100, SizeUnit: "LB", Quantity: 1},
+		},
+	}
+}
+
+func TestNewVehicleLoad(t *testing.T) {
+	tests := []struct {
+		name             string
+		vehicle          *models.Vehicle
+		orders           []*models.Order
+		blocked          bool
+		warnings         int
+		requiresPlacards bool
+	}{
+		{
+			name:    "below the placarding threshold",
+			vehicle: &models.Vehicle{Name: "Van", IsActive: true},
+			orders:  []*models.Order{createLoadTestOrder("1", 500), createLoadTestOrder("2", 500)},
+		},
+		{
+			name:             "combined orders cross the threshold on a non placarded vehicle",
+			vehicle:          &models.Vehicle{Name: "Van", IsActive: true},
+			orders:           []*models.Order{createLoadTestOrder("1", 600), createLoadTestOrder("2", 600)},
+			blocked:          true,
+			requiresPlacards: true,
+		},
+		{
+			name:             "threshold crossed on a placarded vehicle",
+			vehicle:          &models.Vehicle{Name: "Truck", IsActive: true, Placarded: true},
+			orders:           []*models.Order{createLoadTestOrder("1", 600), createLoadTestOrder("2", 600)},
+			warnings:         1,
+			requiresPlacards: true,
+		},
+		{
+			name:    "payload exceeded",
+			vehicle: &models.Vehicle{Name: "Van", IsActive: true, MaxWeightLbs: 1000},
+			orders:  []*models.Order{createLoadTestOrder("1", 450), createLoadTestOrder("2", 450)},
+			blocked: true,
+		},
+		{
+			name:             "hazardous limit exceeded",
+			vehicle:          &models.Vehicle{Name: "Truck", IsActive: true, Placarded: true, MaxHazardousWeightLbs: 1000, PlacardThresholdLbs: 2000},
+			orders:           []*models.Order{createLoadTestOrder("1", 1500)},
+			blocked:          true,
+			requiresPlacards: false,
+		},
+		{
+			name:    "vehicle out of service",
+			vehicle: &models.Vehicle{Name: "Van"},
+			orders:  []*models.Order{createLoadTestOrder("1", 10)},
+			blocked: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			load := models.NewVehicleLoad(test.vehicle, time.Now(), test.orders)
+			if load.IsBlocked() != test.blocked || (load.Validate() != nil) != test.blocked {
+				t.Errorf("expected blocked to be %t, got the issues %+v", test.blocked, load.Issues)
+			}
+			if len(load.GetWarnings()) != test.warnings {
+				t.Errorf("expected %d warnings, got %v", test.warnings, load.GetWarnings())
+			}
+			if load.RequiresPlacards != test.requiresPlacards {
+				t.Errorf("expected requires placards to be %t with %f lb", test.requiresPlacards, load.HazardousWeightLbs)
+			}
+		})
+	}
+}
+
+func TestNewVehicleLoadSumsTheOrders(t *testing.T) {
+	load := models.NewVehicleLoad(&models.Vehicle{IsActive: true}, time.Now(), []*models.Order{createLoadTestOrder("1", 200), createLoadTestOrder("2", 300)})
+	if load.HazardousWeightLbs != 500 || load.WeightLbs != 700 {
+		t.Errorf("expected 500 lb hazardous out of 700 lb, got %f out of %f", load.HazardousWeightLbs, load.WeightLbs)
+	}
+	expectedVolume := 700 / constants.DefaultDensityLbsPerGallon
+	if math.Abs(load.VolumeGal-expectedVolume) > 1e-9 {
+		t.Errorf("expected %f gal, got %f", expectedVolume, load.VolumeGal)
+	}
+	if len(load.OrderIDs) != 2 {
+		t.Errorf("expected the 2 orders in the load, got %v", load.OrderIDs)
+	}
+}
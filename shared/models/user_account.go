@@ -2,6 +2,8 @@ package models
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/utils"
@@ -15,15 +17,34 @@ type UserAccountCreate struct {
 	Customers []string `json:"customers"`
 	Brands    []string `json:"brands"`
 	Role      string   `json:"role"`
+
+	GrantedPermissions []string `json:"grantedPermissions"` // Optional overrides of the permissions of the role
+	RevokedPermissions []string `json:"revokedPermissions"`
 }
 
 func (c *UserAccountCreate) ToFirestoreMap() map[string]any {
 	return map[string]any{
-		"name":      c.Name,
-		"email":     c.Email,
-		"customers": c.Customers,
-		"brands":    c.Brands,
-		"role":      c.Role,
+		"name":               c.Name,
+		"email":              c.Email,
+		"customers":          c.Customers,
+		"brands":             c.Brands,
+		"role":               c.Role,
+		"grantedPermissions": c.GrantedPermissions,
+		"revokedPermissions": c.RevokedPermissions,
+	}
+}
+
+// ToUserAccount returns the account stored for the user, used to set the custom claims of the new user, see
+// services.SetNewUserClaims.
+func (c *UserAccountCreate) ToUserAccount() *UserAccount {
+	return &UserAccount{
+		Name:               c.Name,
+		Email:              c.Email,
+		Customers:          c.Customers,
+		Brands:             c.Brands,
+		Role:               c.Role,
+		GrantedPermissions: c.GrantedPermissions,
+		RevokedPermissions: c.RevokedPermissions,
 	}
 }
 
@@ -39,27 +60,59 @@ func (c *UserAccountCreate) Validate() error {
 	if c.Password == "" {
 		return errors.New("Password of the user cannot be empty")
 	}
-	if len(c.Customers) == 0 {
+	if err := validateUserAssignments(c.Customers, c.Brands, c.Role); err != nil {
+		return err
+	}
+	return ValidatePermissionOverrides(c.GrantedPermissions, c.RevokedPermissions)
+}
+
+// validateUserAssignments validates the customers, brands and role assigned to a new user.
+func validateUserAssignments(customers, brands []string, role string) error {
+	if len(customers) == 0 {
 		return errors.New("At least one customer is required for the user")
 	}
-	if utils.HasDuplicateStrings(c.Customers) {
+	if utils.HasDuplicateStrings(customers) {
 		return errors.New("Customers of the user cannot have duplicates")
 	}
-	if len(c.Brands) == 0 {
+	if len(brands) == 0 {
 		return errors.New("Brands of the user cannot be empty")
 	}
-	if utils.HasDuplicateStrings(c.Brands) {
+	if utils.HasDuplicateStrings(brands) {
 		return errors.New("Brands of the user cannot have duplicates")
 	}
-	if c.Role == "" {
+	if role == "" {
 		return errors.New("Role of the user cannot be empty")
 	}
-	if _, ok := constants.Roles[c.Role]; !ok {
+	if _, ok := constants.Roles[role]; !ok {
 		return errors.New("Role of the user is not valid")
 	}
 	return nil
 }
 
+// ValidateBrands checks the brands of the user are active brands of the catalog and replaces them with the
+// display names of the brands, the names products are stored with.
+func (c *UserAccountCreate) ValidateBrands(catalog *BrandCatalog) error {
+	brands, err := resolveUserBrands(catalog, c.Brands)
+	if err != nil {
+		return err
+	}
+	c.Brands = brands
+	return nil
+}
+
+// resolveUserBrands returns the display names of the brands assigned to a new user.
+func resolveUserBrands(catalog *BrandCatalog, names []string) ([]string, error) {
+	brands, err := catalog.ResolveNames(names)
+	if err != nil {
+		return nil, err
+	}
+	//Two aliases of the same brand
+	if utils.HasDuplicateStrings(brands) {
+		return nil, errors.New("Brands of the user cannot have duplicates")
+	}
+	return brands, nil
+}
+
 // Used when storing/retrieving from Firestore (no password)
 type UserAccount struct {
 	Name      string   `json:"name" firestore:"name"`
@@ -67,4 +120,118 @@ type UserAccount struct {
 	Customers []string `json:"customers" firestore:"customers"`
 	Brands    []string `json:"brands" firestore:"brands"`
 	Role      string   `json:"role" firestore:"role"`
+
+	PriceChangeNotices bool `json:"priceChangeNotices" firestore:"priceChangeNotices"` // Admin opted in to the price change notice emails
+
+	GrantedPermissions []string `json:"grantedPermissions" firestore:"grantedPermissions"` // Added to the permissions of the role
+	RevokedPermissions []string `json:"revokedPermissions" firestore:"revokedPermissions"` // Removed from the permissions of the role
+}
+
+// GetRolePermissions returns the sorted permissions of a role, the super admin has every permission.
+func GetRolePermissions(role string) []string {
+	if role == constants.RoleSuperAdmin {
+		permissions := make([]string, 0, len(constants.Permissions))
+		for permission := range constants.Permissions {
+			permissions = append(permissions, permission)
+		}
+		sort.Strings(permissions)
+		return permissions
+	}
+	permissions := append([]string{}, constants.RolePermissions[role]...)
+	sort.Strings(permissions)
+	return permissions
+}
+
+// ValidatePermissionOverrides checks the overrides are known permissions, without duplicates and not both
+// granted and revoked.
+func ValidatePermissionOverrides(granted, revoked []string) error {
+	if utils.HasDuplicateStrings(granted) || utils.HasDuplicateStrings(revoked) {
+		return errors.New("Permissions of the user cannot have duplicates")
+	}
+	for _, permission := range append(append([]string{}, granted...), revoked...) {
+		if _, ok := constants.Permissions[permission]; !ok {
+			return fmt.Errorf("Permission %s is not valid", permission)
+		}
+	}
+	if utils.HasDuplicateStrings(append(append([]string{}, granted...), revoked...)) {
+		return errors.New("A permission cannot be both granted and revoked")
+	}
+	return nil
+}
+
+// GetPermissions returns the sorted permissions of the role with the granted permissions added and the
+// revoked ones removed. The revoked permissions win, the super admin cannot lose a permission.
+func (u *UserAccount) GetPermissions() []string {
+	set := make(map[string]bool)
+	for _, permission := range GetRolePermissions(u.Role) {
+		set[permission] = true
+	}
+	for _, permission := range u.GrantedPermissions {
+		set[permission] = true
+	}
+	if u.Role != constants.RoleSuperAdmin {
+		for _, permission := range u.RevokedPermissions {
+			delete(set, permission)
+		}
+	}
+	permissions := make([]string, 0, len(set))
+	for permission := range set {
+		permissions = append(permissions, permission)
+	}
+	sort.Strings(permissions)
+	return permissions
+}
+
+func (u *UserAccount) HasPermission(permission string) bool {
+	for _, p := range u.GetPermissions() {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ToClaims returns the firebase custom claims of the user, see constants.ClaimRole.
+func (u *UserAccount) ToClaims() map[string]any {
+	return map[string]any{
+		constants.ClaimRole:        u.Role,
+		constants.ClaimPermissions: u.GetPermissions(),
+	}
+}
+
+// MergeClaims returns the existing custom claims of the firebase user with the claims of the account set,
+// setting the custom claims replaces all of them so the claims set by other services must be kept.
+func (u *UserAccount) MergeClaims(existing map[string]any) map[string]any {
+	claims := make(map[string]any, len(existing)+2)
+	for key, value := range existing {
+		claims[key] = value
+	}
+	for key, value := range u.ToClaims() {
+		claims[key] = value
+	}
+	return claims
+}
+
+// RemoveAccountClaims returns the existing custom claims of the firebase user without the claims set from
+// their account, see UserAccount.ToClaims.
+func RemoveAccountClaims(existing map[string]any) map[string]any {
+	claims := make(map[string]any, len(existing))
+	for key, value := range existing {
+		if key != constants.ClaimRole && key != constants.ClaimPermissions {
+			claims[key] = value
+		}
+	}
+	return claims
+}
+
+// ReplaceCustomer returns the customers of the user with a merged customer replaced by the surviving one,
+// the surviving customer is listed once.
+func (u *UserAccount) ReplaceCustomer(mergedID, survivingID string) []string {
+	customers := make([]string, 0, len(u.Customers))
+	for _, customerID := range u.Customers {
+		if customerID != mergedID && customerID != survivingID {
+			customers = append(customers, customerID)
+		}
+	}
+	return append(customers, survivingID)
 }
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+)
+
+// Vehicle is a truck of the fleet with its capacity and the hazardous materials it can carry.
+type Vehicle struct {
+	ID                    string    `json:"id" firestore:"id"`
+	Name                  string    `json:"name" firestore:"name"` // E.g. the plate of the truck
+	IsActive              bool      `json:"isActive" firestore:"isActive"`
+	MaxWeightLbs          float64   `json:"maxWeightLbs" firestore:"maxWeightLbs"`                   // Payload capacity, 0 if not limited
+	MaxVolumeGal          float64   `json:"maxVolumeGal" firestore:"maxVolumeGal"`                   // 0 if not limited
+	MaxHazardousWeightLbs float64   `json:"maxHazardousWeightLbs" firestore:"maxHazardousWeightLbs"` // 0 if only limited by the payload
+	PlacardThresholdLbs   float64   `json:"placardThresholdLbs" firestore:"placardThresholdLbs"`     // 0 for constants.DefaultPlacardThresholdLbs
+	Placarded             bool      `json:"placarded" firestore:"placarded"`                         // Carries placards and is driven by hazmat endorsed drivers
+	CreatedAt             time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt             time.Time `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// GetPlacardThresholdLbs returns the hazardous weight from which the vehicle must display placards.
+func (v *Vehicle) GetPlacardThresholdLbs() float64 {
+	if v.PlacardThresholdLbs > 0 {
+		return v.PlacardThresholdLbs
+	}
+	return constants.DefaultPlacardThresholdLbs
+}
+
+// VehicleLoadIssue is a limit of the vehicle crossed by its load.
+type VehicleLoadIssue struct {
+	Severity string `json:"severity"` // See the VehicleLoadIssue constants
+	Message  string `json:"message"`
+}
+
+// VehicleLoad is the combined load of the orders assigned to a vehicle on a day, checked against the limits of
+// the vehicle.
+type VehicleLoad struct {
+	VehicleID          string              `json:"vehicleId"`
+	DeliveryDate       time.Time           `json:"deliveryDate"`
+	OrderIDs           []string            `json:"orderIds"`
+	WeightLbs          float64             `json:"weightLbs"`
+	VolumeGal          float64             `json:"volumeGal"`
+	HazardousWeightLbs float64             `json:"hazardousWeightLbs"`
+	RequiresPlacards   bool                `json:"requiresPlacards"`
+	Issues             []*VehicleLoadIssue `json:"issues"`
+}
+
+// NewVehicleLoad sums the load of the orders and checks it against the limits of the vehicle. Crossing the
+// placarding threshold is a warning on a placarded vehicle and blocks the load on any other vehicle. Items with
+// an unknown unit of measure block the load.
+//
+// Parameters:
+//   - vehicle: the vehicle the orders are loaded on
+//   - deliveryDate: the day of the deliveries
+//   - orders: every order assigned to the vehicle on the day, with their items
+//
+// Returns:
+//   - *VehicleLoad: the load with the issues found, see IsBlocked
+func NewVehicleLoad(vehicle *Vehicle, deliveryDate time.Time, orders []*Order) *VehicleLoad {
+	load := &VehicleLoad{
+		VehicleID:    vehicle.ID,
+		DeliveryDate: time.Date(deliveryDate.Year(), deliveryDate.Month(), deliveryDate.Day(), 0, 0, 0, 0, time.UTC),
+		OrderIDs:     make([]string, 0, len(orders)),
+		Issues:       make([]*VehicleLoadIssue, 0),
+	}
+	for _, order := range orders {
+		load.OrderIDs = append(load.OrderIDs, order.ID)
+		load.WeightLbs += order.GetNetWeightInPounds()
+		load.VolumeGal += order.GetNetWeight()
+		load.HazardousWeightLbs += order.GetNetHazardousWeightInPounds()
+		//The weights leave out the items with an unknown unit, the load can not be trusted without them
+		if err := order.ValidateUnits(); err != nil {
+			load.addIssue(constants.VehicleLoadIssueBlocking, "%s", err.Error())
+		}
+	}
+	load.check(vehicle)
+	return load
+}
+
+func (l *VehicleLoad) check(vehicle *Vehicle) {
+	if !vehicle.IsActive {
+		l.addIssue(constants.VehicleLoadIssueBlocking, "%s is not in service", vehicle.Name)
+	}
+	if vehicle.MaxWeightLbs > 0 && l.WeightLbs > vehicle.MaxWeightLbs {
+		l.addIssue(constants.VehicleLoadIssueBlocking, "The load of %.2f lb exceeds the capacity of %s (%.2f lb)", l.WeightLbs, vehicle.Name, vehicle.MaxWeightLbs)
+	}
+	if vehicle.MaxVolumeGal > 0 && l.VolumeGal > vehicle.MaxVolumeGal {
+		l.addIssue(constants.VehicleLoadIssueBlocking, "The load of %.2f gal exceeds the capacity of %s (%.2f gal)", l.VolumeGal, vehicle.Name, vehicle.MaxVolumeGal)
+	}
+	if vehicle.MaxHazardousWeightLbs > 0 && l.HazardousWeightLbs > vehicle.MaxHazardousWeightLbs {
+		l.addIssue(constants.VehicleLoadIssueBlocking, "The hazardous load of %.2f lb exceeds the hazardous limit of %s (%.2f lb)", l.HazardousWeightLbs, vehicle.Name, vehicle.MaxHazardousWeightLbs)
+	}
+
+	threshold := vehicle.GetPlacardThresholdLbs()
+	l.RequiresPlacards = l.HazardousWeightLbs >= threshold
+	switch {
+	case !l.RequiresPlacards:
+	case vehicle.Placarded:
+		l.addIssue(constants.VehicleLoadIssueWarning, "The hazardous load of %.2f lb requires placards (%.0f lb or more), display them on %s", l.HazardousWeightLbs, threshold, vehicle.Name)
+	default:
+		l.addIssue(constants.VehicleLoadIssueBlocking, "The hazardous load of %.2f lb requires placards (%.0f lb or more) and %s is not placarded", l.HazardousWeightLbs, threshold, vehicle.Name)
+	}
+}
+
+func (l *VehicleLoad) addIssue(severity, format string, args ...any) {
+	l.Issues = append(l.Issues, &VehicleLoadIssue{Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+// IsBlocked reports whether the load can not go out on the vehicle.
+func (l *VehicleLoad) IsBlocked() bool {
+	for _, issue := range l.Issues {
+		if issue.Severity == constants.VehicleLoadIssueBlocking {
+			return true
+		}
+	}
+	return false
+}
+
+// GetWarnings returns the messages of the issues that do not block the load.
+func (l *VehicleLoad) GetWarnings() []string {
+	warnings := make([]string, 0)
+	for _, issue := range l.Issues {
+		if issue.Severity == constants.VehicleLoadIssueWarning {
+			warnings = append(warnings, issue.Message)
+		}
+	}
+	return warnings
+}
+
+// Validate returns the blocking issues of the load as a single error, nil if the load can go out.
+func (l *VehicleLoad) Validate() error {
+	messages := make([]string, 0)
+	for _, issue := range l.Issues {
+		if issue.Severity == constants.VehicleLoadIssueBlocking {
+			messages = append(messages, issue.Message)
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(messages, ". "))
+}
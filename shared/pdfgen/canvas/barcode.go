@@ -0,0 +1,99 @@
+package canvas
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+	"github.com/phpdave11/gofpdf"
+)
+
+// Number of image pixels drawn per barcode module. Barcodes are scaled by whole modules so the bars
+// stay sharp when the pdf scales the image.
+const barcodeModulePixels = 4
+
+// BarcodeElement is a machine readable code drawn on the pdf.
+//
+// Note:
+//   - Width and Height are the size of the code on the page. For QR codes only Width is used since they are square
+//   - ShowContent draws the content as text centered below a Code128 barcode
+type BarcodeElement struct {
+	X, Y        float64
+	Width       float64
+	Height      float64
+	Content     string
+	ShowContent bool
+}
+
+// DrawCode128 draws a Code128 barcode of the content.
+//
+// Returns:
+//   - error: if the content can not be encoded, e.g. non ASCII characters
+func (c *Canvas) DrawCode128(element *BarcodeElement) error {
+	if element.Content == "" {
+		return nil
+	}
+	code, err := code128.Encode(element.Content)
+	if err != nil {
+		return fmt.Errorf("Error encoding code128 barcode %q: %v", element.Content, err)
+	}
+	if err := c.drawBarcode(code, "code128", element.X, element.Y, element.Width, element.Height); err != nil {
+		return err
+	}
+	if element.ShowContent {
+		text := &Text{
+			Content: element.Content,
+			Font:    c.FontFamily,
+			Size:    7,
+			X:       element.X,
+			Y:       element.Y + element.Height + 3,
+			Color:   Black,
+		}
+		c.DrawMultipleLines(text, element.Width, "C")
+	}
+	return nil
+}
+
+// DrawQRCode draws a square QR code of the content with medium error correction.
+//
+// Returns:
+//   - error: if the content is too long to be encoded
+func (c *Canvas) DrawQRCode(element *BarcodeElement) error {
+	if element.Content == "" {
+		return nil
+	}
+	code, err := qr.Encode(element.Content, qr.M, qr.Auto)
+	if err != nil {
+		return fmt.Errorf("Error encoding QR code %q: %v", element.Content, err)
+	}
+	return c.drawBarcode(code, "qr", element.X, element.Y, element.Width, element.Width)
+}
+
+// drawBarcode registers the barcode as a png image and draws it. Codes with the same kind and content are
+// only registered once per pdf.
+func (c *Canvas) drawBarcode(code barcode.Barcode, kind string, x, y, width, height float64) error {
+	bounds := code.Bounds()
+	scaled, err := barcode.Scale(code, bounds.Dx()*barcodeModulePixels, max(bounds.Dy()*barcodeModulePixels, barcodeModulePixels))
+	if err != nil {
+		return err
+	}
+	// The barcodes are 16 bit grayscale which the pdf does not support in png files
+	gray := image.NewGray(scaled.Bounds())
+	draw.Draw(gray, gray.Bounds(), scaled, scaled.Bounds().Min, draw.Src)
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, gray); err != nil {
+		return err
+	}
+
+	options := gofpdf.ImageOptions{ImageType: "PNG"}
+	imageName := fmt.Sprintf("barcode-%s-%s", kind, code.Content())
+	c.PDF.RegisterImageOptionsReader(imageName, options, &buffer)
+	c.PDF.ImageOptions(imageName, x, y, width, height, false, options, 0, "")
+	return c.PDF.Error()
+}
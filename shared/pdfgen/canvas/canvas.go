@@ -2,8 +2,11 @@
 package canvas
 
 import (
+	"fmt"
+
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/company_details"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/fonts"
 	"github.com/phpdave11/gofpdf"
 )
 
@@ -25,6 +28,10 @@ type Canvas struct {
 	BorderHeight     float64
 	MarginLeft       float64 // Margin starting from the border
 	MarginTop        float64
+	BorderColor      [3]int  // Color of the border redrawn on every new page
+	BorderLineWidth  float64 // Line width of the border redrawn on every new page
+	FooterHeight     float64 // Space kept free above the bottom of the border for the footer
+	FontFamily       string  // Embedded UTF-8 font family used by all the text drawn by the layout
 }
 
 // Creates a pointer to new canvas
@@ -33,28 +40,51 @@ type Canvas struct {
 //   - BorderX and BorderY are default positions at which the border is drawn for the pdf. By default I set the values to (8, 8)
 //   - BorderWidth and BorderHeight are the dimensions of the border drawn for the pdf. By default I set the values to (193, 280)
 //   - MarginLeft and MarginTop are the margins from the border. By default I set the values to (15, 15)
+//   - BorderColor and BorderLineWidth are used when a new page is added. By default I set the values to (PrimaryBlue, 0.8)
+//   - FooterHeight is the space kept free for the footer at the bottom of every page. By default I set the value to 10
+//   - FontFamily is registered on the pdf. By default I set the value to fonts.DefaultFamily. A registration
+//     error is kept by the pdf and returned when the pdf is generated
 func NewCanvas(pdf *gofpdf.Fpdf) *Canvas {
+	fonts.Register(pdf, fonts.DefaultFamily)
 	return &Canvas{
-		PDF:          pdf,
-		X:            0,
-		Y:            0,
-		BorderX:      8,
-		BorderY:      8,
-		BorderWidth:  193,
-		BorderHeight: 280,
-		MarginLeft:   15,
-		MarginTop:    15,
+		PDF:             pdf,
+		X:               0,
+		Y:               0,
+		BorderX:         8,
+		BorderY:         8,
+		BorderWidth:     193,
+		BorderHeight:    280,
+		MarginLeft:      15,
+		MarginTop:       15,
+		BorderColor:     PrimaryBlue,
+		BorderLineWidth: 0.8,
+		FooterHeight:    10,
+		FontFamily:      fonts.DefaultFamily,
 	}
 }
 
 /* Setters */
 
-func (c *Canvas) SetBorderX(x float64)      { c.BorderX = x }
-func (c *Canvas) SetBorderY(y float64)      { c.BorderY = y }
-func (c *Canvas) SetMarginLeft(x float64)   { c.MarginLeft = x }
-func (c *Canvas) SetMarginTop(y float64)    { c.MarginTop = y }
-func (c *Canvas) SetBorderWidth(w float64)  { c.BorderWidth = w }
-func (c *Canvas) SetBorderHeight(h float64) { c.BorderHeight = h }
+func (c *Canvas) SetBorderX(x float64)        { c.BorderX = x }
+func (c *Canvas) SetBorderY(y float64)        { c.BorderY = y }
+func (c *Canvas) SetMarginLeft(x float64)     { c.MarginLeft = x }
+func (c *Canvas) SetMarginTop(y float64)      { c.MarginTop = y }
+func (c *Canvas) SetBorderWidth(w float64)    { c.BorderWidth = w }
+func (c *Canvas) SetBorderHeight(h float64)   { c.BorderHeight = h }
+func (c *Canvas) SetBorderColor(color [3]int) { c.BorderColor = color }
+
+// SetFontFamily registers the font family on the pdf and uses it for all the text drawn by the canvas.
+// An empty family keeps the current one.
+func (c *Canvas) SetFontFamily(family string) error {
+	if family == "" {
+		return nil
+	}
+	if err := fonts.Register(c.PDF, family); err != nil {
+		return err
+	}
+	c.FontFamily = family
+	return nil
+}
 
 /* Position helpers */
 
@@ -66,20 +96,35 @@ func (c *Canvas) DecY(dy float64)     { c.Y -= dy }
 func (c *Canvas) ResetX()             { c.X = c.MarginLeft }
 func (c *Canvas) ResetY()             { c.Y = c.MarginTop }
 
+// GetContentBottom returns the lowest y position content can be drawn at before the footer.
+func (c *Canvas) GetContentBottom() float64 { return c.BorderY + c.BorderHeight - c.FooterHeight }
+
 /* Reusable Draw Functions */
 
+// DrawBorder draws the outer border of the current page with the canvas border color and line width.
+func (c *Canvas) DrawBorder() {
+	c.DrawRectangle(&Rectangle{
+		X:           c.BorderX,
+		Y:           c.BorderY,
+		Width:       c.BorderWidth,
+		Height:      c.BorderHeight,
+		LineWidth:   c.BorderLineWidth,
+		BorderColor: c.BorderColor,
+	})
+}
+
+// AddPage adds a new page, redraws the border and moves to the top left margin.
+func (c *Canvas) AddPage() {
+	c.PDF.AddPage()
+	c.DrawBorder()
+	c.MoveTo(c.MarginLeft, c.MarginTop)
+}
+
 func (c *Canvas) AddNewPageIfEnd(offest float64, borderColor [3]int, lineWidth float64) {
-	if c.Y+offest > c.BorderHeight {
-		c.PDF.AddPage()
-		c.DrawRectangle(&Rectangle{
-			X:           c.BorderX,
-			Y:           c.BorderY,
-			Width:       c.BorderWidth,
-			Height:      c.BorderHeight,
-			LineWidth:   lineWidth,
-			BorderColor: borderColor,
-		})
-		c.MoveTo(c.MarginLeft, c.MarginTop)
+	if c.Y+offest > c.GetContentBottom() {
+		c.BorderColor = borderColor
+		c.BorderLineWidth = lineWidth
+		c.AddPage()
 	}
 }
 
@@ -99,7 +144,7 @@ func (c *Canvas) DrawCompanyDetails() {
 	for _, line := range lines {
 		c.DrawSingleLineText(&Text{
 			Content: line.text,
-			Font:    "Arial",
+			Font:    c.FontFamily,
 			Style:   line.style,
 			Size:    10,
 			X:       c.X,
@@ -125,7 +170,7 @@ func (c *Canvas) DrawCustomerDetails(customer *models.Customer) {
 	for _, line := range lines {
 		c.DrawSingleLineText(&Text{
 			Content: line.text,
-			Font:    "Arial",
+			Font:    c.FontFamily,
 			Style:   line.style,
 			Size:    10,
 			X:       c.X,
@@ -136,17 +181,38 @@ func (c *Canvas) DrawCustomerDetails(customer *models.Customer) {
 	}
 }
 
+// DrawFooter draws the footer text and a "Page X of Y" number on every page added so far, so it must be
+// called once all the bordered pages are drawn. Pages added afterwards (e.g. full page images) are left without
+// a footer and are not counted.
 func (c *Canvas) DrawFooter(text string) {
-	textElement := &Text{
-		Content: text,
-		Font:    "Arial",
-		Style:   "",
-		Size:    8,
-		X:       c.BorderX,
-		Y:       c.BorderY + c.BorderHeight - 3,
-		Color:   Black,
+	currentPage := c.PDF.PageNo()
+	totalPages := c.PDF.PageCount()
+	y := c.BorderY + c.BorderHeight - 3
+
+	for page := 1; page <= totalPages; page++ {
+		c.PDF.SetPage(page)
+		c.DrawMultipleLines(&Text{
+			Content: text,
+			Font:    c.FontFamily,
+			Style:   "",
+			Size:    8,
+			X:       c.BorderX,
+			Y:       y,
+			Color:   Black,
+		}, c.BorderWidth, "C")
+
+		pageNumber := &Text{
+			Content: fmt.Sprintf("Page %d of %d", page, totalPages),
+			Font:    c.FontFamily,
+			Style:   "",
+			Size:    8,
+			Y:       y - 4,
+			Color:   Black,
+		}
+		pageNumber.SetX(c.BorderX + c.BorderWidth - pageNumber.GetWidth(c.PDF) - 3) // 3 is the padding
+		c.DrawSingleLineText(pageNumber)
 	}
-	c.DrawMultipleLines(textElement, c.BorderWidth, "C")
+	c.PDF.SetPage(currentPage)
 }
 
 func (c *Canvas) DrawBillingDetails(labels, values []string, allLablesBold, allValuesBold bool) {
@@ -157,7 +223,7 @@ func (c *Canvas) DrawBillingDetails(labels, values []string, allLablesBold, allV
 	for _, label := range labels {
 		labelText := &Text{
 			Content: label,
-			Font:    "Arial",
+			Font:    c.FontFamily,
 			Style:   "",
 			Size:    10,
 			X:       c.X,
@@ -176,7 +242,7 @@ func (c *Canvas) DrawBillingDetails(labels, values []string, allLablesBold, allV
 	for i, label := range labels {
 		c.DrawSingleLineText(&Text{
 			Content: label,
-			Font:    "Arial",
+			Font:    c.FontFamily,
 			Style:   labelStyle,
 			Size:    10,
 			X:       c.X,
@@ -190,7 +256,7 @@ func (c *Canvas) DrawBillingDetails(labels, values []string, allLablesBold, allV
 		}
 		c.DrawSingleLineText(&Text{
 			Content: values[i],
-			Font:    "Arial",
+			Font:    c.FontFamily,
 			Style:   valueStyle,
 			Size:    10,
 			X:       c.X,
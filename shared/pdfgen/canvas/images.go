@@ -2,11 +2,11 @@ package canvas
 
 import (
 	"bytes"
+	"crypto/sha1"
 	"fmt"
 	"image"
 	"math"
 	"strings"
-	"time"
 
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/utils"
 	"github.com/phpdave11/gofpdf"
@@ -55,7 +55,8 @@ func (c *Canvas) DrawImageFromBytes(image ImageElement) {
 		ReadDpi:               true,
 		AllowNegativePosition: false,
 	}
-	imageName := fmt.Sprintf("%d", time.Now().UnixNano())
+	// Named by the content so the same image is only embedded once and the pdf is the same on every render
+	imageName := fmt.Sprintf("image-%x", sha1.Sum(image.Bytes))
 	c.PDF.RegisterImageOptionsReader(imageName, options, bytes.NewReader(image.Bytes))
 	c.PDF.ImageOptions(imageName, image.X, image.Y, image.Width, image.Height, image.Flow, options, image.Link, image.LinkStr)
 }
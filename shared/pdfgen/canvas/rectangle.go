@@ -18,3 +18,16 @@ func (c *Canvas) DrawRectangle(r *Rectangle){
 	c.PDF.SetFillColor(r.FillColor[0], r.FillColor[1], r.FillColor[2])
 	c.PDF.Rect(r.X, r.Y, r.Width, r.Height, r.Style)
 }
+
+// DrawCheckbox draws an empty square checkbox with its top left corner at (x, y), e.g. for pick lists
+// that are checked off by hand.
+func (c *Canvas) DrawCheckbox(x, y, size float64, color [3]int) {
+	c.DrawRectangle(&Rectangle{
+		X:           x,
+		Y:           y,
+		Width:       size,
+		Height:      size,
+		BorderColor: color,
+		LineWidth:   0.3,
+	})
+}
@@ -61,6 +61,62 @@ func (th *TableHeader) Draw(c *Canvas, t *Text) {
 	})
 }
 
+// tableSegment tracks the part of a table body drawn on the current page. The outer border and the cell
+// borders are drawn per segment so the table is closed properly on every page it spans.
+type tableSegment struct {
+	X, Y        float64
+	Height      float64
+	CellWidths  []float64
+	BorderColor [3]int
+	Thickness   float64
+}
+
+func (s *tableSegment) draw(c *Canvas) {
+	if s.Height <= 0 {
+		return
+	}
+	c.DrawRectangle(&Rectangle{
+		X:           s.X,
+		Y:           s.Y,
+		Width:       utils.CalculateTableCellWidths(s.CellWidths),
+		Height:      s.Height,
+		LineWidth:   s.Thickness,
+		BorderColor: s.BorderColor,
+	})
+	x := s.X
+	for i := range len(s.CellWidths) - 1 {
+		x += s.CellWidths[i]
+		c.DrawLine(&Line{
+			X1:    x,
+			Y1:    s.Y,
+			X2:    x,
+			Y2:    s.Y + s.Height,
+			Color: s.BorderColor,
+			Width: s.Thickness,
+		})
+	}
+}
+
+// breakTablePage closes the table segment on the current page, adds a new page and redraws the header if
+// the table has one. Returns the y position where the body continues on the new page.
+func breakTablePage(c *Canvas, segment *tableSegment, header *TableHeader, headerText *Text) float64 {
+	segment.draw(c)
+	c.AddPage()
+
+	y := c.Y
+	if header != nil && headerText != nil {
+		text := *headerText
+		header.X = segment.X
+		header.Y = y
+		header.Height = 0
+		header.Draw(c, &text)
+		y += header.Height
+	}
+	segment.Y = y
+	segment.Height = 0
+	return y
+}
+
 type TableBody struct {
 	X, Y            float64
 	Rows            [][]string
@@ -69,6 +125,24 @@ type TableBody struct {
 	TextColor       [3]int
 	BorderColor     [3]int
 	BorderThickness float64
+	Header          *TableHeader // Repeated on every new page when set
+	HeaderText      *Text        // Text style used to redraw the header
+	Barcodes        []string     // Optional Code128 content per row drawn below the text of BarcodeColumn. Empty skips the row
+	BarcodeColumn   int
+	Checkboxes      bool // Draws an empty checkbox in the first column of every row, the first column is left empty
+}
+
+const (
+	tableBarcodeHeight = 7 // Height of the optional barcodes drawn in the table rows
+	tableCheckboxSize  = 4 // Size of the optional checkboxes drawn in the table rows
+)
+
+// getRowBarcode returns the barcode content of the row, empty if the row has none.
+func (tb *TableBody) getRowBarcode(row int) string {
+	if row >= len(tb.Barcodes) || tb.BarcodeColumn < 0 || tb.BarcodeColumn >= len(tb.CellWidths) {
+		return ""
+	}
+	return tb.Barcodes[row]
 }
 
 func (tb *TableBody) DrawTableCellsRightBorder(c *Canvas) {
@@ -86,6 +160,9 @@ func (tb *TableBody) DrawTableCellsRightBorder(c *Canvas) {
 	}
 }
 
+// Draw draws the rows of the table. A row that does not fit in the rest of the page is moved to a new
+// page, where the border and the header (if set) are redrawn. tb.Y and tb.Height are updated to the part of
+// the table drawn on the last page.
 func (tb *TableBody) Draw(c *Canvas, t *Text) {
 	x := tb.X
 	y := tb.Y + 3
@@ -93,7 +170,8 @@ func (tb *TableBody) Draw(c *Canvas, t *Text) {
 	t.SetStyle("")
 	t.SetColor(tb.TextColor)
 
-	for _, row := range tb.Rows {
+	segment := &tableSegment{X: tb.X, Y: tb.Y, Height: tb.Height, CellWidths: tb.CellWidths, BorderColor: tb.BorderColor, Thickness: tb.BorderThickness}
+	for i, row := range tb.Rows {
 		var rowHeight float64 = 0
 
 		// First pass to determine rowHeight
@@ -104,40 +182,19 @@ func (tb *TableBody) Draw(c *Canvas, t *Text) {
 		}
 
 		rowHeight += 2.5
+		textHeight := rowHeight
+		barcode := tb.getRowBarcode(i)
+		if barcode != "" {
+			rowHeight += tableBarcodeHeight + 1
+		}
 
-		// Check for page overflow BEFORE drawing the row
-		if y+rowHeight > c.BorderHeight {
-			// Finish current table on this page
-			c.DrawRectangle(&Rectangle{
-				X:           tb.X,
-				Y:           tb.Y,
-				Width:       utils.CalculateTableCellWidths(tb.CellWidths),
-				Height:      tb.Height,
-				LineWidth:   tb.BorderThickness,
-				BorderColor: tb.BorderColor,
-			})
-			tb.DrawTableCellsRightBorder(c)
-
-			// Add new page
-			c.PDF.AddPage()
-
-			// Redraw the outer borders for the new page
-			c.DrawRectangle(&Rectangle{
-				X:           c.BorderX,
-				Y:           c.BorderY,
-				Width:       c.BorderWidth,
-				Height:      c.BorderHeight,
-				LineWidth:   0.8,
-				BorderColor: tb.BorderColor,
-			})
-
-			// Reset positions
-			c.MoveTo(c.MarginLeft, c.MarginTop)
-			tb.X = c.X
-			tb.Y = c.Y
-			x = tb.X
-			y = tb.Y + 3
-			tb.Height = 0
+		// Check for page overflow BEFORE drawing the row. The first row is always drawn to avoid an
+		// endless loop when a single row is taller than the page
+		if i > 0 && y+rowHeight > c.GetContentBottom() {
+			y = breakTablePage(c, segment, tb.Header, tb.HeaderText) + 3
+			t.SetSize(9)
+			t.SetStyle("")
+			t.SetColor(tb.TextColor)
 		}
 
 		// Finally draw the row
@@ -145,25 +202,32 @@ func (tb *TableBody) Draw(c *Canvas, t *Text) {
 			t.SetContent(cell)
 			width := tb.CellWidths[j]
 			t.SetX(x)
-			t.SetY(y + (rowHeight-t.GetMultiTextHeight(c.PDF, width))/2)
+			t.SetY(y + (textHeight-t.GetMultiTextHeight(c.PDF, width))/2)
 			c.DrawMultipleLines(t, width, "C")
+			if tb.Checkboxes && j == 0 {
+				c.DrawCheckbox(x+(width-tableCheckboxSize)/2, y-3+(textHeight-tableCheckboxSize)/2, tableCheckboxSize, tb.BorderColor)
+			}
+			if barcode != "" && j == tb.BarcodeColumn {
+				// A value that can not be encoded (e.g. non ASCII) only leaves the barcode space empty
+				_ = c.DrawCode128(&BarcodeElement{
+					X:       x + 1,
+					Y:       y + textHeight - 3,
+					Width:   width - 2,
+					Height:  tableBarcodeHeight,
+					Content: barcode,
+				})
+			}
 			x += width
 		}
 		// Reset x, and increment y and height to draw the next row
 		x = tb.X
 		y += rowHeight
-		tb.Height += rowHeight
+		segment.Height += rowHeight
 	}
 	// Draw the final table border on the last page
-	c.DrawRectangle(&Rectangle{
-		X:           tb.X,
-		Y:           tb.Y,
-		Width:       utils.CalculateTableCellWidths(tb.CellWidths),
-		Height:      tb.Height,
-		LineWidth:   tb.BorderThickness,
-		BorderColor: tb.BorderColor,
-	})
-	tb.DrawTableCellsRightBorder(c)
+	segment.draw(c)
+	tb.Y = segment.Y
+	tb.Height = segment.Height
 }
 
 type TableCell struct {
@@ -175,6 +239,8 @@ type TableRow struct {
 	Cells []TableCell
 }
 
+// TableBody2 is a table body where each cell can have multiple lines, e.g. one line per order item.
+// A row is always kept together on a single page.
 type TableBody2 struct {
 	X, Y            float64
 	Height          float64
@@ -183,65 +249,77 @@ type TableBody2 struct {
 	CellWidths      []float64
 	BorderThickness float64
 	Rows            []TableRow
+	Header          *TableHeader // Repeated on every new page when set
+	HeaderText      *Text        // Text style used to redraw the header
 }
 
-// TODO: Not complete yet. Y position gets weird when drawing the next row. Also draws the lines of the second last 
-// column with 4 line gap for some reason. Will fix this later
+// getCellHeight returns the height of all the lines of a cell, each line can wrap within the cell width.
+func (tb *TableBody2) getCellHeight(c *Canvas, t *Text, cell TableCell) float64 {
+	height := 0.0
+	for _, line := range cell.Lines {
+		t.SetContent(line)
+		height += t.GetMultiTextHeight(c.PDF, cell.Width) + 1 // 1 is the line gap
+	}
+	return height
+}
+
+// Draw draws the rows of the table. A row that does not fit in the rest of the page is moved to a new
+// page, where the border and the header (if set) are redrawn. tb.Y and tb.Height are updated to the part of
+// the table drawn on the last page.
 func (tb *TableBody2) Draw(c *Canvas, t *Text) {
-	initialX := tb.X
-	initialY := tb.Y + 5
-	yTracker := initialY
+	const padding = 2.5
 	t.SetSize(9)
 	t.SetStyle("")
 	t.SetColor(tb.TextColor)
 
-	for _, row := range tb.Rows {
-		x := initialX
-		startY := yTracker
-		var maxCellHeight float64 = 0.0
-
-		// Draw each cell
+	y := tb.Y
+	segment := &tableSegment{X: tb.X, Y: tb.Y, Height: tb.Height, CellWidths: tb.CellWidths, BorderColor: tb.BorderColor, Thickness: tb.BorderThickness}
+	for i, row := range tb.Rows {
+		rowHeight := 0.0
 		for _, cell := range row.Cells {
-			lineHeight := t.GetMultiTextHeight(c.PDF, cell.Width)
-			cellHeight := float64(len(cell.Lines)) * (lineHeight + 1)
-			if cellHeight > maxCellHeight {
-				maxCellHeight = cellHeight
-			}
+			rowHeight = max(rowHeight, tb.getCellHeight(c, t, cell))
+		}
+		rowHeight += 2 * padding
+
+		if i > 0 && y+rowHeight > c.GetContentBottom() {
+			y = breakTablePage(c, segment, tb.Header, tb.HeaderText)
+			t.SetSize(9)
+			t.SetStyle("")
+			t.SetColor(tb.TextColor)
+		} else if i > 0 {
+			// Separate the multi line rows
+			c.DrawLine(&Line{
+				X1:    tb.X,
+				Y1:    y,
+				X2:    tb.X + utils.CalculateTableCellWidths(tb.CellWidths),
+				Y2:    y,
+				Color: tb.BorderColor,
+				Width: tb.BorderThickness / 2,
+			})
+		}
 
+		x := tb.X
+		for _, cell := range row.Cells {
+			lineY := y + padding + t.GetTextHeight(c.PDF)
 			for _, line := range cell.Lines {
-				if yTracker+lineHeight > c.BorderHeight {
-					// Page break
-					c.PDF.AddPage()
-					c.DrawRectangle(&Rectangle{
-						X:           c.BorderX,
-						Y:           c.BorderY,
-						Width:       c.BorderWidth,
-						Height:      c.BorderHeight,
-						LineWidth:   0.8,
-						BorderColor: tb.BorderColor,
-					})
-					c.MoveTo(c.MarginLeft, c.MarginTop)
-					x = c.X
-					yTracker = c.Y + 5
-					startY = yTracker
-				}
 				t.SetContent(line)
 				t.SetX(x)
-				t.SetY(yTracker)
+				t.SetY(lineY)
 				c.DrawMultipleLines(t, cell.Width, "C")
-				yTracker += lineHeight + 1
+				lineY += t.GetMultiTextHeight(c.PDF, cell.Width) + 1
 			}
-
-			// Reset Y to start of row for next cell
-			yTracker = startY
 			x += cell.Width
 		}
-
-		yTracker += maxCellHeight + 5 
+		y += rowHeight
+		segment.Height += rowHeight
 	}
+	segment.draw(c)
+	tb.Y = segment.Y
+	tb.Height = segment.Height
 }
 
-// Represents a complete table with header and body and width.
+// Represents a complete table with header and body and width. The header is repeated on every page the
+// body spans.
 type Table struct {
 	Header *TableHeader
 	Body   *TableBody
@@ -250,10 +328,18 @@ type Table struct {
 
 // Draws the entire table. Returns the y position where the table ends
 func (tb *Table) Draw(c *Canvas, t *Text) float64 {
+	// Keep the header together with at least one row (10 is roughly the header and a single line row)
+	if tb.Header.Y+10 > c.GetContentBottom() {
+		c.AddPage()
+		tb.Header.Y = c.Y
+	}
+	headerText := *t
 	tb.Header.Draw(c, t)
 
 	tb.Body.Y = tb.Header.Y + tb.Header.Height
 	tb.Body.Height += 1
+	tb.Body.Header = tb.Header
+	tb.Body.HeaderText = &headerText
 	tb.Body.Draw(c, t)
 
 	return tb.Body.Y + tb.Body.Height
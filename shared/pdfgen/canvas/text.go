@@ -1,6 +1,7 @@
 package canvas
 
 import (
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/fonts"
 	"github.com/phpdave11/gofpdf"
 )
 
@@ -54,12 +55,31 @@ func (t *Text) GetAscent(pdf *gofpdf.Fpdf) float64 {
 }
 
 func (t *Text) GetMultiTextHeight(pdf *gofpdf.Fpdf, drawingWidth float64) float64 {
-	t.ApplyTextStyle(pdf)
 	lineHeight := t.GetTextHeight(pdf)
-	lines := pdf.SplitLines([]byte(t.Content), drawingWidth)
+	lines := t.SplitLines(pdf, drawingWidth)
 	return float64(len(lines)) * lineHeight
 }
 
+// SplitLines wraps the content into lines that fit in the width. Embedded UTF-8 fonts are split by runes,
+// core fonts by bytes since they are single byte encoded.
+func (t *Text) SplitLines(pdf *gofpdf.Fpdf, drawingWidth float64) []string {
+	t.ApplyTextStyle(pdf)
+	if fonts.IsEmbedded(t.Font) {
+		lines := pdf.SplitText(t.Content, drawingWidth)
+		if len(lines) == 0 {
+			// Keep the height of an empty cell the same as a single line, like SplitLines does
+			return []string{""}
+		}
+		return lines
+	}
+	byteLines := pdf.SplitLines([]byte(t.Content), drawingWidth)
+	lines := make([]string, len(byteLines))
+	for i, line := range byteLines {
+		lines[i] = string(line)
+	}
+	return lines
+}
+
 /* Canvas methods */
 
 func (c *Canvas) DrawSingleLineText(text *Text) {
@@ -72,12 +92,10 @@ func (c *Canvas) DrawSingleLineText(text *Text) {
 // to draw multiple lines of text in a single row. The caller is responsible for
 // checking new page when creating the row by first checking the row height.
 func (c *Canvas) DrawMultipleLines(t *Text, allowedWidth float64, align string) {
-	t.ApplyTextStyle(c.PDF)
 	lineHeight := t.GetTextHeight(c.PDF)
-	lines := c.PDF.SplitLines([]byte(t.Content), allowedWidth)
+	lines := t.SplitLines(c.PDF, allowedWidth)
 
-	for i, line := range lines {
-		text := string(line)
+	for i, text := range lines {
 		var x float64
 		switch align {
 		case "C":
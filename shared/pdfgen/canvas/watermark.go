@@ -0,0 +1,116 @@
+package canvas
+
+import "math"
+
+var (
+	Red  = [3]int{200, 40, 40}
+	Gray = [3]int{128, 128, 128}
+)
+
+// Watermark is a large semi-transparent text drawn diagonally across the middle of the pages, e.g. DRAFT or
+// CANCELLED.
+type Watermark struct {
+	Text    string
+	Color   [3]int
+	Opacity float64 // Between 0 and 1. Defaults to 0.15
+	Size    float64 // Font size, shrunk when the text does not fit the page. Defaults to 90
+	Angle   float64 // Rotation in degrees, counter clockwise. Defaults to 45
+}
+
+// Stamp is a small semi-transparent boxed text drawn in the top right corner of the pages, e.g. PAID or COPY.
+type Stamp struct {
+	Text    string
+	Color   [3]int
+	Opacity float64 // Between 0 and 1. Defaults to 0.6
+	Size    float64 // Font size. Defaults to 20
+	Angle   float64 // Rotation in degrees, counter clockwise. Defaults to 15
+}
+
+// DrawWatermark draws the watermark on every page drawn so far. Like DrawFooter it must be called once all
+// the pages of the document exist.
+//
+// Params:
+//   - w: the watermark to draw, nothing is drawn if its text is empty
+func (c *Canvas) DrawWatermark(w *Watermark) {
+	if w == nil || w.Text == "" {
+		return
+	}
+	c.drawOnEveryPage(func() {
+		text := &Text{
+			Content: w.Text,
+			Font:    c.FontFamily,
+			Style:   "B",
+			Size:    getOrDefault(w.Size, 90),
+			Color:   w.Color,
+		}
+		angle := getOrDefault(w.Angle, 45)
+		//Shrink the text to 80% of the diagonal of the border so long texts stay inside the page
+		maxWidth := 0.8 * math.Hypot(c.BorderWidth, c.BorderHeight)
+		if width := text.GetWidth(c.PDF); width > maxWidth {
+			text.Size = text.Size * maxWidth / width
+		}
+		centerX := c.BorderX + c.BorderWidth/2
+		centerY := c.BorderY + c.BorderHeight/2
+		text.X = centerX - text.GetWidth(c.PDF)/2
+		text.Y = centerY + text.GetTextHeight(c.PDF)/3 // Baseline so the capital letters are centered
+
+		c.PDF.SetAlpha(getOrDefault(w.Opacity, 0.15), "Normal")
+		c.PDF.TransformBegin()
+		c.PDF.TransformRotate(angle, centerX, centerY)
+		c.DrawSingleLineText(text)
+		c.PDF.TransformEnd()
+		c.PDF.SetAlpha(1, "Normal")
+	})
+}
+
+// DrawStamp draws the stamp in the top right corner inside the border of every page drawn so far. Like
+// DrawFooter it must be called once all the pages of the document exist.
+//
+// Params:
+//   - s: the stamp to draw, nothing is drawn if its text is empty
+func (c *Canvas) DrawStamp(s *Stamp) {
+	if s == nil || s.Text == "" {
+		return
+	}
+	c.drawOnEveryPage(func() {
+		text := &Text{
+			Content: s.Text,
+			Font:    c.FontFamily,
+			Style:   "B",
+			Size:    getOrDefault(s.Size, 20),
+			Color:   s.Color,
+		}
+		padding := 3.0
+		width := text.GetWidth(c.PDF) + 2*padding
+		height := text.GetTextHeight(c.PDF) + 2*padding
+		x := c.BorderX + c.BorderWidth - width - 10
+		y := c.BorderY + 10
+		text.X = x + padding
+		text.Y = y + padding + text.GetTextHeight(c.PDF)*0.75
+
+		c.PDF.SetAlpha(getOrDefault(s.Opacity, 0.6), "Normal")
+		c.PDF.TransformBegin()
+		c.PDF.TransformRotate(getOrDefault(s.Angle, 15), x+width/2, y+height/2)
+		c.DrawRectangle(&Rectangle{X: x, Y: y, Width: width, Height: height, BorderColor: s.Color, LineWidth: 0.8})
+		c.DrawSingleLineText(text)
+		c.PDF.TransformEnd()
+		c.PDF.SetAlpha(1, "Normal")
+	})
+}
+
+// drawOnEveryPage calls draw on every page drawn so far and goes back to the current page.
+func (c *Canvas) drawOnEveryPage(draw func()) {
+	currentPage := c.PDF.PageNo()
+	for page := 1; page <= c.PDF.PageCount(); page++ {
+		c.PDF.SetPage(page)
+		draw()
+	}
+	c.PDF.SetPage(currentPage)
+}
+
+func getOrDefault(value, defaultValue float64) float64 {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,60 @@
+// package fonts embeds the TTF fonts used by the pdfs. Unlike the core pdf fonts (Arial, Helvetica) which only
+// support cp1252, the embedded fonts support UTF-8 so accents, curly quotes and non-latin characters in customer
+// names, addresses and instructions are drawn correctly.
+package fonts
+
+import (
+	"embed"
+	"fmt"
+
+	"github.com/phpdave11/gofpdf"
+)
+
+//go:embed *.ttf
+var fontFiles embed.FS
+
+// Font families available to the layouts.
+const (
+	DejaVuSans    = "DejaVuSans" // DejaVu Sans Condensed, covers latin, greek and cyrillic
+	DefaultFamily = DejaVuSans
+)
+
+// families maps each font family to its TTF file per style ("" regular, "B" bold, "I" italic, "BI" bold italic).
+var families = map[string]map[string]string{
+	DejaVuSans: {
+		"":   "DejaVuSansCondensed.ttf",
+		"B":  "DejaVuSansCondensed-Bold.ttf",
+		"I":  "DejaVuSansCondensed-Oblique.ttf",
+		"BI": "DejaVuSansCondensed-BoldOblique.ttf",
+	},
+}
+
+// IsEmbedded returns true if the family is one of the embedded UTF-8 font families.
+func IsEmbedded(family string) bool {
+	_, ok := families[family]
+	return ok
+}
+
+// Register registers all the styles of an embedded font family on the pdf. Registering the same family
+// twice is a no-op.
+//
+// Params:
+//   - pdf: *gofpdf.Fpdf
+//   - family: one of the font families defined in this package
+//
+// Returns:
+//   - error: if the family is unknown or the font could not be parsed
+func Register(pdf *gofpdf.Fpdf, family string) error {
+	styles, ok := families[family]
+	if !ok {
+		return fmt.Errorf("unknown font family %q", family)
+	}
+	for style, fileName := range styles {
+		fontBytes, err := fontFiles.ReadFile(fileName)
+		if err != nil {
+			return err
+		}
+		pdf.AddUTF8FontFromBytes(family, style, fontBytes)
+	}
+	return pdf.Error()
+}
@@ -0,0 +1,79 @@
+package layout
+
+import (
+	"fmt"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/company_details"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/canvas"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/template"
+)
+
+var (
+	arAgingReportHeaders        = []string{"CUSTOMER", "CURRENT", "1-30 DAYS", "31-60 DAYS", "61-90 DAYS", "OVER 90 DAYS", "TOTAL"}
+	arAgingReportTableColWidths = []float64{54, 21, 21, 21, 21, 21, 21}
+)
+
+// ARAgingReport is the internal report of the open balance of all the customers split into the current,
+// 1-30, 31-60, 61-90 and over 90 days past due buckets.
+type ARAgingReport struct {
+	AsOf             string
+	TableValues      [][]string
+	TotalCurrent     string
+	TotalOverdue     string
+	TotalOutstanding string
+	FontFamily       string // Optional embedded font family, see package fonts. Defaults to fonts.DefaultFamily
+}
+
+func NewARAgingReport(report *models.ARAgingReport) *ARAgingReport {
+	arAgingReport := &ARAgingReport{
+		AsOf:             report.AsOf.Format("January 2, 2006"),
+		TotalCurrent:     models.FormatAmount(report.Totals.Current),
+		TotalOverdue:     models.FormatAmount(report.Totals.GetOverdue()),
+		TotalOutstanding: models.FormatAmount(report.Totals.GetTotal()),
+	}
+	arAgingReport.setTableValues(report)
+	return arAgingReport
+}
+
+func (ar *ARAgingReport) setTableValues(report *models.ARAgingReport) {
+	tableValues := make([][]string, 0)
+	for _, row := range report.Rows {
+		tableValues = append(tableValues, append([]string{row.Customer.GetFormattedName()}, row.Buckets.GetFormattedValues()...))
+	}
+	tableValues = append(tableValues, append([]string{"TOTAL"}, report.Totals.GetFormattedValues()...))
+	ar.TableValues = tableValues
+}
+
+// ToTemplate returns the declarative layout of the aging report.
+func (ar *ARAgingReport) ToTemplate() *template.Template {
+	return &template.Template{
+		Color:      canvas.PrimaryGreen,
+		FontFamily: ar.FontFamily,
+		Blocks: []template.Block{
+			&template.Header{Title: "AR AGING REPORT", TitleSize: 22, Layout: template.HeaderLogoRight, LogoWidth: 70, Height: 15},
+			&template.Row{Columns: []template.Column{
+				{Width: 120, Blocks: []template.Block{&template.CompanyDetails{}}},
+				{Blocks: []template.Block{
+					&template.Spacer{Height: 25},
+					&template.KeyValues{Pairs: []template.KeyValue{{Label: "As Of:", Value: ar.AsOf}}},
+				}},
+			}},
+			&template.Spacer{Height: 5},
+			&template.Table{Headers: arAgingReportHeaders, CellWidths: arAgingReportTableColWidths, Rows: ar.TableValues, HeaderSize: 9},
+			&template.Spacer{Height: 5},
+			&template.Row{Columns: []template.Column{
+				{Width: 110},
+				{Blocks: []template.Block{&template.Totals{
+					Labels: []string{"CURRENT", "OVERDUE", "TOTAL OUTSTANDING"},
+					Values: []string{ar.TotalCurrent, ar.TotalOverdue, ar.TotalOutstanding},
+				}}},
+			}},
+			&template.Footer{Text: fmt.Sprintf("This report is for internal use only. Generated by %s", company_details.COMPANYNAME)},
+		},
+	}
+}
+
+func (ar *ARAgingReport) RenderToPDF() ([]byte, error) {
+	return ar.ToTemplate().RenderToPDF()
+}
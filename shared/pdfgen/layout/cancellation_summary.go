@@ -4,11 +4,9 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/company_details"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/canvas"
-	pdfutils "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/utils"
-	"github.com/phpdave11/gofpdf"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/template"
 )
 
 type CancellationSummary struct {
@@ -17,6 +15,7 @@ type CancellationSummary struct {
 	Date               string
 	Time               string
 	Total              string
+	FontFamily         string // Optional embedded font family, see package fonts. Defaults to fonts.DefaultFamily
 }
 
 var (
@@ -113,160 +112,42 @@ func getCancelledOrderTotal(orders []*models.Order) string {
 	return fmt.Sprintf("$%.2f", total)
 }
 
-func (cm *CancellationSummary) RenderToPDF() ([]byte, error) {
-
-	pdf := gofpdf.New("L", "mm", "A4", "")
-	pdf.AddPage()
-	pageWidth, pageHeight := pdf.GetPageSize()
-
-	c := canvas.NewCanvas(pdf)
-	c.SetBorderHeight(pageHeight - 6)
-	c.SetBorderWidth(pageWidth - 6)
-	c.SetBorderX(3)
-	c.SetBorderY(3)
-	c.SetMarginLeft(c.BorderX + 5)
-	c.SetMarginTop(c.BorderY + 5)
-
-	//Draw the outer border
-	c.DrawRectangle(&canvas.Rectangle{
-		X:           c.BorderX,
-		Y:           c.BorderY,
-		Width:       c.BorderWidth,
-		Height:      c.BorderHeight,
-		LineWidth:   0.8,
-		BorderColor: canvas.PrimaryBlue,
-	})
-	c.IncY(5)
-	c.IncX(5)
-
-	c.DrawImageFromURL(canvas.ImageElement{
-		URL:    company_details.LOGOPATH,
-		X:      c.X,
-		Y:      c.Y,
-		Width:  60,
-		Height: 0,
-	})
-
-	c.IncY(10)
-	headingText := &canvas.Text{
-		Content: "Cancellation Summary",
-		Size:    16,
-		X:       c.X,
-		Y:       c.Y,
-		Style:   "B",
-		Font:    "Arial",
-		Color:   canvas.PrimaryBlue,
-	}
-	c.MoveTo((pageWidth-headingText.GetWidth(c.PDF))/2, c.Y)
-	headingText.SetX(c.X)
-	c.DrawSingleLineText(headingText)
-
-	c.MoveTo(c.BorderWidth-32, c.Y-5)
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "Date:",
-		Size:    10,
-		X:       c.X,
-		Y:       c.Y,
-		Style:   "B",
-		Font:    "Arial",
-		Color:   canvas.Black,
-	}, cm.Date)
-	c.IncY(5)
-
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "Time:",
-		Size:    10,
-		X:       c.X,
-		Y:       c.Y,
-		Style:   "B",
-		Font:    "Arial",
-		Color:   canvas.Black,
-	}, cm.Time)
-	c.IncY(20)
-	c.MoveTo(c.MarginLeft, c.Y)
-
-	summaryTableHeader := &canvas.TableHeader{
-		X:           c.X,
-		Y:           c.Y,
-		Headers:     CancellationSummaryTableHeaders,
-		CellWidths:  CancellationSummaryTableColWidths,
-		TextColor:   canvas.White,
-		FillColor:   canvas.PrimaryBlue,
-		BorderColor: canvas.PrimaryBlue,
-	}
-	summaryTableHeader.Draw(c, &canvas.Text{
-		Size:    10,
-		Style:   "B",
-		Font:    "Arial",
-		Color:   canvas.White,
-	})
-	summarTableBody := &canvas.TableBody2{
-		X:           c.X,
-		CellWidths:  CancellationSummaryTableColWidths,
-		TextColor:   canvas.Black,
-		BorderColor: canvas.PrimaryBlue,
-		Rows: cm.TableValues,
-	}
-	summarTableBody.Y = summaryTableHeader.Y + summaryTableHeader.Height
-	summarTableBody.Draw(c, &canvas.Text{
-		Size:    9,
-		Font:    "Arial",
-		Color:   canvas.Black,
-	})
-	
-	/*
-	c.AddNewPageIfEnd(30, canvas.PrimaryBlue, 0.8)
-	
-	c.MoveTo(c.MarginLeft, tableEndYPos+10)
-	c.DrawSingleLineText(&canvas.Text{
-		Content: "Summary Section - Totals",
-		Size:    11,
-		X:       c.MarginLeft,
-		Y:       c.Y,
-		Style:   "B",
-		Font:    "Arial",
-		Color:   canvas.Black,
-	})
-	c.IncY(5)
-	c.IncX(45)
-
-	tableEndYPos = (&canvas.Table{
-		Header: &canvas.TableHeader{
-			X:           c.X,
-			Y:           c.Y,
-			Headers:     CancellationTotalSummaryTableHeaders,
-			CellWidths:  CancellationTotalSummaryTableColWidths,
-			TextColor:   canvas.White,
-			FillColor:   canvas.PrimaryBlue,
-			BorderColor: canvas.PrimaryBlue,
-		},
-		Body: &canvas.TableBody{
-			X:           c.X,
-			Y:           c.Y,
-			CellWidths:  CancellationTotalSummaryTableColWidths,
-			Rows:        cm.TotalSummaryValues,
-			BorderColor: canvas.PrimaryBlue,
+// ToTemplate returns the declarative layout of the cancellation summary.
+func (cm *CancellationSummary) ToTemplate() *template.Template {
+	return &template.Template{
+		Orientation: template.OrientationLandscape,
+		Color:       canvas.PrimaryBlue,
+		FontFamily:  cm.FontFamily,
+		Border:      &template.Border{X: 3, Y: 3},
+		Blocks: []template.Block{
+			&template.Header{Title: "Cancellation Summary", TitleSize: 16, Layout: template.HeaderTitleCentered, LogoWidth: 60, Height: 15},
+			&template.Row{Columns: []template.Column{
+				{Width: 245},
+				{Blocks: []template.Block{&template.KeyValues{Pairs: []template.KeyValue{
+					{Label: "Date:", Value: cm.Date},
+					{Label: "Time:", Value: cm.Time},
+				}}}},
+			}},
+			&template.Spacer{Height: 5},
+			&template.Table{Headers: CancellationSummaryTableHeaders, CellWidths: CancellationSummaryTableColWidths, MultiLineRows: cm.TableValues},
+			&template.Spacer{Height: 10},
+			&template.Paragraph{Label: "Summary Section - Totals"},
+			&template.Table{
+				Indent:     45,
+				Headers:    CancellationTotalSummaryTableHeaders,
+				CellWidths: CancellationTotalSummaryTableColWidths,
+				Rows:       cm.TotalSummaryValues,
+			},
+			&template.Spacer{Height: 5},
+			&template.Row{Columns: []template.Column{
+				{Width: 164},
+				{Blocks: []template.Block{&template.KeyValues{Pairs: []template.KeyValue{{Label: "TOTAL AMOUNT CANCELLED:", Value: cm.Total}}}}},
+			}},
+			&template.Footer{Text: "This is an automated document. Please do not reply to this email."},
 		},
-		Width: pdfutils.CalculateTableCellWidths(CancellationTotalSummaryTableColWidths),
-	}).Draw(c, &canvas.Text{
-		Font:  "Helvetica",
-		Size:  10,
-		Style: "B",
-	})
-	c.MoveTo(c.X+119, tableEndYPos+5)
+	}
+}
 
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "TOTAL AMOUNT CANCELLED:",
-		Size:    10,
-		X:       c.X,
-		Color:   canvas.Black,
-		Y:       c.Y,
-		Style:   "B",
-		Font:    "Arial",
-	}, cm.Total)
-	*/
-	c.DrawFooter("This is an automated document. Please do not reply to this email.")
-	//Generate the PDF
-	bytes, err := pdfutils.GetGeneratedPDF(pdf)
-	return bytes, err
-}
\ No newline at end of file
+func (cm *CancellationSummary) RenderToPDF() ([]byte, error) {
+	return cm.ToTemplate().RenderToPDF()
+}
@@ -7,8 +7,7 @@ import (
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/company_details"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/canvas"
-	pdfutils "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/utils"
-	"github.com/phpdave11/gofpdf"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/template"
 )
 
 type Invoice struct {
@@ -24,6 +23,8 @@ type Invoice struct {
 	PaymentDue  string
 	LateFeeDate string
 	CreatedAt   string
+	FontFamily  string // Optional embedded font family, see package fonts. Defaults to fonts.DefaultFamily
+	Stamp       string // Document state stamped on the pages, see the Stamp constants. Set from the order, empty for none
 }
 
 const (
@@ -47,6 +48,7 @@ func NewInvoice(order *models.Order, invoiceNumber string) *Invoice {
 		CreatedAt:   order.GetLocalUpdatedAtTime().Format("January 2, 2006"),
 		PaymentDue:  order.GetLocalUpdatedAtTime().AddDate(0, 0, 30).Format("January 2, 2006"),
 		LateFeeDate: order.GetLocalUpdatedAtTime().AddDate(0, 0, 44).Format("January 2, 2006"),
+		Stamp:       GetInvoiceStamp(order),
 	}
 	invoice.setTableValues(order.Items)
 
@@ -66,200 +68,50 @@ func (i *Invoice) setTableValues(items []*models.Product) {
 	i.TableValues = tableValues
 }
 
-func (i *Invoice) RenderToPDF() ([]byte, error) {
-
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-
-	c := canvas.NewCanvas(pdf)
-	c.MoveTo(c.BorderX, c.BorderY)
-
-	//Draw the outer border
-	c.DrawRectangle(&canvas.Rectangle{
-		X:           c.BorderX,
-		Y:           c.BorderY,
-		Width:       c.BorderWidth,
-		Height:      c.BorderHeight,
-		LineWidth:   0.8,
-		BorderColor: canvas.PrimaryGreen,
-	})
-	c.MoveTo(c.MarginLeft, c.MarginTop+10)
-
-	//Draw the pdf title on top left
-	c.DrawSingleLineText(&canvas.Text{
-		Content: "INVOICE",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    26,
-		Color:   canvas.PrimaryGreen,
-		Style:   "B",
-	})
-	c.IncY(10)
-
-	c.DrawCompanyDetails()
-	companyDetailsEndYPos := c.Y
-	c.MoveTo(125, c.MarginTop)
-
-	//Draw the company logo on top right
-	c.DrawImageFromURL(canvas.ImageElement{
-		URL:    company_details.LOGOPATH,
-		X:      c.X,
-		Y:      c.Y,
-		Width:  70,
-		Height: 0,
-	})
-	c.MoveTo(c.MarginLeft, companyDetailsEndYPos+5)
-
-	c.DrawSingleLineText(&canvas.Text{
-		Content: "Bill To",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	})
-	c.IncY(5)
-
-	c.DrawCustomerDetails(i.Customer)
-	c.MoveTo(135, companyDetailsEndYPos+5)
-
-	//Invoice No
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "Invoice No:",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	}, i.Number)
-	c.IncY(5)
-
-	//Invoice Date
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "Invoice Date:",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	}, i.CreatedAt)
-	c.IncY(5)
-
-	//Payment Due
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "Payment Due:",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	}, i.PaymentDue)
-	c.IncY(5)
-
-	//Late Fee Due
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "Late Fee Date:",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	}, i.LateFeeDate)
-
-	c.IncY(25)
-	c.ResetX()
-
-	tableEndYPos := (&canvas.Table{
-		Header: &canvas.TableHeader{
-			X:           c.X,
-			Y:           c.Y,
-			Headers:     invoiceTableHeaders,
-			CellWidths:  invoiceTableColWidths,
-			TextColor:   canvas.White,
-			FillColor:   canvas.PrimaryGreen,
-			BorderColor: canvas.PrimaryGreen,
-			BorderThickness: 0.8,
-		},
-		Body: &canvas.TableBody{
-			X:           c.X,
-			Y:           c.Y,
-			CellWidths:  invoiceTableColWidths,
-			Rows:        i.TableValues,
-			TextColor:   canvas.Black,
-			BorderColor: canvas.PrimaryGreen,
-			BorderThickness: 0.8,
+// ToTemplate returns the declarative layout of the invoice.
+func (i *Invoice) ToTemplate() *template.Template {
+	return &template.Template{
+		Color:      canvas.PrimaryGreen,
+		FontFamily: i.FontFamily,
+		Watermark:  getStampWatermark(i.Stamp),
+		Stamp:      getCornerStamp(i.Stamp),
+		Blocks: []template.Block{
+			&template.Header{Title: "INVOICE", TitleSize: 26, Layout: template.HeaderLogoRight, LogoWidth: 70, Height: 15},
+			&template.CompanyDetails{},
+			&template.Spacer{Height: 5},
+			&template.Row{Columns: []template.Column{
+				{Width: 120, Blocks: []template.Block{&template.CustomerDetails{Customer: i.Customer, Heading: "Bill To"}}},
+				{Blocks: []template.Block{&template.KeyValues{Pairs: []template.KeyValue{
+					{Label: "Invoice No:", Value: i.Number},
+					{Label: "Invoice Date:", Value: i.CreatedAt},
+					{Label: "Payment Due:", Value: i.PaymentDue},
+					{Label: "Late Fee Date:", Value: i.LateFeeDate},
+				}}}},
+			}},
+			&template.Spacer{Height: 15},
+			&template.Table{Headers: invoiceTableHeaders, CellWidths: invoiceTableColWidths, Rows: i.TableValues},
+			&template.Spacer{Height: 5},
+			&template.Row{Columns: []template.Column{
+				{Width: 120},
+				{Blocks: []template.Block{&template.Totals{
+					Labels: []string{"SUBTOTAL", fmt.Sprintf("TAX (%s)", i.TaxRate), "TOTAL"},
+					Values: []string{i.SubTotal, i.TaxAmount, i.Total},
+				}}},
+			}},
+			&template.Spacer{Height: 5},
+			&template.Paragraph{
+				Label:   "Notes",
+				Content: fmt.Sprintf("A late fee of %s will be charged if the invoice is not paid within the late fee date. Continued non-payment may result in suspension of services and additional collection actions.", i.LateFee),
+				Size:    10,
+				Width:   100,
+			},
+			&template.Spacer{Height: 5},
+			&template.Paragraph{Label: "Terms & Conditions", Content: TermsAndConditions, Size: 10, Width: 100},
+			&template.Footer{Text: fmt.Sprintf("If you have any questions or concerns about this invoice please contact us at %s", company_details.COMPANYEMAIL)},
 		},
-		Width: pdfutils.CalculateTableCellWidths(shippingTableCellWidths),
-	}).Draw(c, &canvas.Text{
-		Font:  "Helvetica",
-		Size:  10,
-		Style: "B",
-		Color: canvas.White,
-	})
-	c.MoveTo(c.MarginLeft, tableEndYPos+5)
-
-	//Check if we need to add a new page (3 labels with a gap of 10px each, so 30px total)
-	c.AddNewPageIfEnd(30, canvas.PrimaryGreen, 0.8)
-
-	c.IncX(127)
-	c.DrawBillingDetails([]string{"SUBTOTAL", fmt.Sprintf("TAX (%s)", i.TaxRate), "TOTAL"}, []string{i.SubTotal, i.TaxAmount, i.Total}, false, false)
-	c.MoveTo(c.MarginLeft, c.Y+5)
-
-	//Check if we need to add a new page (3 labels with a gap of 10px each, so 30px total)
-	c.AddNewPageIfEnd(25, canvas.PrimaryGreen, 0.8)
-	c.DrawSingleLineText(&canvas.Text{
-		Content: "Notes",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	})
-	c.IncY(5)
-
-	//Check if we need to add a new page (3 labels with a gap of 10px each, so 30px total)
-	c.AddNewPageIfEnd(25, canvas.PrimaryGreen, 0.8)
-	c.DrawMultipleLines(&canvas.Text{
-		Content: fmt.Sprintf("A late fee of %s will be charged if the invoice is not paid within the late fee date. Continued non-payment may result in suspension of services and additional collection actions.", i.LateFee),
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "",
-	}, 100, "")
-	c.IncY(15)
-
-	c.DrawSingleLineText(&canvas.Text{
-		Content: "Terms & Conditions",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	})
-	c.IncY(5)
-	c.DrawMultipleLines(&canvas.Text{
-		Content: TermsAndConditions,
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "",
-	}, 100, "")
-
-	c.DrawFooter(fmt.Sprintf("If you have any questions or concerns about this invoice please contact us at %s", company_details.COMPANYEMAIL))
+	}
+}
 
-	//Generate the PDF
-	bytes, err := pdfutils.GetGeneratedPDF(c.PDF)
-	return bytes, err
+func (i *Invoice) RenderToPDF() ([]byte, error) {
+	return i.ToTemplate().RenderToPDF()
 }
@@ -3,11 +3,9 @@ package layout
 import (
 	"fmt"
 
-	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/company_details"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/canvas"
-	pdfutils "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/utils"
-	"github.com/phpdave11/gofpdf"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/template"
 )
 
 type OrderRevenueReport struct {
@@ -23,6 +21,8 @@ type OrderRevenueReport struct {
 	TaxRate     string
 	Revenue     string
 	CreatedAt   string
+	FontFamily  string // Optional embedded font family, see package fonts. Defaults to fonts.DefaultFamily
+	Stamp       string // Document state stamped on the pages, see the Stamp constants. Set from the order, empty for none
 }
 
 var (
@@ -43,6 +43,7 @@ func NewOrderRevenueReport(order *models.Order, invoiceNo string) *OrderRevenueR
 		TaxRate:    order.GetFormattedTaxRate(),
 		Revenue:    order.GetFormattedTotalRevenue(),
 		CreatedAt:  order.GetLocalUpdatedAtTime().Format("January 2, 2006"),
+		Stamp:      GetOrderStamp(order),
 	}
 	orderRevenue.CreateTableValues(order)
 	return orderRevenue
@@ -56,140 +57,39 @@ func (orr *OrderRevenueReport) CreateTableValues(order *models.Order) {
 	orr.TableValues = tableValues
 }
 
-func (orr *OrderRevenueReport) RenderToPDF() ([]byte, error) {
-
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-
-	c := canvas.NewCanvas(pdf)
-	c.MoveTo(c.BorderX, c.BorderY)
-
-	//Draw the outer border
-	c.DrawRectangle(&canvas.Rectangle{
-		X:           c.BorderX,
-		Y:           c.BorderY,
-		Width:       c.BorderWidth,
-		Height:      c.BorderHeight,
-		LineWidth:   0.8,
-		BorderColor: canvas.PrimaryGreen,
-	})
-	c.MoveTo(c.MarginLeft, c.MarginTop+10)
-
-	//Draw the company logo on top left
-	c.DrawSingleLineText(&canvas.Text{
-		Content: "REVENUE REPORT",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    24,
-		Color:   canvas.PrimaryGreen,
-		Style:   "B",
-	})
-	c.IncY(10)
-
-	c.DrawCompanyDetails()
-	companyDetailsEndYPos := c.Y
-	c.MoveTo(125, c.MarginTop)
-
-	//Draw the company logo on top right
-	c.DrawImageFromURL(canvas.ImageElement{
-		URL:    company_details.LOGOPATH,
-		X:      c.X,
-		Y:      c.Y,
-		Width:  70,
-		Height: 0,
-	})
-	c.MoveTo(c.MarginLeft, companyDetailsEndYPos+5)
-
-	c.DrawSingleLineText(&canvas.Text{
-		Content: "Customer Details",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	})
-	c.IncY(5)
-
-	c.DrawCustomerDetails(orr.Customer)
-	c.MoveTo(135, companyDetailsEndYPos+5)
-
-	//Invoice No
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "Invoice No:",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	}, orr.InvoiceNo)
-	c.IncY(5)
-
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "Order No:",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	}, orr.OrderNo)
-	c.IncY(5)
-
-	//Invoice Date
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "Created At:",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	}, orr.CreatedAt)
-	c.IncY(5)
-
-	c.IncY(25)
-	c.ResetX()
-
-	//Draw the table
-	tableEndYPos := (&canvas.Table{
-		Header: &canvas.TableHeader{
-			X:           c.X,
-			Y:           c.Y,
-			Headers:     orderRevenueReportHeaders,
-			CellWidths:  orderRevenueReportTableColWidths,
-			TextColor:   canvas.White,
-			FillColor:   canvas.PrimaryGreen,
-			BorderColor: canvas.PrimaryGreen,
-			BorderThickness: 0.8,
-		},
-		Body: &canvas.TableBody{
-			X:           c.X,
-			Y:           c.Y,
-			CellWidths:  orderRevenueReportTableColWidths,
-			Rows:        orr.TableValues,
-			TextColor:   canvas.Black,
-			BorderColor: canvas.PrimaryGreen,
-			BorderThickness: 0.8,
+// ToTemplate returns the declarative layout of the revenue report.
+func (orr *OrderRevenueReport) ToTemplate() *template.Template {
+	return &template.Template{
+		Color:      canvas.PrimaryGreen,
+		FontFamily: orr.FontFamily,
+		Watermark:  getStampWatermark(orr.Stamp),
+		Stamp:      getCornerStamp(orr.Stamp),
+		Blocks: []template.Block{
+			&template.Header{Title: "REVENUE REPORT", Layout: template.HeaderLogoRight, LogoWidth: 70, Height: 15},
+			&template.CompanyDetails{},
+			&template.Spacer{Height: 5},
+			&template.Row{Columns: []template.Column{
+				{Width: 120, Blocks: []template.Block{&template.CustomerDetails{Customer: orr.Customer, Heading: "Customer Details"}}},
+				{Blocks: []template.Block{&template.KeyValues{Pairs: []template.KeyValue{
+					{Label: "Invoice No:", Value: orr.InvoiceNo},
+					{Label: "Order No:", Value: orr.OrderNo},
+					{Label: "Created At:", Value: orr.CreatedAt},
+				}}}},
+			}},
+			&template.Spacer{Height: 15},
+			&template.Table{Headers: orderRevenueReportHeaders, CellWidths: orderRevenueReportTableColWidths, Rows: orr.TableValues},
+			&template.Spacer{Height: 5},
+			&template.Row{Columns: []template.Column{
+				{Width: 100},
+				{Blocks: []template.Block{&template.Totals{
+					Labels: []string{"CASH", "COST OF GOODS", "SALES", fmt.Sprintf("SALES TAX AMOUNT (%s)", orr.TaxRate), "TOTAL REVENUE"},
+					Values: []string{orr.Cash, orr.COG, orr.TotalSales, orr.SalesTax, orr.Revenue},
+				}}},
+			}},
 		},
-		Width: pdfutils.CalculateTableCellWidths(orderRevenueReportTableColWidths),
-	}).Draw(c, &canvas.Text{
-		Font:  "Helvetica",
-		Size:  10,
-		Style: "B",
-		Color: canvas.White,
-	})
-	c.MoveTo(c.MarginLeft, tableEndYPos+5)
-
-	//Check if a new page is needed or not
-	c.AddNewPageIfEnd(10, canvas.PrimaryGreen, 0.8)
-
-	c.IncX(108)
-	c.DrawBillingDetails([]string{"CASH", "COST OF GOODS", "SALES", fmt.Sprintf("SALES TAX AMOUNT (%s)", orr.TaxRate), "TOTAL REVENUE"}, []string{orr.Cash, orr.COG, orr.TotalSales, orr.SalesTax, orr.Revenue}, false, false)
+	}
+}
 
-	//Generate the PDF
-	bytes, err := pdfutils.GetGeneratedPDF(c.PDF)
-	return bytes, err
+func (orr *OrderRevenueReport) RenderToPDF() ([]byte, error) {
+	return orr.ToTemplate().RenderToPDF()
 }
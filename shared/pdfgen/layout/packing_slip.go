@@ -0,0 +1,96 @@
+package layout
+
+import (
+	"fmt"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/company_details"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/canvas"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/template"
+)
+
+var (
+	packingSlipTableHeaders    = []string{"SKU", "DESCRIPTION", "PACKS", "UNITS"}
+	packingSlipTableCellWidths = []float64{30, 100, 25, 25}
+)
+
+// PackingSlip is the customer facing list of the items shipped in an order. Unlike the purchase order and
+// the invoice it has no prices since it is put inside the package.
+type PackingSlip struct {
+	OrderID             string
+	Customer            *models.Customer
+	SpecialInstructions string
+	TableValues         [][]string
+	TotalPacks          string
+	TotalUnits          string
+	CreatedAt           string
+	FontFamily          string // Optional embedded font family, see package fonts. Defaults to fonts.DefaultFamily
+	Stamp               string // Document state stamped on the pages, see the Stamp constants. Set from the order, empty for none
+}
+
+func NewPackingSlip(o *models.Order) *PackingSlip {
+	packingSlip := &PackingSlip{
+		OrderID:             o.ID,
+		Customer:            o.Customer,
+		SpecialInstructions: o.SpecialInstructions,
+		TotalPacks:          o.GetFormattedTotalItems(),
+		TotalUnits:          o.GetFormattedTotalUnits(),
+		CreatedAt:           o.GetLocalCreatedAtTime().Format("January 2, 2006"),
+		Stamp:               GetOrderStamp(o),
+	}
+	packingSlip.getTableValues(o.Items)
+	return packingSlip
+}
+
+func (p *PackingSlip) getTableValues(items []*models.Product) {
+	tableValues := make([][]string, 0)
+	for _, item := range items {
+		tableValues = append(tableValues, []string{
+			item.SKU,
+			item.GetFormattedDescription(),
+			item.GetFormattedQuantity(),
+			item.GetFormattedTotalUnits(),
+		})
+	}
+	p.TableValues = tableValues
+}
+
+// ToTemplate returns the declarative layout of the packing slip.
+func (p *PackingSlip) ToTemplate() *template.Template {
+	return &template.Template{
+		Color:      canvas.PrimaryBlue,
+		FontFamily: p.FontFamily,
+		Watermark:  getStampWatermark(p.Stamp),
+		Stamp:      getCornerStamp(p.Stamp),
+		Blocks: []template.Block{
+			&template.Header{Title: "PACKING SLIP"},
+			&template.Row{Columns: []template.Column{
+				{Width: 120, Blocks: []template.Block{&template.CompanyDetails{}}},
+				{Blocks: []template.Block{
+					&template.KeyValues{Pairs: []template.KeyValue{
+						{Label: "Date:", Value: p.CreatedAt},
+						{Label: "Order #:", Value: p.OrderID},
+					}},
+					//Order Number barcode for the receiving docks
+					&template.Barcode{Content: p.OrderID, Width: 55, Height: 10, ShowContent: true},
+				}},
+			}},
+			&template.Spacer{Height: 5},
+			&template.CustomerDetails{Customer: p.Customer, Heading: "SHIP TO", FilledHeading: true},
+			&template.Spacer{Height: 5},
+			&template.Table{Headers: packingSlipTableHeaders, CellWidths: packingSlipTableCellWidths, Rows: p.TableValues},
+			&template.Spacer{Height: 5},
+			&template.Row{Columns: []template.Column{
+				{Width: 60, Blocks: []template.Block{&template.KeyValues{Pairs: []template.KeyValue{{Label: "Total Packs:", Value: p.TotalPacks}}}}},
+				{Blocks: []template.Block{&template.KeyValues{Pairs: []template.KeyValue{{Label: "Total Units:", Value: p.TotalUnits}}}}},
+			}},
+			&template.Spacer{Height: 5},
+			&template.Paragraph{Label: "Comments or Special Instructions:", Content: p.SpecialInstructions, Width: 170},
+			&template.Footer{Text: fmt.Sprintf("Thank you for your business! If any item is missing or damaged please contact us at %s", company_details.COMPANYEMAIL)},
+		},
+	}
+}
+
+func (p *PackingSlip) RenderToPDF() ([]byte, error) {
+	return p.ToTemplate().RenderToPDF()
+}
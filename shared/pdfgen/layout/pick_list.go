@@ -0,0 +1,131 @@
+package layout
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/canvas"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/template"
+)
+
+var (
+	pickListTableHeaders    = []string{"PICKED", "CATEGORY", "SKU", "DESCRIPTION", "PACKS", "UNITS", "HM"}
+	pickListTableCellWidths = []float64{14, 28, 28, 62, 15, 15, 12}
+)
+
+// PickList is the warehouse document used to pick the items of an order. The lines are sorted by category
+// (the warehouse stores the products by category) so the picker walks the warehouse once.
+type PickList struct {
+	OrderID             string
+	Customer            *models.Customer
+	SpecialInstructions string
+	TableValues         [][]string
+	TotalPacks          string
+	TotalUnits          string
+	CreatedAt           string
+	FontFamily          string // Optional embedded font family, see package fonts. Defaults to fonts.DefaultFamily
+	Stamp               string // Document state stamped on the pages, see the Stamp constants. Set from the order, empty for none
+}
+
+func NewPickList(o *models.Order) *PickList {
+	pickList := &PickList{
+		OrderID:             o.ID,
+		Customer:            o.Customer,
+		SpecialInstructions: o.SpecialInstructions,
+		TotalPacks:          o.GetFormattedTotalItems(),
+		TotalUnits:          o.GetFormattedTotalUnits(),
+		CreatedAt:           o.GetLocalCreatedAtTime().Format("January 2, 2006 at 3:04 PM"),
+		Stamp:               GetOrderStamp(o),
+	}
+	pickList.getTableValues(o.Items)
+	return pickList
+}
+
+// sortPickListItems returns a copy of the items sorted by category, brand, name and sku. Items without a
+// category are picked last.
+func sortPickListItems(items []*models.Product) []*models.Product {
+	sorted := make([]*models.Product, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Category != b.Category {
+			if a.Category == "" || b.Category == "" {
+				return b.Category == ""
+			}
+			return a.Category < b.Category
+		}
+		if a.Brand != b.Brand {
+			return a.Brand < b.Brand
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.SKU < b.SKU
+	})
+	return sorted
+}
+
+func (p *PickList) getTableValues(items []*models.Product) {
+	tableValues := make([][]string, 0)
+	for _, item := range sortPickListItems(items) {
+		hazardous := ""
+		if item.Hazardous {
+			hazardous = "HM"
+		}
+		tableValues = append(tableValues, []string{
+			"", //Checkbox
+			item.Category,
+			item.SKU,
+			item.GetFormattedDescription(),
+			item.GetFormattedQuantity(),
+			item.GetFormattedTotalUnits(),
+			hazardous,
+		})
+	}
+	p.TableValues = tableValues
+}
+
+// ToTemplate returns the declarative layout of the pick list.
+func (p *PickList) ToTemplate() *template.Template {
+	return &template.Template{
+		Color:      canvas.PrimaryBlue,
+		FontFamily: p.FontFamily,
+		Watermark:  getStampWatermark(p.Stamp),
+		Stamp:      getCornerStamp(p.Stamp),
+		Blocks: []template.Block{
+			&template.Header{Title: "PICK LIST"},
+			&template.Row{Columns: []template.Column{
+				{Width: 120, Blocks: []template.Block{&template.CustomerDetails{Customer: p.Customer, Heading: "Ship To"}}},
+				{Blocks: []template.Block{
+					&template.KeyValues{Pairs: []template.KeyValue{
+						{Label: "Date:", Value: p.CreatedAt},
+						{Label: "Order #:", Value: p.OrderID},
+					}},
+					//Order Number barcode so the picked order can be scanned
+					&template.Barcode{Content: p.OrderID, Width: 55, Height: 10, ShowContent: true},
+				}},
+			}},
+			&template.Spacer{Height: 5},
+			&template.Table{Headers: pickListTableHeaders, CellWidths: pickListTableCellWidths, Rows: p.TableValues, HeaderSize: 9, Checkboxes: true},
+			&template.Spacer{Height: 5},
+			&template.Row{Columns: []template.Column{
+				{Width: 60, Blocks: []template.Block{&template.KeyValues{Pairs: []template.KeyValue{{Label: "Total Packs:", Value: p.TotalPacks}}}}},
+				{Blocks: []template.Block{&template.KeyValues{Pairs: []template.KeyValue{{Label: "Total Units:", Value: p.TotalUnits}}}}},
+			}},
+			&template.Spacer{Height: 5},
+			&template.Paragraph{Label: "Comments or Special Instructions:", Content: p.SpecialInstructions, Width: 170},
+			&template.Spacer{Height: 10},
+			//Sign off lines for the picker and the checker
+			&template.Row{Columns: []template.Column{
+				{Width: 90, Blocks: []template.Block{&template.Signature{Label: "PICKED BY:", Width: 80}}},
+				{Blocks: []template.Block{&template.Signature{Label: "CHECKED BY:", Width: 80}}},
+			}},
+			&template.Footer{Text: fmt.Sprintf("Pick list for order %s. Hazardous (HM) items must be packed and labeled separately.", p.OrderID)},
+		},
+	}
+}
+
+func (p *PickList) RenderToPDF() ([]byte, error) {
+	return p.ToTemplate().RenderToPDF()
+}
@@ -6,8 +6,7 @@ import (
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/company_details"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/canvas"
-	pdfutils "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/utils"
-	"github.com/phpdave11/gofpdf"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/template"
 )
 
 var (
@@ -28,6 +27,8 @@ type PurchaseOrder struct {
 	SubTotal            string
 	Total               string
 	CreatedAt           string
+	FontFamily          string // Optional embedded font family, see package fonts. Defaults to fonts.DefaultFamily
+	Stamp               string // Document state stamped on the pages, see the Stamp constants. Set from the order, empty for none
 }
 
 func NewPurchaseOrder(o *models.Order) *PurchaseOrder {
@@ -40,6 +41,7 @@ func NewPurchaseOrder(o *models.Order) *PurchaseOrder {
 		SubTotal:            o.GetFormattedSubTotal(),
 		Total:               o.GetFormattedTotal(),
 		CreatedAt:           o.GetLocalUpdatedAtTime().Format("January 2, 2006 at 3:04 PM"),
+		Stamp:               GetOrderStamp(o),
 	}
 	purchaseOrder.getTableValues(o.Items)
 	return purchaseOrder
@@ -59,218 +61,48 @@ func (p *PurchaseOrder) getTableValues(items []*models.Product) {
 	p.TableValues = tableValues
 }
 
-func (p *PurchaseOrder) RenderToPDF() ([]byte, error) {
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-
-	c := canvas.NewCanvas(pdf)
-	c.MoveTo(c.BorderX, c.BorderY)
-
-	//Draw the outer border
-	c.DrawRectangle(&canvas.Rectangle{
-		X:           c.BorderX,
-		Y:           c.BorderY,
-		Width:       c.BorderWidth,
-		Height:      c.BorderHeight,
-		LineWidth:   0.8,
-		BorderColor: canvas.PrimaryBlue,
-	})
-	c.MoveTo(c.MarginLeft, 10)
-
-	//Draw the company logo on top left
-	c.DrawImageFromURL(canvas.ImageElement{
-		URL:    company_details.LOGOPATH,
-		X:      c.X,
-		Y:      c.Y,
-		Width:  65,
-		Height: 0,
-	})
-	c.IncX(100)
-	c.IncY(25)
-
-	//Draw the PDF Name on top right side
-	c.DrawSingleLineText(&canvas.Text{
-		Content: "PURCHASE ORDER",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    24,
-		Color:   canvas.PrimaryBlue,
-		Style:   "B",
-	})
-	c.ResetX()
-	c.IncY(7)
-
-	c.DrawCompanyDetails()
-	c.IncX(120)
-	c.DecY(30)
-
-	//Draw the Date
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "Date:",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	}, p.CreatedAt)
-	c.IncY(5)
-
-	//Draw the PO Number
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "P.O. #:",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	}, p.ID)
-	c.ResetX()
-	c.IncY(25)
-
-	//Draw the Vendor Heading
-	c.DrawTextInColoredRect(&canvas.Text{
-		Content: "VENDOR",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.White,
-		Style:   "B",
-	}, &canvas.Rectangle{
-		X:         c.X,
-		Y:         c.Y,
-		Width:     75,
-		Style:     "F",
-		Height:    6,
-		LineWidth: 0.8,
-		FillColor: canvas.PrimaryBlue,
-	}, "left")
-	c.IncY(11)
-	//Vendor Details
-	c.DrawCompanyDetails()
-	c.DecY(41)
-	c.IncX(c.X + 90)
-
-	//Draw Ship To Heading
-	c.DrawTextInColoredRect(&canvas.Text{
-		Content: "SHIP TO",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.White,
-		Style:   "B",
-	}, &canvas.Rectangle{
-		X:         c.X,
-		Y:         c.Y,
-		Width:     75,
-		Style:     "F",
-		Height:    6,
-		LineWidth: 0.8,
-		FillColor: canvas.PrimaryBlue,
-	}, "left")
-	c.IncY(11)
-	//Ship To Details
-	c.DrawCustomerDetails(p.Customer)
-	c.IncY(5)
-	c.ResetX()
-
-	//Draw the shipping table
-	tableEndYPos := (&canvas.Table{
-		Header: &canvas.TableHeader{
-			X:           c.X,
-			Y:           c.Y,
-			Headers:     shippingTableHeaders,
-			CellWidths:  shippingTableCellWidths,
-			TextColor:   canvas.White,
-			FillColor:   canvas.PrimaryBlue,
-			BorderColor: canvas.PrimaryBlue,
-			BorderThickness: 0.8,
+// ToTemplate returns the declarative layout of the purchase order.
+func (p *PurchaseOrder) ToTemplate() *template.Template {
+	return &template.Template{
+		Color:      canvas.PrimaryBlue,
+		FontFamily: p.FontFamily,
+		Watermark:  getStampWatermark(p.Stamp),
+		Stamp:      getCornerStamp(p.Stamp),
+		Blocks: []template.Block{
+			&template.Header{Title: "PURCHASE ORDER"},
+			&template.Row{Columns: []template.Column{
+				{Width: 120, Blocks: []template.Block{&template.CompanyDetails{}}},
+				{Blocks: []template.Block{
+					&template.KeyValues{Pairs: []template.KeyValue{
+						{Label: "Date:", Value: p.CreatedAt},
+						{Label: "P.O. #:", Value: p.ID},
+					}},
+					//PO Number barcode for the receiving docks
+					&template.Barcode{Content: p.ID, Width: 55, Height: 10, ShowContent: true},
+				}},
+			}},
+			&template.Spacer{Height: 5},
+			&template.Row{Columns: []template.Column{
+				{Width: 90, Blocks: []template.Block{&template.CompanyDetails{Heading: "VENDOR", FilledHeading: true}}},
+				{Blocks: []template.Block{&template.CustomerDetails{Customer: p.Customer, Heading: "SHIP TO", FilledHeading: true}}},
+			}},
+			&template.Spacer{Height: 5},
+			&template.Table{Headers: shippingTableHeaders, CellWidths: shippingTableCellWidths, Rows: shippingTableValues},
+			&template.Spacer{Height: 5},
+			&template.Table{Headers: productTableHeaders, CellWidths: productTableCellWidths, Rows: p.TableValues},
+			&template.Spacer{Height: 5},
+			&template.Row{Columns: []template.Column{
+				{Width: 132, Blocks: []template.Block{&template.Paragraph{Label: "Comments or Special Instructions:", Content: p.SpecialInstructions, Width: 90}}},
+				{Blocks: []template.Block{&template.Totals{
+					Labels: []string{"SUBTOTAL", fmt.Sprintf("TAX (%s)", p.TaxRate), "TOTAL"},
+					Values: []string{p.SubTotal, p.TaxAmount, p.Total},
+				}}},
+			}},
+			&template.Footer{Text: fmt.Sprintf("If you have any questions or concerns about this purchase order please contact us at %s", company_details.COMPANYEMAIL)},
 		},
-		Body: &canvas.TableBody{
-			X:           c.X,
-			Y:           c.Y,
-			CellWidths:  shippingTableCellWidths,
-			Rows:        shippingTableValues,
-			TextColor:   canvas.Black,
-			BorderColor: canvas.PrimaryBlue,
-			BorderThickness: 0.8,
-		},
-		Width: pdfutils.CalculateTableCellWidths(shippingTableCellWidths),
-	}).Draw(c, &canvas.Text{
-		Font:  "Helvetica",
-		Size:  10,
-		Style: "B",
-		Color: canvas.White,
-	})
-	c.MoveTo(c.MarginLeft, tableEndYPos)
-	c.IncY(5)
-
-	//Draw the product table
-	tableEndYPos = (&canvas.Table{
-		Header: &canvas.TableHeader{
-			X:           c.X,
-			Y:           c.Y,
-			Headers:     productTableHeaders,
-			CellWidths:  productTableCellWidths,
-			TextColor:   canvas.White,
-			FillColor:   canvas.PrimaryBlue,
-			BorderColor: canvas.PrimaryBlue,
-			BorderThickness: 0.8,
-		},
-		Body: &canvas.TableBody{
-			X:           c.X,
-			Y:           c.Y,
-			Height:      0,
-			CellWidths:  productTableCellWidths,
-			Rows:        p.TableValues,
-			TextColor:   canvas.Black,
-			BorderColor: canvas.PrimaryBlue,
-			BorderThickness: 0.8,
-		},
-		Width: pdfutils.CalculateTableCellWidths(productTableCellWidths),
-	}).Draw(c, &canvas.Text{
-		Font:  "Helvetica",
-		Size:  10,
-		Style: "B",
-	})
-
-	c.MoveTo(147, tableEndYPos+5)
-	//Check if new page needs to be drawn before drawing the billing section (30px is the height of the label drawn)
-	c.AddNewPageIfEnd(30, canvas.PrimaryBlue, 0.8)
-	c.MoveTo(147, c.Y)
-
-	//Draw the billing section
-	c.DrawBillingDetails([]string{"SUBTOTAL", fmt.Sprintf("TAX (%s)", p.TaxRate), "TOTAL"}, []string{p.SubTotal, p.TaxAmount, p.Total}, false, false)
-	c.MoveTo(c.MarginLeft, c.Y - 15)
-
-	//Comments or Special Instructions label
-	c.DrawSingleLineText(&canvas.Text{
-		Content: "Comments or Special Instructions:",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Style:   "B",
-	})
-	c.IncY(5)
-	//Comments or Special Instructions
-	c.DrawMultipleLines(&canvas.Text{
-		Content: p.SpecialInstructions,
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    9,
-		Style:   "",
-	}, 90, "")
-
-	//Footer
-	c.DrawFooter(fmt.Sprintf("If you have any questions or concerns about this purchase order please contact us at %s", company_details.COMPANYEMAIL))
+	}
+}
 
-	//Generate the PDF
-	bytes, err := pdfutils.GetGeneratedPDF(c.PDF)
-	return bytes, err
+func (p *PurchaseOrder) RenderToPDF() ([]byte, error) {
+	return p.ToTemplate().RenderToPDF()
 }
@@ -0,0 +1,139 @@
+package layout
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/company_details"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/canvas"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/template"
+)
+
+var (
+	routeSheetTableHeaders    = []string{"STOP", "ORDER #", "CUSTOMER", "PACKS", "HM WEIGHT", "RECEIVED BY / SIGNATURE"}
+	routeSheetTableCellWidths = []float64{12, 28, 60, 14, 24, 42}
+	// Empty lines of the signature column, leaving a box tall enough to sign in
+	routeSheetSignatureLines = []string{"", "", "", ""}
+)
+
+// RouteSheet is the driver facing list of the stops of a delivery route, in the order they are delivered,
+// with the hazardous load of the vehicle and a box for the signature of every receiver.
+type RouteSheet struct {
+	RouteID            string
+	DriverName         string
+	Vehicle            string
+	DeliveryDate       string
+	Stops              []canvas.TableRow
+	TotalStops         string
+	TotalPacks         string
+	TotalDistance      string
+	NonHazardousWeight string
+	HazardousWeight    string
+	TotalWeight        string
+	HazardousLoad      string   // Hazardous weight in pounds, checked against the placarding threshold
+	LoadWarnings       []string // Warnings of the vehicle load check, e.g. placards required
+	FontFamily         string   // Optional embedded font family, see package fonts. Defaults to fonts.DefaultFamily
+}
+
+// NewRouteSheet creates the route sheet of a route loaded with the detailed orders of its stops.
+func NewRouteSheet(route *models.DeliveryRoute) *RouteSheet {
+	routeSheet := &RouteSheet{
+		RouteID:            route.ID,
+		DriverName:         route.DriverName,
+		Vehicle:            route.Vehicle,
+		DeliveryDate:       route.DeliveryDate.Format("Monday, January 2, 2006"),
+		TotalStops:         strconv.Itoa(len(route.Stops)),
+		TotalPacks:         strconv.Itoa(route.GetTotalPacks()),
+		TotalDistance:      fmt.Sprintf("%.1f mi", route.GetTotalDistanceMeters()/1609.344),
+		NonHazardousWeight: fmt.Sprintf("%.2f gal", route.GetNonHazardousWeight()),
+		HazardousWeight:    fmt.Sprintf("%.2f gal", route.GetHazardousWeight()),
+		TotalWeight:        fmt.Sprintf("%.2f gal", route.GetNonHazardousWeight()+route.GetHazardousWeight()),
+		HazardousLoad:      fmt.Sprintf("%.2f lb", route.GetHazardousWeightInPounds()),
+		LoadWarnings:       route.LoadWarnings,
+	}
+	routeSheet.getStops(route.Stops)
+	return routeSheet
+}
+
+func (rs *RouteSheet) getStops(stops []*models.RouteStop) {
+	rows := make([]canvas.TableRow, 0, len(stops))
+	for _, stop := range stops {
+		customer := []string{"Unknown"}
+		packs, hazardousWeight := "", ""
+		if order := stop.Order; order != nil {
+			if order.Customer != nil {
+				customer = []string{order.Customer.Name, order.Customer.Address1, order.Customer.FormatAddress2()}
+			}
+			if order.SpecialInstructions != "" {
+				customer = append(customer, "Note: "+order.SpecialInstructions)
+			}
+			packs = order.GetFormattedTotalItems()
+			hazardousWeight = order.GetFormattedNetHazardousWeight()
+		}
+		cells := [][]string{{strconv.Itoa(stop.Sequence)}, {stop.OrderID}, customer, {packs}, {hazardousWeight}, routeSheetSignatureLines}
+		row := canvas.TableRow{Cells: make([]canvas.TableCell, len(cells))}
+		for i, lines := range cells {
+			row.Cells[i] = canvas.TableCell{Lines: lines, Width: routeSheetTableCellWidths[i]}
+		}
+		rows = append(rows, row)
+	}
+	rs.Stops = rows
+}
+
+// ToTemplate returns the declarative layout of the route sheet.
+func (rs *RouteSheet) ToTemplate() *template.Template {
+	tmpl := &template.Template{
+		Color:      canvas.PrimaryBlue,
+		FontFamily: rs.FontFamily,
+		Blocks: []template.Block{
+			&template.Header{Title: "ROUTE SHEET"},
+			&template.Row{Columns: []template.Column{
+				{Width: 110, Blocks: []template.Block{&template.CompanyDetails{}}},
+				{Blocks: []template.Block{
+					&template.KeyValues{Pairs: []template.KeyValue{
+						{Label: "Date:", Value: rs.DeliveryDate},
+						{Label: "Driver:", Value: rs.DriverName},
+						{Label: "Vehicle:", Value: rs.Vehicle},
+					}},
+					//Route barcode scanned by the driver app to open the route
+					&template.Barcode{Content: rs.RouteID, Width: 55, Height: 10, ShowContent: true},
+				}},
+			}},
+			&template.Spacer{Height: 5},
+			&template.Table{Headers: routeSheetTableHeaders, CellWidths: routeSheetTableCellWidths, MultiLineRows: rs.Stops, HeaderSize: 9},
+			&template.Spacer{Height: 5},
+			&template.Row{Columns: []template.Column{
+				{Width: 90, Blocks: []template.Block{&template.KeyValues{Pairs: []template.KeyValue{
+					{Label: "Total Stops:", Value: rs.TotalStops},
+					{Label: "Total Packs:", Value: rs.TotalPacks},
+					{Label: "Distance:", Value: rs.TotalDistance},
+				}}}},
+				//Hazardous load of the vehicle for the shipping papers, in pounds for the placarding threshold
+				{Blocks: []template.Block{&template.Totals{
+					Labels:     []string{"NON HAZARDOUS WEIGHT:", "HAZARDOUS WEIGHT:", "TOTAL WEIGHT:", "HAZARDOUS LOAD:"},
+					Values:     []string{rs.NonHazardousWeight, rs.HazardousWeight, rs.TotalWeight, rs.HazardousLoad},
+					BoldLabels: true,
+					BoldValues: true,
+				}}},
+			}},
+		},
+	}
+	if len(rs.LoadWarnings) > 0 {
+		tmpl.Blocks = append(tmpl.Blocks, &template.Paragraph{Label: "LOAD WARNINGS:", Content: strings.Join(rs.LoadWarnings, "\n")})
+	}
+	tmpl.Blocks = append(tmpl.Blocks,
+		&template.Spacer{Height: 10},
+		&template.Row{Columns: []template.Column{
+			{Width: 90, Blocks: []template.Block{&template.Signature{Label: "DRIVER SIGNATURE:", Width: 80}}},
+			{Blocks: []template.Block{&template.Signature{Label: "DISPATCHER SIGNATURE:", Width: 85}}},
+		}},
+		&template.Footer{Text: fmt.Sprintf("Deliver the stops in order. Report any missing or damaged item to %s", company_details.COMPANYEMAIL)},
+	)
+	return tmpl
+}
+
+func (rs *RouteSheet) RenderToPDF() ([]byte, error) {
+	return rs.ToTemplate().RenderToPDF()
+}
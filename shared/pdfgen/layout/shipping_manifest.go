@@ -1,15 +1,14 @@
 package layout
 
 import (
-	"bytes"
 	"fmt"
-	"image"
+	"net/url"
+	"strings"
 
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/company_details"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/canvas"
-	pdfutils "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/utils"
-	"github.com/phpdave11/gofpdf"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/template"
 )
 
 var (
@@ -30,9 +29,16 @@ type ShippingManifest struct {
 	TotalWeight          string
 	ReceivedBy           string
 	Signature            []byte
-	DeliverImages        [][]byte
+	DeliverImages        [][]byte // Compressed thumbnails, see models.DeliveryThumbnailCompression
+	DeliveryImageURLs    []string // Links to the full resolution originals, opened when a thumbnail is clicked
+	ShowContactSheet     bool     // Draws the delivery images larger on a grid after the manifest
 	TableValues          [][]string
 	DeliveredAt          string
+	ProofOfDelivery      []template.KeyValue // GPS location, location check and capture times, empty if no proof of delivery was recorded
+	ProofOfDeliveryURL   string              // Encoded in the QR code printed next to the order barcode
+	ShowSKUBarcodes      bool                // Draws a Code128 barcode of the SKU in every product row
+	FontFamily           string              // Optional embedded font family, see package fonts. Defaults to fonts.DefaultFamily
+	Stamp                string              // Document state stamped on the pages, see the Stamp constants. Set from the order, empty for none
 }
 
 func NewShippingManifest(delivery *models.Delivery) *ShippingManifest {
@@ -50,13 +56,68 @@ func NewShippingManifest(delivery *models.Delivery) *ShippingManifest {
 		DeliveredBy:          delivery.DeliveredBy,
 		ReceivedBy:           delivery.ReceivedBy,
 		Signature:            delivery.Signature,
-		DeliverImages:        delivery.GetCorrectlyRotatedImages(),
+		DeliverImages:        delivery.GetCompressedImages(models.DeliveryThumbnailCompression),
+		DeliveryImageURLs:    delivery.ImageURLs,
 		DeliveredAt:          delivery.GetDeliveredAtLocalTime().Format("January 2, 2006 at 3:04 PM"),
+		ProofOfDeliveryURL:   GetProofOfDeliveryURL(delivery.Order.ID),
+		Stamp:                GetOrderStamp(delivery.Order),
 	}
 	shippingManifest.getTableValues(shippingManifest.Product)
+	shippingManifest.getProofOfDelivery(delivery)
 	return shippingManifest
 }
 
+// GetProofOfDeliveryURL returns the public link to the proof of delivery record of an order.
+func GetProofOfDeliveryURL(orderID string) string {
+	return fmt.Sprintf("%s/deliveries/%s", strings.TrimSuffix(company_details.COMPANYURL, "/"), url.PathEscape(orderID))
+}
+
+// getProofOfDelivery sets the lines of the proof of delivery section from the recorded proof of delivery.
+func (p *ShippingManifest) getProofOfDelivery(delivery *models.Delivery) {
+	proof := delivery.Proof
+	if proof == nil {
+		return
+	}
+	location := "Unknown"
+	if checked := proof.GetCheckedLocation(); checked != nil {
+		location = checked.GetFormatted()
+		if proof.Location == nil {
+			location += " (from photo)"
+		}
+	}
+	capturedAt := "Unknown"
+	if !proof.CapturedAt.IsZero() {
+		capturedAt = proof.CapturedAt.In(delivery.GetDeliveredAtLocalTime().Location()).Format("January 2, 2006 at 3:04 PM")
+	}
+	p.ProofOfDelivery = []template.KeyValue{
+		{Label: "GPS LOCATION:", Value: location},
+		{Label: "LOCATION CHECK:", Value: proof.GetFormattedLocationCheck()},
+		{Label: "CAPTURED AT:", Value: capturedAt},
+		{Label: "PHOTOS TAKEN AT:", Value: proof.GetFormattedPhotosTakenAt(delivery.Order.TimeZone)},
+	}
+}
+
+// getDeliveryImageLinks returns the links of the thumbnails, nil when they do not match the images, e.g. when
+// some images failed to upload.
+func (p *ShippingManifest) getDeliveryImageLinks() []string {
+	if len(p.DeliveryImageURLs) != len(p.DeliverImages) {
+		return nil
+	}
+	return p.DeliveryImageURLs
+}
+
+// getSKUBarcodes returns the SKU of every product row, used as the row barcodes of the table.
+func (p *ShippingManifest) getSKUBarcodes() []string {
+	if !p.ShowSKUBarcodes {
+		return nil
+	}
+	barcodes := make([]string, len(p.Product))
+	for i, item := range p.Product {
+		barcodes[i] = item.SKU
+	}
+	return barcodes
+}
+
 func (p *ShippingManifest) getTableValues(items []*models.Product) {
 	tableValues := make([][]string, 0)
 	for _, item := range items {
@@ -75,257 +136,75 @@ func (p *ShippingManifest) getTableValues(items []*models.Product) {
 	p.TableValues = tableValues
 }
 
-func (p *ShippingManifest) RenderToPDF() ([]byte, error) {
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-
-	c := canvas.NewCanvas(pdf)
-	//Shipping manifest is has more table rows so it needs more space
-	c.BorderX = 5
-	c.BorderY = 5
-	c.BorderWidth = 200
-	c.BorderHeight = 285
-	c.MarginLeft = c.BorderX + 5
-	c.MarginTop = c.BorderX + 5
-
-	//Draw the outer border
-	c.DrawRectangle(&canvas.Rectangle{
-		X:           c.BorderX,
-		Y:           c.BorderY,
-		Width:       c.BorderWidth,
-		Height:      c.BorderHeight,
-		LineWidth:   0.8,
-		BorderColor: canvas.PrimaryBlue,
-	})
-	c.MoveTo(c.MarginLeft, 10)
-
-	//Draw the company logo on top left
-	c.DrawImageFromURL(canvas.ImageElement{
-		URL:    company_details.LOGOPATH,
-		X:      c.X,
-		Y:      c.Y,
-		Width:  65,
-		Height: 0,
-	})
-	c.IncX(105)
-	c.IncY(25)
-
-	//Draw the PDF Name on top right side
-	c.DrawSingleLineText(&canvas.Text{
-		Content: "SHIPPING MANIFEST",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    24,
-		Color:   canvas.PrimaryBlue,
-		Style:   "B",
-	})
-	c.ResetX()
-	c.IncY(7)
-
-	//Ship To Section
-	c.DrawSingleLineText(&canvas.Text{
-		Content: "Ship To",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	})
-	c.IncY(5)
-
-	c.DrawCustomerDetails(p.Customer)
-	c.DecY(30)
-	c.MoveTo(c.MarginLeft+105, c.Y)
-
-	c.DrawCompanyDetails()
-	c.IncY(5)
-
-	//Delivered At Section
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "Delivered At:",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	}, p.DeliveredAt)
-	c.IncY(5)
-
-	//P.O. Number
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "P.O.#:",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	}, p.PONumber)
-	c.IncY(10)
-	c.ResetX()
-
-	tableEndYPos := (&canvas.Table{
-		Header: &canvas.TableHeader{
-			X:           c.X,
-			Y:           c.Y,
-			Headers:     shippingManifestHeaders,
-			CellWidths:  shippingManifestTableColWidths,
-			FillColor:   canvas.PrimaryBlue,
-			BorderColor: canvas.PrimaryBlue,
-			TextColor:   canvas.White,
-			BorderThickness: 0.8,
-		},
-		Body: &canvas.TableBody{
-			X:           c.X,
-			Y:           c.Y,
-			Rows:        p.TableValues,
-			CellWidths:  shippingManifestTableColWidths,
-			TextColor:   canvas.Black,
-			BorderColor: canvas.PrimaryBlue,
-			BorderThickness: 0.8,
+// ToTemplate returns the declarative layout of the shipping manifest.
+func (p *ShippingManifest) ToTemplate() *template.Template {
+	tmpl := &template.Template{
+		Color:      canvas.PrimaryBlue,
+		FontFamily: p.FontFamily,
+		Watermark:  getStampWatermark(p.Stamp),
+		Stamp:      getCornerStamp(p.Stamp),
+		//Shipping manifest is has more table rows so it needs more space
+		Border: &template.Border{X: 5, Y: 5, Width: 200, Height: 285},
+		Blocks: []template.Block{
+			&template.Header{Title: "SHIPPING MANIFEST"},
+			&template.Row{Columns: []template.Column{
+				{Width: 105, Blocks: []template.Block{&template.CustomerDetails{Customer: p.Customer, Heading: "Ship To"}}},
+				{Blocks: []template.Block{
+					&template.CompanyDetails{},
+					&template.Spacer{Height: 5},
+					&template.KeyValues{Pairs: []template.KeyValue{
+						{Label: "Delivered At:", Value: p.DeliveredAt},
+						{Label: "P.O.#:", Value: p.PONumber},
+					}},
+					//Order barcode and the QR code linking to the proof of delivery record
+					&template.Row{Columns: []template.Column{
+						{Width: 62, Blocks: []template.Block{&template.Barcode{Content: p.PONumber, Width: 55, Height: 12, ShowContent: true}}},
+						{Blocks: []template.Block{&template.Barcode{Content: p.ProofOfDeliveryURL, QR: true, Width: 22}}},
+					}},
+				}},
+			}},
+			&template.Spacer{Height: 5},
+			&template.Table{
+				Headers:       shippingManifestHeaders,
+				CellWidths:    shippingManifestTableColWidths,
+				Rows:          p.TableValues,
+				HeaderSize:    9,
+				Barcodes:      p.getSKUBarcodes(),
+				BarcodeColumn: 5, //SKU column
+			},
+			&template.Spacer{Height: 5},
+			&template.Row{Columns: []template.Column{
+				{Width: 122, Blocks: []template.Block{&template.KeyValues{Pairs: []template.KeyValue{{Label: "Total Units:", Value: p.TotalUnits}}}}},
+				//Not exactly billing details but the total weight of each product. Didn't know what to call it
+				{Blocks: []template.Block{&template.Totals{
+					Labels:     []string{"NON HAZARDOUS WEIGHT:", "HAZARDOUS WEIGHT:", "TOTAL WEIGHT:"},
+					Values:     []string{p.TotalNonHazardWeight, p.TotalHazardousWeight, p.TotalWeight},
+					BoldLabels: true,
+					BoldValues: true,
+				}}},
+			}},
+			&template.Spacer{Height: 5},
+			&template.Row{Columns: []template.Column{
+				{Width: 122, Blocks: []template.Block{&template.KeyValues{Pairs: []template.KeyValue{{Label: "RECEIVED BY:", Value: p.ReceivedBy}}}}},
+				{Blocks: []template.Block{&template.KeyValues{Pairs: []template.KeyValue{{Label: "DELIVERED BY:", Value: p.DeliveredBy}}}}},
+			}},
+			&template.Spacer{Height: 5},
 		},
-	}).Draw(c, &canvas.Text{
-		Font:  "Helvetica",
-		X:     c.X,
-		Y:     c.Y,
-		Size:  9,
-		Color: canvas.White,
-		Style: "B",
-	})
-	c.MoveTo(c.MarginLeft, tableEndYPos+5)
-
-	//Check if a new page needs to be created
-	c.AddNewPageIfEnd(30, canvas.PrimaryBlue, 0.8)
-
-	//Total Units
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "Total Units: ",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	}, p.TotalUnits)
-	c.IncX(122)
-
-	//Not exactly billing details but the total weight of each product. Didn't know what to call it
-	c.DrawBillingDetails([]string{"NON HAZARDOUS WEIGHT:", "HAZARDOUS WEIGHT:", "TOTAL WEIGHT:"}, []string{p.TotalNonHazardWeight, p.TotalHazardousWeight, p.TotalWeight}, true, true)
-	c.MoveTo(c.MarginLeft, c.Y+10)
-
-	//Check if a new page needs to be created
-	c.AddNewPageIfEnd(10, canvas.PrimaryBlue, 0.8)
-
-	//Received By
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "RECEIVED BY: ",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	}, p.ReceivedBy)
-	c.IncX(122)
-
-	//Delivered By
-	c.DrawLabelWithSingleLineText(&canvas.Text{
-		Content: "DELIVERED BY:",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	}, p.DeliveredBy)
-	c.MoveTo(c.MarginLeft, c.Y+5)
-
-	//Check if a new page needs to be created (using 90px just to be safe)
-	c.AddNewPageIfEnd(90, canvas.PrimaryBlue, 0.8)
-
-	//Signature label
-	c.DrawSingleLineText(&canvas.Text{
-		Content: "SIGNATURE:",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	})
-	c.IncY(5)
-
-	//Signature Image
-	c.DrawImageFromBytes(canvas.ImageElement{
-		X:      c.X,
-		Y:      c.Y,
-		Width:  60,
-		Bytes:  p.Signature,
-	})
-	c.IncY(90)
-
-	//Check if a new page needs to be created (40px is the height of the image and 10px is the height of the label)
-	c.AddNewPageIfEnd(40, canvas.PrimaryBlue, 0.8)
-
-	//Delivery images label
-	c.DrawSingleLineText(&canvas.Text{
-		Content: "DELIVERY IMAGES:",
-		Font:    "Helvetica",
-		X:       c.X,
-		Y:       c.Y,
-		Size:    10,
-		Color:   canvas.Black,
-		Style:   "B",
-	})
-	c.IncY(5)
-
-	//Small images drawn below the signature
-	for _, imageBytes := range p.DeliverImages {
-		//Delivery Images
-		c.DrawImageFromBytes(canvas.ImageElement{
-			X:      c.X,
-			Y:      c.Y,
-			Width:  30,
-			Height: 30,
-			Bytes:  imageBytes,
-		})
-		c.IncX(33)
 	}
-
-	//Footer
-	c.DrawFooter(fmt.Sprintf("If you have any questions or concerns about this shipping manifest please contact us at %s", company_details.COMPANYEMAIL))
-
-	//Big Delivery Images drawn in each separate page
-	for _, imageBytes := range p.DeliverImages {
-		//Each image is drawn on a new page
-		c.PDF.AddPage()
-		c.MoveTo(0, 0)
-
-		var imageWidth float64
-		var imageHeight float64
-		pageWidth, pageHeight := c.PDF.GetPageSize()
-		image, _, err := image.Decode(bytes.NewReader(imageBytes))
-		if err != nil {
-			imageWidth = c.BorderWidth + c.BorderX //Page width basically
-			imageHeight = 0
-		} else {
-			imageWidth, imageHeight = c.GetCorrectByteImageDimensions(image)
-		}
-
-		//Delivery Image
-		c.DrawImageFromBytes(canvas.ImageElement{
-			X:      (pageWidth - imageWidth) / 2,   //Center horizontally
-			Y:      (pageHeight - imageHeight) / 2, //Center vertically
-			Width:  imageWidth,
-			Height: imageHeight,
-			Bytes:  imageBytes,
-		})
+	//Where and when the driver app captured the delivery
+	if len(p.ProofOfDelivery) > 0 {
+		tmpl.Blocks = append(tmpl.Blocks, &template.KeyValues{Pairs: p.ProofOfDelivery}, &template.Spacer{Height: 5})
 	}
+	tmpl.Blocks = append(tmpl.Blocks,
+		&template.Signature{Label: "SIGNATURE:", Image: p.Signature, Width: 60, Height: 40},
+		&template.Spacer{Height: 5},
+		//Thumbnails drawn below the signature linking to the originals, and optionally larger on a contact sheet
+		&template.Images{Label: "DELIVERY IMAGES:", Images: p.DeliverImages, Links: p.getDeliveryImageLinks(), ContactSheet: p.ShowContactSheet},
+		&template.Footer{Text: fmt.Sprintf("If you have any questions or concerns about this shipping manifest please contact us at %s", company_details.COMPANYEMAIL)},
+	)
+	return tmpl
+}
 
-	//Generate the PDF
-	bytes, err := pdfutils.GetGeneratedPDF(c.PDF)
-	return bytes, err
+func (p *ShippingManifest) RenderToPDF() ([]byte, error) {
+	return p.ToTemplate().RenderToPDF()
 }
@@ -0,0 +1,74 @@
+package layout
+
+import (
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/canvas"
+)
+
+// States stamped on the documents. Draft and cancelled documents get a diagonal watermark, paid documents and
+// copies a stamp in the corner. Any other text is stamped in the corner as is.
+const (
+	StampDraft     = "DRAFT"
+	StampCancelled = "CANCELLED"
+	StampPaid      = "PAID"
+	StampCopy      = "COPY"
+)
+
+// GetOrderStamp returns the stamp of the documents of an order from its status: pending orders are drafts
+// and cancelled orders are cancelled.
+//
+// Params:
+//   - o: the order of the document
+//
+// Returns:
+//   - string: StampDraft, StampCancelled or an empty string when the document needs no stamp
+func GetOrderStamp(o *models.Order) string {
+	switch o.Status {
+	case constants.OrderStatusPending:
+		return StampDraft
+	case constants.OrderStatusCancelled:
+		return StampCancelled
+	}
+	return ""
+}
+
+// GetInvoiceStamp returns the stamp of the invoice of an order. Same as GetOrderStamp, except invoices of
+// paid orders are stamped paid.
+//
+// Params:
+//   - o: the order of the invoice
+//
+// Returns:
+//   - string: StampDraft, StampCancelled, StampPaid or an empty string when the invoice needs no stamp
+func GetInvoiceStamp(o *models.Order) string {
+	if stamp := GetOrderStamp(o); stamp != "" {
+		return stamp
+	}
+	if o.Paid {
+		return StampPaid
+	}
+	return ""
+}
+
+// getStampWatermark returns the watermark drawn for a stamp, nil if the stamp is drawn in the corner.
+func getStampWatermark(stamp string) *canvas.Watermark {
+	switch stamp {
+	case StampDraft:
+		return &canvas.Watermark{Text: stamp, Color: canvas.Gray}
+	case StampCancelled:
+		return &canvas.Watermark{Text: stamp, Color: canvas.Red}
+	}
+	return nil
+}
+
+// getCornerStamp returns the corner stamp drawn for a stamp, nil if there is no stamp or it is a watermark.
+func getCornerStamp(stamp string) *canvas.Stamp {
+	switch stamp {
+	case "", StampDraft, StampCancelled:
+		return nil
+	case StampPaid:
+		return &canvas.Stamp{Text: stamp, Color: canvas.PrimaryGreen}
+	}
+	return &canvas.Stamp{Text: stamp, Color: canvas.PrimaryBlue}
+}
@@ -0,0 +1,109 @@
+package layout
+
+import (
+	"fmt"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/company_details"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/canvas"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/template"
+)
+
+var (
+	statementTableHeaders      = []string{"DATE", "TYPE", "NUMBER", "DESCRIPTION", "CHARGES", "PAYMENTS & CREDITS", "BALANCE"}
+	statementTableColWidths    = []float64{22, 20, 22, 50, 22, 22, 22}
+	statementAgingTableHeaders = []string{"CURRENT", "1-30 DAYS", "31-60 DAYS", "61-90 DAYS", "OVER 90 DAYS", "AMOUNT DUE"}
+	statementAgingColWidths    = []float64{30, 30, 30, 30, 30, 30}
+)
+
+// Statement is the statement of account of a customer. It lists the invoices, payments and credits over a
+// date range with the running balance, and the aging of the amount due at the end of the range.
+type Statement struct {
+	Customer         *models.Customer
+	From             string
+	To               string
+	OpeningBalance   string
+	TotalInvoiced    string
+	TotalPaid        string
+	AmountDue        string
+	TableValues      [][]string
+	AgingTableValues [][]string
+	FontFamily       string // Optional embedded font family, see package fonts. Defaults to fonts.DefaultFamily
+}
+
+func NewStatement(statement *models.AccountStatement) *Statement {
+	s := &Statement{
+		Customer:         statement.Customer,
+		From:             statement.From.Format("January 2, 2006"),
+		To:               statement.To.Format("January 2, 2006"),
+		OpeningBalance:   models.FormatAmount(statement.OpeningBalance),
+		TotalInvoiced:    models.FormatAmount(statement.TotalInvoiced),
+		TotalPaid:        models.FormatAmount(statement.TotalPaid),
+		AmountDue:        models.FormatAmount(statement.ClosingBalance),
+		AgingTableValues: [][]string{statement.Aging.GetFormattedValues()},
+	}
+	s.setTableValues(statement)
+	return s
+}
+
+func (s *Statement) setTableValues(statement *models.AccountStatement) {
+	tableValues := [][]string{{s.From, "", "", "Opening Balance", "", "", s.OpeningBalance}}
+	for _, line := range statement.Lines {
+		transaction := line.Transaction
+		charges, payments := "", ""
+		if transaction.Type == constants.AccountTransactionTypeInvoice {
+			charges = models.FormatAmount(transaction.Amount)
+		} else {
+			payments = models.FormatAmount(transaction.Amount)
+		}
+		tableValues = append(tableValues, []string{
+			transaction.Date.Format("01/02/2006"),
+			transaction.Type,
+			transaction.Number,
+			transaction.Description,
+			charges,
+			payments,
+			models.FormatAmount(line.RunningBalance),
+		})
+	}
+	s.TableValues = tableValues
+}
+
+// ToTemplate returns the declarative layout of the statement.
+func (s *Statement) ToTemplate() *template.Template {
+	return &template.Template{
+		Color:      canvas.PrimaryGreen,
+		FontFamily: s.FontFamily,
+		Blocks: []template.Block{
+			&template.Header{Title: "STATEMENT", TitleSize: 26, Layout: template.HeaderLogoRight, LogoWidth: 70, Height: 15},
+			&template.CompanyDetails{},
+			&template.Spacer{Height: 5},
+			&template.Row{Columns: []template.Column{
+				{Width: 120, Blocks: []template.Block{&template.CustomerDetails{Customer: s.Customer, Heading: "Statement For"}}},
+				{Blocks: []template.Block{&template.KeyValues{Pairs: []template.KeyValue{
+					{Label: "From:", Value: s.From},
+					{Label: "To:", Value: s.To},
+					{Label: "Amount Due:", Value: s.AmountDue},
+				}}}},
+			}},
+			&template.Spacer{Height: 15},
+			&template.Table{Headers: statementTableHeaders, CellWidths: statementTableColWidths, Rows: s.TableValues, HeaderSize: 9},
+			&template.Spacer{Height: 5},
+			&template.Row{Columns: []template.Column{
+				{Width: 105},
+				{Blocks: []template.Block{&template.Totals{
+					Labels: []string{"OPENING BALANCE", "CHARGES", "PAYMENTS & CREDITS", "AMOUNT DUE"},
+					Values: []string{s.OpeningBalance, s.TotalInvoiced, s.TotalPaid, s.AmountDue},
+				}}},
+			}},
+			&template.Spacer{Height: 5},
+			&template.Table{Headers: statementAgingTableHeaders, CellWidths: statementAgingColWidths, Rows: s.AgingTableValues, HeaderSize: 9},
+			&template.Footer{Text: fmt.Sprintf("If you have any questions or concerns about this statement please contact us at %s", company_details.COMPANYEMAIL)},
+		},
+	}
+}
+
+func (s *Statement) RenderToPDF() ([]byte, error) {
+	return s.ToTemplate().RenderToPDF()
+}
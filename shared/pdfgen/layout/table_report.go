@@ -0,0 +1,55 @@
+package layout
+
+import (
+	"fmt"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/company_details"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/canvas"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/template"
+)
+
+// TableReport is a generic internal report made of a single table and a summary block, used by the reports
+// of package reporting. The cell widths should add up to at most 180 to fit the page.
+type TableReport struct {
+	Title         string
+	Subtitle      string // E.g. the period of the report
+	Headers       []string
+	CellWidths    []float64
+	TableValues   [][]string
+	SummaryLabels []string
+	SummaryValues []string
+	FontFamily    string // Optional embedded font family, see package fonts. Defaults to fonts.DefaultFamily
+}
+
+// ToTemplate returns the declarative layout of the report.
+func (tr *TableReport) ToTemplate() *template.Template {
+	blocks := []template.Block{
+		&template.Header{Title: tr.Title, TitleSize: 18, Layout: template.HeaderLogoRight, LogoWidth: 70, Height: 15},
+		&template.Row{Columns: []template.Column{
+			{Width: 110, Blocks: []template.Block{&template.CompanyDetails{}}},
+			{Blocks: []template.Block{
+				&template.Spacer{Height: 25},
+				&template.Paragraph{Label: tr.Subtitle},
+			}},
+		}},
+		&template.Spacer{Height: 5},
+		&template.Table{Headers: tr.Headers, CellWidths: tr.CellWidths, Rows: tr.TableValues, HeaderSize: 8},
+	}
+	if len(tr.SummaryLabels) > 0 {
+		blocks = append(blocks, &template.Spacer{Height: 5}, &template.Row{Columns: []template.Column{
+			{Width: 100},
+			{Blocks: []template.Block{&template.Totals{Labels: tr.SummaryLabels, Values: tr.SummaryValues}}},
+		}})
+	}
+	blocks = append(blocks, &template.Footer{Text: fmt.Sprintf("This report is for internal use only. Generated by %s", company_details.COMPANYNAME)})
+
+	return &template.Template{
+		Color:      canvas.PrimaryGreen,
+		FontFamily: tr.FontFamily,
+		Blocks:     blocks,
+	}
+}
+
+func (tr *TableReport) RenderToPDF() ([]byte, error) {
+	return tr.ToTemplate().RenderToPDF()
+}
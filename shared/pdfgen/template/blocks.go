@@ -0,0 +1,791 @@
+package template
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/company_details"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/canvas"
+	pdfutils "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/utils"
+)
+
+const (
+	lineHeight     = 5 // Height of a single line of 10pt text
+	baselineOffset = 4 // Distance from the top of a line to the baseline of its text
+)
+
+// Block is a part of a template. The set of blocks is fixed, they are the types declared in this file.
+type Block interface {
+	// getHeight returns the height of the block when drawn in the width, used to move the block to a new page
+	// when it does not fit in the rest of the current one
+	getHeight(r *renderer, width float64) float64
+	// draw draws the block with its top left corner at (x, y) and returns the y position where it ends
+	draw(r *renderer, x, y, width float64) (float64, error)
+	validate() error
+}
+
+/* Header */
+
+type HeaderLayout int
+
+const (
+	HeaderLogoLeft      HeaderLayout = iota // Logo on the left and the title right aligned
+	HeaderLogoRight                         // Title on the left and the logo on the right
+	HeaderTitleCentered                     // Logo on the left and the title centered
+)
+
+// Header draws the title of the document and the company logo.
+type Header struct {
+	Title     string
+	TitleSize float64 // Defaults to 24
+	Layout    HeaderLayout
+	LogoURL   string  // Defaults to company_details.LOGOPATH
+	LogoWidth float64 // Defaults to 65
+	Height    float64 // Defaults to 30
+}
+
+func (h *Header) getHeight(r *renderer, width float64) float64 { return getOrDefault(h.Height, 30) }
+
+func (h *Header) draw(r *renderer, x, y, width float64) (float64, error) {
+	height := h.getHeight(r, width)
+	logoURL := h.LogoURL
+	if logoURL == "" {
+		logoURL = company_details.LOGOPATH
+	}
+	logoWidth := getOrDefault(h.LogoWidth, 65)
+	logoX := x
+	if h.Layout == HeaderLogoRight {
+		logoX = x + width - logoWidth
+	}
+	r.canvas.DrawImageFromURL(canvas.ImageElement{
+		URL:   logoURL,
+		X:     logoX,
+		Y:     y,
+		Width: logoWidth,
+	})
+
+	title := r.newText(h.Title, getOrDefault(h.TitleSize, 24), "B", r.color)
+	titleWidth := title.GetWidth(r.canvas.PDF)
+	switch h.Layout {
+	case HeaderLogoRight:
+		title.SetX(x)
+		title.SetY(y + 10)
+	case HeaderTitleCentered:
+		title.SetX(x + (width-titleWidth)/2)
+		title.SetY(y + height - 3)
+	default:
+		title.SetX(x + width - titleWidth)
+		title.SetY(y + height - 3)
+	}
+	r.canvas.DrawSingleLineText(title)
+	return y + height, nil
+}
+
+func (h *Header) validate() error { return nil }
+
+/* Company and customer details */
+
+// CompanyDetails draws the name, address and contact details of the company.
+type CompanyDetails struct {
+	Heading       string // Optional heading drawn above the details
+	FilledHeading bool   // Draws the heading in white inside a rectangle filled with the template color
+}
+
+func (cd *CompanyDetails) getHeight(r *renderer, width float64) float64 {
+	return getHeadingHeight(cd.Heading, cd.FilledHeading) + 6*lineHeight
+}
+
+func (cd *CompanyDetails) draw(r *renderer, x, y, width float64) (float64, error) {
+	y = r.drawHeading(cd.Heading, cd.FilledHeading, x, y, width)
+	r.canvas.MoveTo(x, y+baselineOffset)
+	r.canvas.DrawCompanyDetails()
+	return y + 6*lineHeight, nil
+}
+
+func (cd *CompanyDetails) validate() error { return nil }
+
+// CustomerDetails draws the name, address and contact details of a customer.
+type CustomerDetails struct {
+	Customer      *models.Customer
+	Heading       string // Optional heading drawn above the details, e.g. "Bill To"
+	FilledHeading bool   // Draws the heading in white inside a rectangle filled with the template color
+}
+
+func (cd *CustomerDetails) getHeight(r *renderer, width float64) float64 {
+	return getHeadingHeight(cd.Heading, cd.FilledHeading) + 5*lineHeight
+}
+
+func (cd *CustomerDetails) draw(r *renderer, x, y, width float64) (float64, error) {
+	y = r.drawHeading(cd.Heading, cd.FilledHeading, x, y, width)
+	r.canvas.MoveTo(x, y+baselineOffset)
+	r.canvas.DrawCustomerDetails(cd.Customer)
+	return y + 5*lineHeight, nil
+}
+
+func (cd *CustomerDetails) validate() error {
+	if cd.Customer == nil {
+		return errors.New("customer details without a customer")
+	}
+	return nil
+}
+
+/* Key values and totals */
+
+type KeyValue struct {
+	Label string
+	Value string
+}
+
+// KeyValues draws a bold label followed by its value on every line, e.g. the date and the number of a document.
+type KeyValues struct {
+	Pairs []KeyValue
+}
+
+func (kv *KeyValues) getHeight(r *renderer, width float64) float64 {
+	return float64(len(kv.Pairs)) * lineHeight
+}
+
+func (kv *KeyValues) draw(r *renderer, x, y, width float64) (float64, error) {
+	for _, pair := range kv.Pairs {
+		text := r.newText(pair.Label, 10, "B", canvas.Black)
+		text.SetX(x)
+		text.SetY(y + baselineOffset)
+		r.canvas.DrawLabelWithSingleLineText(text, pair.Value)
+		y += lineHeight
+	}
+	return y, nil
+}
+
+func (kv *KeyValues) validate() error { return nil }
+
+// Totals draws the labels and the values in two aligned columns, the last value is always bold.
+type Totals struct {
+	Labels     []string
+	Values     []string
+	BoldLabels bool
+	BoldValues bool
+}
+
+func (t *Totals) getHeight(r *renderer, width float64) float64 {
+	return float64(len(t.Labels)) * lineHeight
+}
+
+func (t *Totals) draw(r *renderer, x, y, width float64) (float64, error) {
+	r.canvas.MoveTo(x, y+baselineOffset)
+	r.canvas.DrawBillingDetails(t.Labels, t.Values, t.BoldLabels, t.BoldValues)
+	return y + t.getHeight(r, width), nil
+}
+
+func (t *Totals) validate() error {
+	if len(t.Labels) != len(t.Values) {
+		return fmt.Errorf("totals have %d labels and %d values", len(t.Labels), len(t.Values))
+	}
+	return nil
+}
+
+/* Paragraph */
+
+// Paragraph draws an optional bold label and the content wrapped in the width of the block.
+type Paragraph struct {
+	Label   string
+	Content string
+	Size    float64 // Size of the content. Defaults to 9
+	Width   float64 // Optional width the content is wrapped in, less than the width of the block
+}
+
+func (p *Paragraph) getHeight(r *renderer, width float64) float64 {
+	height := 1.0
+	if p.Label != "" {
+		height += lineHeight
+	}
+	if p.Content != "" {
+		height += r.newText(p.Content, getOrDefault(p.Size, 9), "", canvas.Black).GetMultiTextHeight(r.canvas.PDF, p.getWidth(width))
+	}
+	return height
+}
+
+func (p *Paragraph) getWidth(width float64) float64 {
+	if p.Width > 0 && p.Width < width {
+		return p.Width
+	}
+	return width
+}
+
+func (p *Paragraph) draw(r *renderer, x, y, width float64) (float64, error) {
+	end := y + p.getHeight(r, width)
+	if p.Label != "" {
+		label := r.newText(p.Label, 10, "B", canvas.Black)
+		label.SetX(x)
+		label.SetY(y + baselineOffset)
+		r.canvas.DrawSingleLineText(label)
+		y += lineHeight
+	}
+	if p.Content != "" {
+		content := r.newText(p.Content, getOrDefault(p.Size, 9), "", canvas.Black)
+		content.SetX(x)
+		content.SetY(y + content.GetTextHeight(r.canvas.PDF))
+		r.canvas.DrawMultipleLines(content, p.getWidth(width), "")
+	}
+	return end, nil
+}
+
+func (p *Paragraph) validate() error { return nil }
+
+/* Table */
+
+// Table draws a table with a header in the template color. The table breaks across pages with the header
+// repeated on every page, so it should not be put in a Row.
+type Table struct {
+	Indent        float64 // Space left of the table
+	Headers       []string
+	CellWidths    []float64
+	Rows          [][]string
+	MultiLineRows []canvas.TableRow // Rows where every cell has several lines, used instead of Rows when set
+	HeaderSize    float64           // Defaults to 10
+	Checkboxes    bool              // See canvas.TableBody
+	Barcodes      []string          // See canvas.TableBody
+	BarcodeColumn int
+}
+
+// getHeight returns the height of the header and a single line row, tables are never moved to a new page
+// as a whole.
+func (t *Table) getHeight(r *renderer, width float64) float64 { return 10 }
+
+func (t *Table) draw(r *renderer, x, y, width float64) (float64, error) {
+	c := r.canvas
+	x += t.Indent
+	headerText := r.newText("", getOrDefault(t.HeaderSize, 10), "B", canvas.White)
+	header := &canvas.TableHeader{
+		X:               x,
+		Y:               y,
+		Headers:         t.Headers,
+		CellWidths:      t.CellWidths,
+		TextColor:       canvas.White,
+		FillColor:       r.color,
+		BorderColor:     r.color,
+		BorderThickness: 0.8,
+	}
+	if len(t.MultiLineRows) == 0 {
+		return (&canvas.Table{
+			Header: header,
+			Body: &canvas.TableBody{
+				X:               x,
+				Y:               y,
+				CellWidths:      t.CellWidths,
+				Rows:            t.Rows,
+				TextColor:       canvas.Black,
+				BorderColor:     r.color,
+				BorderThickness: 0.8,
+				Checkboxes:      t.Checkboxes,
+				Barcodes:        t.Barcodes,
+				BarcodeColumn:   t.BarcodeColumn,
+			},
+			Width: pdfutils.CalculateTableCellWidths(t.CellWidths),
+		}).Draw(c, headerText), nil
+	}
+
+	// Keep the header together with at least one row like canvas.Table
+	if header.Y+10 > c.GetContentBottom() {
+		c.AddPage()
+		header.Y = c.Y
+	}
+	repeatedHeaderText := *headerText
+	header.Draw(c, headerText)
+	body := &canvas.TableBody2{
+		X:               x,
+		Y:               header.Y + header.Height,
+		CellWidths:      t.CellWidths,
+		TextColor:       canvas.Black,
+		BorderColor:     r.color,
+		BorderThickness: 0.8,
+		Rows:            t.getMultiLineRows(),
+		Header:          header,
+		HeaderText:      &repeatedHeaderText,
+	}
+	body.Draw(c, r.newText("", 9, "", canvas.Black))
+	return body.Y + body.Height, nil
+}
+
+// getMultiLineRows returns the multi line rows with the cells without a width set to the width of their column.
+func (t *Table) getMultiLineRows() []canvas.TableRow {
+	rows := make([]canvas.TableRow, len(t.MultiLineRows))
+	for i, row := range t.MultiLineRows {
+		cells := make([]canvas.TableCell, len(row.Cells))
+		for j, cell := range row.Cells {
+			if cell.Width == 0 {
+				cell.Width = t.CellWidths[j]
+			}
+			cells[j] = cell
+		}
+		rows[i] = canvas.TableRow{Cells: cells}
+	}
+	return rows
+}
+
+func (t *Table) validate() error {
+	if len(t.Headers) == 0 || len(t.Headers) != len(t.CellWidths) {
+		return fmt.Errorf("table has %d headers and %d cell widths", len(t.Headers), len(t.CellWidths))
+	}
+	for i, row := range t.Rows {
+		if len(row) != len(t.CellWidths) {
+			return fmt.Errorf("table row %d has %d cells, expected %d", i, len(row), len(t.CellWidths))
+		}
+	}
+	for i, row := range t.MultiLineRows {
+		if len(row.Cells) != len(t.CellWidths) {
+			return fmt.Errorf("table row %d has %d cells, expected %d", i, len(row.Cells), len(t.CellWidths))
+		}
+	}
+	return nil
+}
+
+/* Images */
+
+// Image draws an image from a URL or from its bytes.
+//
+// Note:
+//   - Images from bytes keep their aspect ratio within Width and Height, a zero Height only limits the width
+//   - Images from a URL are drawn with Width and Height as is, a zero Height keeps the aspect ratio but the
+//     block does not take any space since the height is unknown before the image is downloaded
+type Image struct {
+	URL    string
+	Bytes  []byte
+	Width  float64
+	Height float64
+}
+
+func (i *Image) getHeight(r *renderer, width float64) float64 {
+	if len(i.Bytes) == 0 {
+		return i.Height
+	}
+	_, height := getImageSize(i.Bytes, getOrDefault(i.Width, width), i.Height)
+	return height
+}
+
+func (i *Image) draw(r *renderer, x, y, width float64) (float64, error) {
+	if len(i.Bytes) == 0 {
+		r.canvas.DrawImageFromURL(canvas.ImageElement{URL: i.URL, X: x, Y: y, Width: i.Width, Height: i.Height})
+		return y + i.Height, nil
+	}
+	imageWidth, imageHeight := getImageSize(i.Bytes, getOrDefault(i.Width, width), i.Height)
+	r.canvas.DrawImageFromBytes(canvas.ImageElement{Bytes: i.Bytes, X: x, Y: y, Width: imageWidth, Height: imageHeight})
+	return y + imageHeight, nil
+}
+
+func (i *Image) validate() error {
+	if i.URL == "" && len(i.Bytes) == 0 {
+		return errors.New("image without a URL or bytes")
+	}
+	return nil
+}
+
+// Images draws thumbnails of the images side by side, wrapping to the next line when the width is full. Every
+// image is fitted in a square keeping its aspect ratio.
+type Images struct {
+	Label        string
+	Images       [][]byte
+	Links        []string // Optional url opened when the image at the same index is clicked, e.g. the full resolution original
+	Size         float64  // Size of the thumbnails. Defaults to 30
+	FullPages    bool     // Also draws every image centered on its own page after the footer
+	ContactSheet bool     // Also draws the images larger on a grid, on pages after the footer
+}
+
+const (
+	imagesGap           = 3
+	contactSheetColumns = 2
+	contactSheetRows    = 3
+)
+
+func (im *Images) getSize() float64 { return getOrDefault(im.Size, 30) }
+
+func (im *Images) getLink(i int) string {
+	if i < len(im.Links) {
+		return im.Links[i]
+	}
+	return ""
+}
+
+func (im *Images) getHeight(r *renderer, width float64) float64 {
+	height := 0.0
+	if im.Label != "" {
+		height += lineHeight
+	}
+	perLine := max(int((width+imagesGap)/(im.getSize()+imagesGap)), 1)
+	lines := (len(im.Images) + perLine - 1) / perLine
+	return height + float64(lines)*(im.getSize()+imagesGap)
+}
+
+func (im *Images) draw(r *renderer, x, y, width float64) (float64, error) {
+	end := y + im.getHeight(r, width)
+	if im.Label != "" {
+		label := r.newText(im.Label, 10, "B", canvas.Black)
+		label.SetX(x)
+		label.SetY(y + baselineOffset)
+		r.canvas.DrawSingleLineText(label)
+		y += lineHeight
+	}
+	size := im.getSize()
+	imageX := x
+	for i, imageBytes := range im.Images {
+		if imageX+size > x+width && imageX > x {
+			imageX = x
+			y += size + imagesGap
+		}
+		r.drawFittedImage(imageBytes, im.getLink(i), imageX, y, size, size)
+		imageX += size + imagesGap
+	}
+	if im.FullPages {
+		for _, imageBytes := range im.Images {
+			r.trailingPages = append(r.trailingPages, func() { r.drawFullPageImage(imageBytes) })
+		}
+	}
+	if im.ContactSheet && len(im.Images) > 0 {
+		r.trailingPages = append(r.trailingPages, func() { r.drawContactSheet(im) })
+	}
+	return end, nil
+}
+
+func (im *Images) validate() error {
+	if len(im.Links) > len(im.Images) {
+		return fmt.Errorf("Images has %d links for %d images", len(im.Links), len(im.Images))
+	}
+	return nil
+}
+
+// drawFittedImage draws the image centered in the box, as large as the box allows without changing its aspect
+// ratio.
+func (r *renderer) drawFittedImage(imageBytes []byte, link string, x, y, width, height float64) {
+	imageWidth, imageHeight := getImageSize(imageBytes, width, height)
+	r.canvas.DrawImageFromBytes(canvas.ImageElement{
+		Bytes:   imageBytes,
+		X:       x + (width-imageWidth)/2,
+		Y:       y + (height-imageHeight)/2,
+		Width:   imageWidth,
+		Height:  imageHeight,
+		LinkStr: link,
+	})
+}
+
+// drawContactSheet adds bordered pages with the images on a grid, numbered in the same order as the thumbnails.
+// Images with a link get a caption to open the original.
+func (r *renderer) drawContactSheet(im *Images) {
+	c := r.canvas
+	width := r.getContentWidth()
+	perPage := contactSheetColumns * contactSheetRows
+	cellWidth := (width - imagesGap*(contactSheetColumns-1)) / contactSheetColumns
+	for i, imageBytes := range im.Images {
+		y := c.MarginTop
+		if i%perPage == 0 {
+			c.AddPage()
+			if im.Label != "" {
+				label := r.newText(im.Label, 10, "B", canvas.Black)
+				label.SetX(c.MarginLeft)
+				label.SetY(y + baselineOffset)
+				c.DrawSingleLineText(label)
+			}
+		}
+		y += lineHeight
+		//Each cell has the image and a caption below it
+		cellHeight := (c.GetContentBottom()-y)/contactSheetRows - imagesGap
+		imageHeight := cellHeight - lineHeight
+		column := i % contactSheetColumns
+		row := (i % perPage) / contactSheetColumns
+		cellX := c.MarginLeft + float64(column)*(cellWidth+imagesGap)
+		cellY := y + float64(row)*(cellHeight+imagesGap)
+
+		link := im.getLink(i)
+		r.drawFittedImage(imageBytes, link, cellX, cellY, cellWidth, imageHeight)
+		caption := fmt.Sprintf("Image %d", i+1)
+		if link != "" {
+			caption += " - click to open the full resolution original"
+		}
+		text := r.newText(caption, 8, "", canvas.Black)
+		text.SetX(cellX + (cellWidth-text.GetWidth(c.PDF))/2)
+		text.SetY(cellY + imageHeight + baselineOffset)
+		c.DrawSingleLineText(text)
+		if link != "" {
+			c.PDF.LinkString(text.X, cellY+imageHeight, text.GetWidth(c.PDF), lineHeight, link)
+		}
+	}
+}
+
+// drawFullPageImage adds a page without a border and draws the image centered, as large as the page allows.
+func (r *renderer) drawFullPageImage(imageBytes []byte) {
+	c := r.canvas
+	c.PDF.AddPage()
+	pageWidth, pageHeight := c.PDF.GetPageSize()
+
+	var imageWidth, imageHeight float64
+	img, _, err := image.Decode(bytes.NewReader(imageBytes))
+	if err != nil {
+		imageWidth = pageWidth
+	} else {
+		imageWidth, imageHeight = c.GetCorrectByteImageDimensions(img)
+	}
+	c.DrawImageFromBytes(canvas.ImageElement{
+		X:      (pageWidth - imageWidth) / 2,   //Center horizontally
+		Y:      (pageHeight - imageHeight) / 2, //Center vertically
+		Width:  imageWidth,
+		Height: imageHeight,
+		Bytes:  imageBytes,
+	})
+}
+
+// getImageSize returns the size of the image scaled to the width, and scaled down further to the max
+// height when it is set. The width and the max height are returned as is when the image can not be decoded.
+func getImageSize(imageBytes []byte, width, maxHeight float64) (float64, float64) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(imageBytes))
+	if err != nil || config.Width == 0 || config.Height == 0 {
+		return width, maxHeight
+	}
+	aspectRatio := float64(config.Height) / float64(config.Width)
+	height := width * aspectRatio
+	if maxHeight > 0 && height > maxHeight {
+		return maxHeight / aspectRatio, maxHeight
+	}
+	return width, height
+}
+
+/* Signature */
+
+// Signature draws a bold label and the signature image below it. Without an image a line to sign on is drawn
+// next to the label instead, e.g. for documents signed by hand.
+type Signature struct {
+	Label  string
+	Image  []byte
+	Width  float64 // Width of the image or the end of the line from the left of the block. Defaults to 60
+	Height float64 // Max height of the image. Defaults to 30
+}
+
+func (s *Signature) getHeight(r *renderer, width float64) float64 {
+	if len(s.Image) == 0 {
+		return lineHeight
+	}
+	return lineHeight + getOrDefault(s.Height, 30)
+}
+
+func (s *Signature) draw(r *renderer, x, y, width float64) (float64, error) {
+	label := r.newText(s.Label, 10, "B", canvas.Black)
+	label.SetX(x)
+	label.SetY(y + baselineOffset)
+	r.canvas.DrawSingleLineText(label)
+
+	signatureWidth := min(getOrDefault(s.Width, 60), width)
+	if len(s.Image) == 0 {
+		r.canvas.DrawLine(&canvas.Line{
+			X1:    x + label.GetWidth(r.canvas.PDF) + 2,
+			Y1:    y + baselineOffset + 1,
+			X2:    x + signatureWidth,
+			Y2:    y + baselineOffset + 1,
+			Color: canvas.Black,
+			Width: 0.3,
+		})
+		return y + lineHeight, nil
+	}
+	imageWidth, imageHeight := getImageSize(s.Image, signatureWidth, getOrDefault(s.Height, 30))
+	r.canvas.DrawImageFromBytes(canvas.ImageElement{Bytes: s.Image, X: x, Y: y + lineHeight, Width: imageWidth, Height: imageHeight})
+	return y + s.getHeight(r, width), nil
+}
+
+func (s *Signature) validate() error { return nil }
+
+/* Barcode */
+
+// Barcode draws a Code128 barcode or a QR code of the content, see canvas.BarcodeElement.
+type Barcode struct {
+	Content     string
+	QR          bool
+	Width       float64
+	Height      float64 // Not used by QR codes since they are square
+	ShowContent bool
+}
+
+func (b *Barcode) getHeight(r *renderer, width float64) float64 {
+	if b.QR {
+		return b.Width
+	}
+	if b.ShowContent {
+		return b.Height + lineHeight
+	}
+	return b.Height
+}
+
+func (b *Barcode) draw(r *renderer, x, y, width float64) (float64, error) {
+	element := &canvas.BarcodeElement{X: x, Y: y, Width: b.Width, Height: b.Height, Content: b.Content, ShowContent: b.ShowContent}
+	var err error
+	if b.QR {
+		err = r.canvas.DrawQRCode(element)
+	} else {
+		err = r.canvas.DrawCode128(element)
+	}
+	return y + b.getHeight(r, width), err
+}
+
+func (b *Barcode) validate() error {
+	if b.Width <= 0 || (!b.QR && b.Height <= 0) {
+		return errors.New("barcode without a size")
+	}
+	return nil
+}
+
+/* Layout blocks */
+
+// Spacer leaves an empty vertical space.
+type Spacer struct {
+	Height float64
+}
+
+func (s *Spacer) getHeight(r *renderer, width float64) float64 { return s.Height }
+
+func (s *Spacer) draw(r *renderer, x, y, width float64) (float64, error) { return y + s.Height, nil }
+
+func (s *Spacer) validate() error { return nil }
+
+// PageBreak starts a new page. It can only be used at the top level of a template.
+type PageBreak struct{}
+
+func (pb *PageBreak) getHeight(r *renderer, width float64) float64 { return 0 }
+
+func (pb *PageBreak) draw(r *renderer, x, y, width float64) (float64, error) { return y, nil }
+
+func (pb *PageBreak) validate() error { return nil }
+
+// Footer is drawn at the bottom of every page with the page number, wherever it is in the blocks.
+type Footer struct {
+	Text string
+}
+
+func (f *Footer) getHeight(r *renderer, width float64) float64 { return 0 }
+
+func (f *Footer) draw(r *renderer, x, y, width float64) (float64, error) { return y, nil }
+
+func (f *Footer) validate() error { return nil }
+
+// Column is a column of a Row. The blocks are drawn top to bottom within the width of the column.
+type Column struct {
+	Width  float64 // A zero width shares the width left by the other columns
+	Blocks []Block
+}
+
+// Row draws its columns side by side. The row ends at the end of its longest column and is moved to a new
+// page as a whole.
+type Row struct {
+	Columns []Column
+}
+
+// getColumnWidths returns the width of every column, the columns without a width share the width left.
+func (rw *Row) getColumnWidths(width float64) []float64 {
+	widths := make([]float64, len(rw.Columns))
+	fixedWidth, flexibleColumns := 0.0, 0
+	for _, column := range rw.Columns {
+		fixedWidth += column.Width
+		if column.Width == 0 {
+			flexibleColumns++
+		}
+	}
+	for i, column := range rw.Columns {
+		widths[i] = column.Width
+		if column.Width == 0 {
+			widths[i] = max(width-fixedWidth, 0) / float64(flexibleColumns)
+		}
+	}
+	return widths
+}
+
+func (rw *Row) getHeight(r *renderer, width float64) float64 {
+	height := 0.0
+	for i, columnWidth := range rw.getColumnWidths(width) {
+		columnHeight := 0.0
+		for _, block := range rw.Columns[i].Blocks {
+			columnHeight += block.getHeight(r, columnWidth)
+		}
+		height = max(height, columnHeight)
+	}
+	return height
+}
+
+func (rw *Row) draw(r *renderer, x, y, width float64) (float64, error) {
+	end := y
+	for i, columnWidth := range rw.getColumnWidths(width) {
+		columnY := y
+		for _, block := range rw.Columns[i].Blocks {
+			var err error
+			if columnY, err = block.draw(r, x, columnY, columnWidth); err != nil {
+				return 0, err
+			}
+		}
+		end = max(end, columnY)
+		x += columnWidth
+	}
+	return end, nil
+}
+
+func (rw *Row) validate() error {
+	for _, column := range rw.Columns {
+		for _, block := range column.Blocks {
+			switch block.(type) {
+			case nil:
+				return errors.New("row has a nil block")
+			case *Footer, *PageBreak:
+				return errors.New("footers and page breaks can not be put in a row")
+			}
+			if err := block.validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/* Helpers */
+
+// newText returns a text in the font family of the canvas.
+func (r *renderer) newText(content string, size float64, style string, color [3]int) *canvas.Text {
+	return &canvas.Text{
+		Content: content,
+		Font:    r.canvas.FontFamily,
+		Size:    size,
+		Style:   style,
+		Color:   color,
+	}
+}
+
+func getHeadingHeight(heading string, filled bool) float64 {
+	switch {
+	case heading == "":
+		return 0
+	case filled:
+		return 8
+	default:
+		return lineHeight
+	}
+}
+
+// drawHeading draws the heading of a details block and returns the y position where the details start.
+func (r *renderer) drawHeading(heading string, filled bool, x, y, width float64) float64 {
+	if heading == "" {
+		return y
+	}
+	if !filled {
+		text := r.newText(heading, 10, "B", canvas.Black)
+		text.SetX(x)
+		text.SetY(y + baselineOffset)
+		r.canvas.DrawSingleLineText(text)
+		return y + lineHeight
+	}
+	r.canvas.DrawTextInColoredRect(r.newText(heading, 10, "B", canvas.White), &canvas.Rectangle{
+		X:         x,
+		Y:         y,
+		Width:     min(75, width),
+		Height:    6,
+		Style:     "F",
+		LineWidth: 0.8,
+		FillColor: r.color,
+	}, "left")
+	return y + getHeadingHeight(heading, filled)
+}
@@ -0,0 +1,194 @@
+// package template describes pdf layouts declaratively as a list of blocks (header, company, customer,
+// key values, tables, totals, images, signatures and footer) and renders them with the canvas. The layouts of
+// package layout build a Template from their data instead of positioning every element by hand.
+//
+// Blocks are drawn top to bottom starting at the top left margin. A block that does not fit in the rest of
+// the page is moved to a new page, tables break across pages on their own. Blocks are placed side by side
+// with a Row.
+package template
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/canvas"
+	pdfutils "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/utils"
+	"github.com/phpdave11/gofpdf"
+)
+
+const (
+	OrientationPortrait  = "P"
+	OrientationLandscape = "L"
+)
+
+// Template is the declarative description of a pdf. It implements the PDFGen interface of package pdfgen.
+type Template struct {
+	Orientation string            // OrientationPortrait (default) or OrientationLandscape
+	Color       [3]int            // Color of the border, the title, the headings and the tables. Defaults to canvas.PrimaryBlue
+	Border      *Border           // Optional border of the pages. Defaults to the canvas border
+	FontFamily  string            // Optional embedded font family, see package fonts. Defaults to fonts.DefaultFamily
+	CreatedAt   time.Time         // Creation date in the metadata of the pdf. Defaults to the time the pdf is generated
+	Watermark   *canvas.Watermark // Optional text drawn diagonally across every page, e.g. DRAFT
+	Stamp       *canvas.Stamp     // Optional text stamped in the top right corner of every page, e.g. PAID
+	Blocks      []Block
+}
+
+// Border is the border drawn on every page and the margin of the content inside it.
+//
+// Note:
+//   - A zero Width or Height fills the page, leaving the same space as X and Y on the other side
+//   - Margin is the space between the border and the content. Defaults to 5
+type Border struct {
+	X, Y   float64
+	Width  float64
+	Height float64
+	Margin float64
+}
+
+// Validate checks the blocks can be rendered, e.g. tables have a width for every header.
+//
+// Returns:
+//   - error: the first invalid block, nil if the template is valid
+func (t *Template) Validate() error {
+	if t.Orientation != "" && t.Orientation != OrientationPortrait && t.Orientation != OrientationLandscape {
+		return fmt.Errorf("Invalid template orientation %q", t.Orientation)
+	}
+	footers := 0
+	for i, block := range t.Blocks {
+		if block == nil {
+			return fmt.Errorf("Template block %d is nil", i)
+		}
+		if _, ok := block.(*Footer); ok {
+			footers++
+		}
+		if err := block.validate(); err != nil {
+			return fmt.Errorf("Template block %d: %v", i, err)
+		}
+	}
+	if footers > 1 {
+		return fmt.Errorf("Template has %d footers, at most one is allowed", footers)
+	}
+	return nil
+}
+
+// RenderToPDF renders the blocks of the template.
+//
+// Returns:
+//   - []byte: the generated pdf
+//   - error: if the template is invalid, the font family can not be registered or a block can not be drawn
+func (t *Template) RenderToPDF() ([]byte, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	orientation := t.Orientation
+	if orientation == "" {
+		orientation = OrientationPortrait
+	}
+	pdf := gofpdf.New(orientation, "mm", "A4", "")
+	// Sorting the fonts and images keeps the object numbers stable so the same template always generates the
+	// same pdf when CreatedAt is set
+	pdf.SetCatalogSort(true)
+	if !t.CreatedAt.IsZero() {
+		pdf.SetCreationDate(t.CreatedAt)
+		pdf.SetModificationDate(t.CreatedAt)
+	}
+	pdf.AddPage()
+
+	c := canvas.NewCanvas(pdf)
+	if err := c.SetFontFamily(t.FontFamily); err != nil {
+		return nil, err
+	}
+	r := &renderer{canvas: c, color: t.getColor()}
+	t.applyBorder(c)
+	c.SetBorderColor(r.color)
+	c.DrawBorder()
+
+	//Draw the blocks, the footer, the watermark and the stamp are drawn once all the pages exist
+	var footer *Footer
+	y := c.MarginTop
+	for _, block := range t.Blocks {
+		if f, ok := block.(*Footer); ok {
+			footer = f
+			continue
+		}
+		var err error
+		if y, err = r.drawFlowBlock(block, y); err != nil {
+			return nil, err
+		}
+	}
+	if footer != nil {
+		c.DrawFooter(footer.Text)
+	}
+	c.DrawWatermark(t.Watermark)
+	c.DrawStamp(t.Stamp)
+	//Pages added after the footer, e.g. full page delivery images
+	for _, drawPage := range r.trailingPages {
+		drawPage()
+	}
+
+	//Generate the PDF
+	bytes, err := pdfutils.GetGeneratedPDF(c.PDF)
+	return bytes, err
+}
+
+func (t *Template) getColor() [3]int {
+	if t.Color == ([3]int{}) {
+		return canvas.PrimaryBlue
+	}
+	return t.Color
+}
+
+func (t *Template) applyBorder(c *canvas.Canvas) {
+	if t.Border == nil {
+		return
+	}
+	pageWidth, pageHeight := c.PDF.GetPageSize()
+	margin := t.Border.Margin
+	if margin == 0 {
+		margin = 5
+	}
+	c.SetBorderX(t.Border.X)
+	c.SetBorderY(t.Border.Y)
+	c.SetBorderWidth(getOrDefault(t.Border.Width, pageWidth-2*t.Border.X))
+	c.SetBorderHeight(getOrDefault(t.Border.Height, pageHeight-2*t.Border.Y))
+	c.SetMarginLeft(t.Border.X + margin)
+	c.SetMarginTop(t.Border.Y + margin)
+}
+
+// renderer keeps the state shared by the blocks while a template is drawn.
+type renderer struct {
+	canvas        *canvas.Canvas
+	color         [3]int
+	trailingPages []func()
+}
+
+// getContentWidth returns the width between the left and the right margin.
+func (r *renderer) getContentWidth() float64 {
+	c := r.canvas
+	return c.BorderWidth - 2*(c.MarginLeft-c.BorderX)
+}
+
+// drawFlowBlock draws a top level block at the left margin, on a new page if it does not fit in the rest of
+// the current one. Returns the y position where the block ends.
+func (r *renderer) drawFlowBlock(block Block, y float64) (float64, error) {
+	c := r.canvas
+	width := r.getContentWidth()
+	if _, ok := block.(*PageBreak); ok {
+		c.AddPage()
+		return c.Y, nil
+	}
+	// Tables break across pages on their own. Blocks at the top of a page are drawn even if they are
+	// taller than the page
+	if _, ok := block.(*Table); !ok && y > c.MarginTop && y+block.getHeight(r, width) > c.GetContentBottom() {
+		c.AddPage()
+		y = c.Y
+	}
+	return block.draw(r, c.MarginLeft, y, width)
+}
+
+func getOrDefault(value, defaultValue float64) float64 {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
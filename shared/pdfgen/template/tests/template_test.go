@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/template"
+)
+
+func TestTemplateValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		blocks  []template.Block
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			blocks: []template.Block{
+				&template.Header{Title: "INVOICE"},
+				&template.Row{Columns: []template.Column{
+					{Width: 120, Blocks: []template.Block{&template.CustomerDetails{Customer: &models.Customer{}}}},
+					{Blocks: []template.Block{&template.KeyValues{Pairs: []template.KeyValue{{Label: "Date:", Value: "today"}}}}},
+				}},
+				&template.Table{Headers: []string{"SKU", "QTY"}, CellWidths: []float64{50, 20}, Rows: [][]string{{"A", "1"}}},
+				&template.Footer{Text: "footer"},
+			},
+		},
+		{
+			name:    "table row with a missing cell",
+			blocks:  []template.Block{&template.Table{Headers: []string{"SKU", "QTY"}, CellWidths: []float64{50, 20}, Rows: [][]string{{"A"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "table without cell widths",
+			blocks:  []template.Block{&template.Table{Headers: []string{"SKU"}}},
+			wantErr: true,
+		},
+		{
+			name:    "totals without values",
+			blocks:  []template.Block{&template.Totals{Labels: []string{"TOTAL"}}},
+			wantErr: true,
+		},
+		{
+			name:    "two footers",
+			blocks:  []template.Block{&template.Footer{}, &template.Footer{}},
+			wantErr: true,
+		},
+		{
+			name: "footer in a row",
+			blocks: []template.Block{&template.Row{Columns: []template.Column{
+				{Blocks: []template.Block{&template.Footer{}}},
+			}}},
+			wantErr: true,
+		},
+		{
+			name: "customer details in a row without a customer",
+			blocks: []template.Block{&template.Row{Columns: []template.Column{
+				{Blocks: []template.Block{&template.CustomerDetails{}}},
+			}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&template.Template{Blocks: tt.blocks}).Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/company_details"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/mocks"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/layout"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/template"
+)
+
+// Run with -update to rewrite the golden files after an intended change of a layout:
+//
+//	go test ./shared/pdfgen/tests -update
+//
+// The text golden files are always compared and cover the content and the position of every text. The page
+// rasters are opt-in: they are only compared, and written, with -raster since they depend on the installed
+// pdftoppm (poppler). The golden rasters are not checked in yet, so the default run has no raster coverage.
+// Generate them once on a machine with poppler and commit the png files of testdata/golden:
+//
+//	go test ./shared/pdfgen/tests -raster -update
+var (
+	update = flag.Bool("update", false, "update the golden files")
+	raster = flag.Bool("raster", false, "compare the pages to the golden rasters, requires pdftoppm")
+)
+
+// Max fraction of the pixels of a page that can differ from the golden raster
+const maxRasterDifference = 0.001
+
+// TestMain sets fixed company details. The logo is left empty so the tests do not download it.
+func TestMain(m *testing.M) {
+	flag.Parse()
+	company_details.COMPANYNAME = "AHS Chemicals"
+	company_details.COMPANYURL = "https://example.com"
+	company_details.COMPANYEMAIL = "orders@example.com"
+	company_details.COMPANYPHONE = "559-555-0100"
+	company_details.COMPANYADDRESSLINE1 = "100 Industrial Way"
+	company_details.COMPANYADDRESSLINE2 = "Fresno, CA 93725"
+	os.Exit(m.Run())
+}
+
+type templateLayout interface {
+	ToTemplate() *template.Template
+}
+
+// getGoldenLayouts returns every layout rendered from the mock data. The layouts with the current time in
+// their data get the mock time instead.
+func getGoldenLayouts() map[string]templateLayout {
+	order := mocks.CreateMockOrder(3)
+	customer := mocks.CreateMockCustomer()
+	transactions := mocks.CreateMockAccountTransactions(customer.ID)
+
+	cancellationSummary := layout.NewCancellationSummary([]*models.Order{order, mocks.CreateMockOrder(2)})
+	cancellationSummary.Date = mocks.MockTime.Format("01/02/2006")
+	cancellationSummary.Time = mocks.MockTime.Format("03:04:05 PM")
+
+	paidOrder := mocks.CreateMockOrder(3)
+	paidOrder.SetStatus(constants.OrderStatusDelivered)
+	paidOrder.Paid = true
+	cancelledOrder := mocks.CreateMockOrder(3)
+	cancelledOrder.SetStatus(constants.OrderStatusCancelled)
+	packingSlipCopy := layout.NewPackingSlip(paidOrder)
+	packingSlipCopy.Stamp = layout.StampCopy
+	storedDelivery := mocks.CreateMockDelivery(3)
+	storedDelivery.ImageURLs = []string{"https://example.com/delivery_images/image-1.png", "https://example.com/delivery_images/image-2.png"}
+	contactSheetManifest := layout.NewShippingManifest(storedDelivery)
+	contactSheetManifest.ShowContactSheet = true
+	provenDelivery := mocks.CreateMockDelivery(3)
+	provenDelivery.Order.Customer.Latitude, provenDelivery.Order.Customer.Longitude = 36.1716, -115.1391
+	provenDelivery.Location = &models.GeoLocation{Latitude: 36.1723, Longitude: -115.1401, Accuracy: 12}
+	provenDelivery.CapturedAt = mocks.MockTime
+	provenDelivery.Proof = models.NewProofOfDelivery(provenDelivery, constants.DefaultDeliveryRadiusMeters)
+	placardedRoute := mocks.CreateMockDeliveryRoute(3)
+	placardedRoute.LoadWarnings = models.NewVehicleLoad(mocks.CreateMockVehicle(), placardedRoute.DeliveryDate, placardedRoute.GetOrders()).GetWarnings()
+
+	return map[string]templateLayout{
+		"purchase_order":                      layout.NewPurchaseOrder(order),
+		"purchase_order_multi_page":           layout.NewPurchaseOrder(mocks.CreateMockOrder(40)),
+		"invoice":                             layout.NewInvoice(order, "1001"),
+		"invoice_paid":                        layout.NewInvoice(paidOrder, "1001"),
+		"purchase_order_cancelled":            layout.NewPurchaseOrder(cancelledOrder),
+		"packing_slip_copy":                   packingSlipCopy,
+		"order_revenue_report":                layout.NewOrderRevenueReport(order, "1001"),
+		"shipping_manifest":                   layout.NewShippingManifest(mocks.CreateMockDelivery(3)),
+		"shipping_manifest_contact_sheet":     contactSheetManifest,
+		"shipping_manifest_proof_of_delivery": layout.NewShippingManifest(provenDelivery),
+		"cancellation_summary":                cancellationSummary,
+		"pick_list":                           layout.NewPickList(order),
+		"route_sheet":                         layout.NewRouteSheet(mocks.CreateMockDeliveryRoute(3)),
+		"route_sheet_load_warnings":           layout.NewRouteSheet(placardedRoute),
+		"packing_slip":                        layout.NewPackingSlip(order),
+		"statement":                           layout.NewStatement(models.NewAccountStatement(customer, transactions, mocks.MockTime.AddDate(0, -3, 0), mocks.MockTime)),
+		"ar_aging_report":                     layout.NewARAgingReport(models.NewARAgingReport([]*models.Customer{customer}, transactions, mocks.MockTime)),
+		"table_report": &layout.TableReport{
+			Title:         "SALES BY BRAND",
+			Subtitle:      "March 2025",
+			Headers:       []string{"BRAND", "UNITS", "SALES"},
+			CellWidths:    []float64{80, 40, 60},
+			TableValues:   [][]string{{"Acme", "600", "$77,994.00"}},
+			SummaryLabels: []string{"TOTAL SALES"},
+			SummaryValues: []string{"$77,994.00"},
+		},
+	}
+}
+
+func renderGoldenLayout(t *testing.T, l templateLayout) []byte {
+	t.Helper()
+	tmpl := l.ToTemplate()
+	tmpl.CreatedAt = mocks.MockTime
+	pdf, err := tmpl.RenderToPDF()
+	if err != nil {
+		t.Fatalf("RenderToPDF() error = %v", err)
+	}
+	return pdf
+}
+
+func TestLayoutsAreDeterministic(t *testing.T) {
+	for name, l := range getGoldenLayouts() {
+		t.Run(name, func(t *testing.T) {
+			if !bytes.Equal(renderGoldenLayout(t, l), renderGoldenLayout(t, l)) {
+				t.Error("rendering the same layout twice generated different pdfs")
+			}
+		})
+	}
+}
+
+func TestLayoutsGolden(t *testing.T) {
+	for name, l := range getGoldenLayouts() {
+		t.Run(name, func(t *testing.T) {
+			pdf := renderGoldenLayout(t, l)
+			compareGoldenText(t, name, pdf)
+			if *raster {
+				compareGoldenRasters(t, name, pdf)
+			}
+		})
+	}
+}
+
+func compareGoldenText(t *testing.T, name string, pdf []byte) {
+	t.Helper()
+	text, err := extractPDFText(pdf)
+	if err != nil {
+		t.Fatalf("extractPDFText() error = %v", err)
+	}
+	//An empty golden file would match a pdf the text cannot be extracted from
+	pages := strings.Split(text, "--- page ")[1:]
+	if len(pages) == 0 {
+		t.Fatal("no pages were extracted from the pdf")
+	}
+	for i, page := range pages {
+		if strings.Count(page, "\n") < 2 {
+			t.Fatalf("no text was extracted from page %d", i+1)
+		}
+	}
+	path := filepath.Join("testdata", "golden", name+".txt")
+	if *update {
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing golden file, run the tests with -update: %v", err)
+	}
+	if text != string(golden) {
+		t.Errorf("text of %s does not match %s, run the tests with -update if the change is intended\ngot:\n%s", name, path, text)
+	}
+}
+
+func compareGoldenRasters(t *testing.T, name string, pdf []byte) {
+	t.Helper()
+	pages, err := rasterizePDF(pdf, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pages == nil {
+		t.Fatal("pdftoppm is not installed, it is required by -raster")
+	}
+	if *update {
+		stale, _ := filepath.Glob(filepath.Join("testdata", "golden", name+"-page-*.png"))
+		for _, path := range stale {
+			os.Remove(path)
+		}
+		for i, page := range pages {
+			if err := os.WriteFile(getGoldenRasterPath(name, i+1), page, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return
+	}
+	for i, page := range pages {
+		golden, err := os.ReadFile(getGoldenRasterPath(name, i+1))
+		if err != nil {
+			t.Fatalf("missing golden raster of page %d, generate the rasters with -raster -update and commit them: %v", i+1, err)
+		}
+		difference, err := getRasterDifference(page, golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if difference > maxRasterDifference {
+			t.Errorf("page %d differs from the golden raster by %.2f%% of the pixels", i+1, difference*100)
+		}
+	}
+	if extra, _ := os.Stat(getGoldenRasterPath(name, len(pages)+1)); extra != nil {
+		t.Errorf("pdf has %d pages but there are more golden rasters", len(pages))
+	}
+}
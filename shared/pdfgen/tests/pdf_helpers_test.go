@@ -0,0 +1,162 @@
+package tests
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+var (
+	// The page objects are matched up to their endobj, the nested dictionaries of the /Group and the link
+	// /Annots cannot be matched by a regexp
+	pageRegexp         = regexp.MustCompile(`(?s)<</Type /Page\n(.*?)\nendobj`)
+	pageContentsRegexp = regexp.MustCompile(`/Contents (\d+) 0 R`)
+	textRegexp         = regexp.MustCompile(`BT (-?[\d.]+) (-?[\d.]+) Td \(((?:\\.|[^\\)])*)\) Tj ET`)
+)
+
+// extractPDFText returns the text drawn on every page of a pdf generated by gofpdf, one line per text with its
+// position in points. Only the embedded UTF-8 fonts used by the layouts are supported.
+func extractPDFText(pdf []byte) (string, error) {
+	var builder strings.Builder
+	for page, pageMatch := range pageRegexp.FindAllSubmatch(pdf, -1) {
+		match := pageContentsRegexp.FindSubmatch(pageMatch[1])
+		if match == nil {
+			return "", fmt.Errorf("page %d: contents not found", page+1)
+		}
+		content, err := getPDFObjectStream(pdf, string(match[1]))
+		if err != nil {
+			return "", fmt.Errorf("page %d: %v", page+1, err)
+		}
+		fmt.Fprintf(&builder, "--- page %d ---\n", page+1)
+		for _, text := range textRegexp.FindAllSubmatch(content, -1) {
+			fmt.Fprintf(&builder, "%s %s %s\n", text[1], text[2], decodePDFString(text[3]))
+		}
+	}
+	return builder.String(), nil
+}
+
+// getPDFObjectStream returns the decompressed stream of the object.
+func getPDFObjectStream(pdf []byte, objectNumber string) ([]byte, error) {
+	objectRegexp := regexp.MustCompile(`(?s)\n` + objectNumber + ` 0 obj\n<<([^>]*)>>\nstream\n(.*?)\nendstream`)
+	match := objectRegexp.FindSubmatch(pdf)
+	if match == nil {
+		return nil, fmt.Errorf("object %s not found", objectNumber)
+	}
+	if !bytes.Contains(match[1], []byte("/FlateDecode")) {
+		return match[2], nil
+	}
+	reader, err := zlib.NewReader(bytes.NewReader(match[2]))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// decodePDFString unescapes a pdf string literal and decodes the UTF-16BE text of the embedded fonts.
+func decodePDFString(escaped []byte) string {
+	raw := make([]byte, 0, len(escaped))
+	for i := 0; i < len(escaped); i++ {
+		if escaped[i] == '\\' && i+1 < len(escaped) {
+			i++
+			switch escaped[i] {
+			case 'r':
+				raw = append(raw, '\r')
+			case 'n':
+				raw = append(raw, '\n')
+			default:
+				raw = append(raw, escaped[i])
+			}
+			continue
+		}
+		raw = append(raw, escaped[i])
+	}
+	units := make([]uint16, len(raw)/2)
+	for i := range units {
+		units[i] = uint16(raw[2*i])<<8 | uint16(raw[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
+
+// rasterizePDF renders every page of the pdf to a png with pdftoppm. Returns nil when pdftoppm is not
+// installed.
+func rasterizePDF(pdf []byte, dir string) ([][]byte, error) {
+	if _, err := exec.LookPath("pdftoppm"); err != nil {
+		return nil, nil
+	}
+	pdfPath := filepath.Join(dir, "document.pdf")
+	if err := os.WriteFile(pdfPath, pdf, 0644); err != nil {
+		return nil, err
+	}
+	if output, err := exec.Command("pdftoppm", "-r", "50", "-png", pdfPath, filepath.Join(dir, "page")).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("pdftoppm failed: %v: %s", err, output)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "page-*.png"))
+	if err != nil {
+		return nil, err
+	}
+	// pdftoppm pads the page numbers to the same width so sorting by name is sorting by page
+	sort.Strings(paths)
+	pages := make([][]byte, len(paths))
+	for i, path := range paths {
+		if pages[i], err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+// getRasterDifference returns the fraction of the pixels of the two pngs that differ noticeably. Images of
+// different sizes are completely different.
+func getRasterDifference(a, b []byte) (float64, error) {
+	imageA, err := png.Decode(bytes.NewReader(a))
+	if err != nil {
+		return 0, err
+	}
+	imageB, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		return 0, err
+	}
+	bounds := imageA.Bounds()
+	if bounds != imageB.Bounds() {
+		return 1, nil
+	}
+	different := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if !isSimilarColor(imageA, imageB, x, y) {
+				different++
+			}
+		}
+	}
+	return float64(different) / float64(bounds.Dx()*bounds.Dy()), nil
+}
+
+func isSimilarColor(a, b image.Image, x, y int) bool {
+	const tolerance = 0x1000 // Out of 0xffff, absorbs anti aliasing differences between pdftoppm versions
+	r1, g1, b1, _ := a.At(x, y).RGBA()
+	r2, g2, b2, _ := b.At(x, y).RGBA()
+	return absDiff(r1, r2) <= tolerance && absDiff(g1, g2) <= tolerance && absDiff(b1, b2) <= tolerance
+}
+
+func absDiff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func getGoldenRasterPath(name string, page int) string {
+	return filepath.Join("testdata", "golden", name+"-page-"+strconv.Itoa(page)+".png")
+}
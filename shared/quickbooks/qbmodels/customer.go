@@ -1,6 +1,7 @@
 package qbmodels
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
@@ -102,6 +103,13 @@ func (qb *QBCustomer) SetAddressInCustomer(c *models.Customer) {
 	c.State = qb.BillAddr.CountrySubDivisionCode
 	c.Zip = qb.BillAddr.PostalCode
 	c.Country = qb.BillAddr.Country
+	//Quickbooks geocodes the addresses it can, the coordinates are "INVALID" otherwise
+	latitude, latErr := strconv.ParseFloat(qb.BillAddr.Lat, 64)
+	longitude, longErr := strconv.ParseFloat(qb.BillAddr.Long, 64)
+	if latErr == nil && longErr == nil {
+		c.Latitude = latitude
+		c.Longitude = longitude
+	}
 }
 
 func (qb *QBCustomer) MapToCustomer() *models.Customer{
@@ -2,7 +2,9 @@ package qbmodels
 
 import (
 	"encoding/json"
+	"time"
 
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/quickbooks"
 )
@@ -19,6 +21,7 @@ type Invoice struct {
 	CustomField           []CustomField        `json:"CustomField,omitempty"`
 	TotalAmt              float64              `json:"TotalAmt,omitempty"`
 	Balance               float64              `json:"Balance,omitempty"`
+	TxnTaxDetail          *TxnTaxDetail        `json:"TxnTaxDetail,omitempty"`
 	ApplyTaxAfterDiscount bool                 `json:"ApplyTaxAfterDiscount,omitempty"`
 	PrintStatus           string               `json:"PrintStatus,omitempty"`
 	EmailStatus           string               `json:"EmailStatus,omitempty"`
@@ -58,6 +61,19 @@ func (i *Invoice) GetDocNumber() string {
 	return i.DocNumber
 }
 
+// IsPaid reports whether the invoice has been fully paid, i.e. it has an amount and no balance left.
+func (i *Invoice) IsPaid() bool {
+	return i.TotalAmt > 0 && i.Balance <= 0
+}
+
+// GetTotalTax returns the sales tax quickbooks calculated for the invoice, 0 if there is no tax detail.
+func (i *Invoice) GetTotalTax() float64 {
+	if i.TxnTaxDetail == nil {
+		return 0
+	}
+	return i.TxnTaxDetail.TotalTax
+}
+
 func (i *Invoice) AddLines(order *models.Order) {
 	invoiceLines := make([]Line, 0)
 	for _, item := range order.Items {
@@ -150,4 +166,28 @@ type QBInvoiceResponse struct {
 
 func (qb *QBInvoiceResponse) GetDocNumber() string {
 	return qb.Invoice.GetDocNumber()
-}
\ No newline at end of file
+}
+// ToAccountTransaction maps the invoice to a transaction on the customer account. The open balance is the
+// unpaid Balance of the invoice.
+func (i *Invoice) ToAccountTransaction() *models.AccountTransaction {
+	return &models.AccountTransaction{
+		ID:          i.ID,
+		CustomerID:  i.CustomerRef.Value,
+		Type:        constants.AccountTransactionTypeInvoice,
+		Number:      i.DocNumber,
+		Description: "Invoice #" + i.DocNumber,
+		Date:        parseQBDate(i.TxnDate),
+		DueDate:     parseQBDate(i.DueDate),
+		Amount:      i.TotalAmt,
+		OpenBalance: i.Balance,
+	}
+}
+
+// parseQBDate parses a quickbooks date (2006-01-02), zero time if the date is empty or invalid.
+func parseQBDate(date string) time.Time {
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
@@ -0,0 +1,80 @@
+package qbmodels
+
+import (
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/quickbooks"
+)
+
+// Payment is a customer payment received in quickbooks. Only the fields used by the customer statement
+// and the AR aging report are mapped.
+type Payment struct {
+	ID               string               `json:"Id,omitempty"`
+	CustomerRef      Reference            `json:"CustomerRef"`
+	TxnDate          string               `json:"TxnDate,omitempty"`
+	PaymentRefNum    string               `json:"PaymentRefNum,omitempty"`
+	PrivateNote      string               `json:"PrivateNote,omitempty"`
+	TotalAmt         float64              `json:"TotalAmt"`
+	UnappliedAmt     float64              `json:"UnappliedAmt,omitempty"`
+	PaymentMethodRef *Reference           `json:"PaymentMethodRef,omitempty"`
+	MetaData         *quickbooks.MetaData `json:"MetaData,omitempty"`
+}
+
+// ToAccountTransaction maps the payment to a transaction on the customer account. The open balance is the
+// amount not applied to any invoice.
+func (p *Payment) ToAccountTransaction() *models.AccountTransaction {
+	description := "Payment"
+	if p.PaymentRefNum != "" {
+		description += " - Ref #" + p.PaymentRefNum
+	}
+	return &models.AccountTransaction{
+		ID:          p.ID,
+		CustomerID:  p.CustomerRef.Value,
+		Type:        constants.AccountTransactionTypePayment,
+		Number:      p.PaymentRefNum,
+		Description: description,
+		Date:        parseQBDate(p.TxnDate),
+		Amount:      p.TotalAmt,
+		OpenBalance: p.UnappliedAmt,
+	}
+}
+
+type QBPaymentsResponse struct {
+	QueryResponse struct {
+		Payment []Payment `json:"Payment"`
+	} `json:"QueryResponse"`
+}
+
+// CreditMemo is a credit given to a customer in quickbooks. Only the fields used by the customer statement
+// and the AR aging report are mapped.
+type CreditMemo struct {
+	ID              string               `json:"Id,omitempty"`
+	CustomerRef     Reference            `json:"CustomerRef"`
+	TxnDate         string               `json:"TxnDate,omitempty"`
+	DocNumber       string               `json:"DocNumber,omitempty"`
+	PrivateNote     string               `json:"PrivateNote,omitempty"`
+	TotalAmt        float64              `json:"TotalAmt"`
+	RemainingCredit float64              `json:"RemainingCredit,omitempty"`
+	MetaData        *quickbooks.MetaData `json:"MetaData,omitempty"`
+}
+
+// ToAccountTransaction maps the credit memo to a transaction on the customer account. The open balance is
+// the credit not applied to any invoice.
+func (cm *CreditMemo) ToAccountTransaction() *models.AccountTransaction {
+	return &models.AccountTransaction{
+		ID:          cm.ID,
+		CustomerID:  cm.CustomerRef.Value,
+		Type:        constants.AccountTransactionTypeCredit,
+		Number:      cm.DocNumber,
+		Description: "Credit Memo #" + cm.DocNumber,
+		Date:        parseQBDate(cm.TxnDate),
+		Amount:      cm.TotalAmt,
+		OpenBalance: cm.RemainingCredit,
+	}
+}
+
+type QBCreditMemosResponse struct {
+	QueryResponse struct {
+		CreditMemo []CreditMemo `json:"CreditMemo"`
+	} `json:"QueryResponse"`
+}
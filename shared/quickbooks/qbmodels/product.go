@@ -7,15 +7,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/quickbooks"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/units"
 )
 
 var (
-	Brands = map[string]struct{}{
-		"microtech": {},
-		"problend":  {},
-	}
 	slugSanitizer = regexp.MustCompile(`[^a-z0-9]+`)
 )
 
@@ -62,56 +60,165 @@ type QBItemCustomField struct {
 	Value string `json:"StringValue"` // Field value
 }
 
-// parseName parses product name into firestore product
+// Names of the custom fields of the quickbooks items with the structured attributes of the product. The
+// custom fields win over the legacy `Brand - Name` and `SKU - Size - SizeUnit - PackOf` strings.
+const (
+	ItemCustomFieldBrand    = "Brand"
+	ItemCustomFieldSize     = "Size"
+	ItemCustomFieldUnit     = "Unit"
+	ItemCustomFieldPack     = "Pack"
+	ItemCustomFieldHazmat   = "Hazmat"
+	ItemCustomFieldCategory = "Category"
+	ItemCustomFieldDensity  = "Density" // Pounds per gallon
+)
+
+// parseNameInto parses product name into firestore product
 //
-// Quickbooks does not allow storing additional properties like product brand.
-// So each product is inputted as one entire string: `BrandName - ProductName` in quickbooks manually. We parse
-// it from the delimeter '-' to get the appropriate brand and product name
-func (qb *QBItem) parseNameInto(product *models.Product) {
-	splitString := strings.SplitN(qb.Name, "-", 2)
-	if len(splitString) != 2 {
-		product.Name = qb.Name
+// Items without a brand custom field are inputted as one entire string: `BrandName - ProductName` in quickbooks
+// manually. The brand prefix is removed from the name when it is a brand of the catalog or the brand of the
+// product.
+func (qb *QBItem) parseNameInto(product *models.Product, brands *models.BrandCatalog) {
+	product.Name = qb.Name
+	brand, name, ok := qb.splitName()
+	if !ok {
 		return
 	}
-	parsedBrand := strings.TrimSpace(splitString[0])
-	parsedProductName := strings.TrimSpace(splitString[1])
+	if _, found := brands.Find(brand); found || strings.EqualFold(brand, product.Brand) {
+		product.Name = name
+	}
+}
 
-	if _, ok := Brands[strings.ToLower(parsedBrand)]; ok {
-		product.Brand = parsedBrand
-		product.Name = parsedProductName
-	} else {
-		product.Name = qb.Name
+func (qb *QBItem) splitName() (string, string, bool) {
+	splitString := strings.SplitN(qb.Name, "-", 2)
+	if len(splitString) != 2 {
+		return "", "", false
 	}
+	return strings.TrimSpace(splitString[0]), strings.TrimSpace(splitString[1]), true
 }
 
-// parseSKUInto parses product SKU into Firestore Product
+// getCustomFieldAttributes parses the attributes of the custom fields of the item. Empty custom fields are not
+// set.
 //
-// Quickbooks does not allow storing additional properties like size unit, size or pack of.
-// So each product is inputted as one entire string: `SKU - Size - SizeUnit - PackOf` in quickbooks manually. We parse
-// it from the delimeter '-' to get the appropriate SKU, Size, SizeUnit and PackOf. If the delimeter is not present or
-// there is a malformed string, we set the default values as "QBSKU - 0.0 - GAL - 1"
-func (qb *QBItem) parseSKUInto(product *models.Product) {
-	splitString := strings.SplitN(qb.SKU, "-", 4)
-	if len(splitString) == 4 {
-		size, err := strconv.ParseFloat(splitString[1], 64)
-		if err != nil {
-			size = 0.0
+// Returns:
+//   - *models.ProductAttributes: the attributes, nil if the item has none of the custom fields
+//   - []*models.ProductSyncIssue: the custom fields that could not be parsed
+func (qb *QBItem) getCustomFieldAttributes(product *models.Product) (*models.ProductAttributes, []*models.ProductSyncIssue) {
+	attributes := &models.ProductAttributes{ProductID: qb.ID, Source: constants.ProductAttributeSourceCustomField}
+	issues := make([]*models.ProductSyncIssue, 0)
+	addIssue := func(field, value string, err error) {
+		issues = append(issues, models.NewProductSyncIssue(product, field, attributes.Source, constants.ProductSyncIssueError, value, err.Error()))
+	}
+	found := false
+	for _, customField := range qb.CustomField {
+		value := strings.TrimSpace(customField.Value)
+		if value == "" {
+			continue
 		}
-		packOf, err := strconv.Atoi(splitString[3])
-		if err != nil {
-			packOf = 0
+		switch strings.TrimSpace(customField.Name) {
+		case ItemCustomFieldBrand:
+			attributes.Brand = &value
+		case ItemCustomFieldCategory:
+			attributes.Category = &value
+		case ItemCustomFieldSize:
+			if size, err := strconv.ParseFloat(value, 64); err != nil {
+				addIssue(models.ProductAttributeSize, value, fmt.Errorf("Size %q is not a number", value))
+			} else {
+				attributes.Size = &size
+			}
+		case ItemCustomFieldUnit:
+			if unit, err := units.Parse(value); err != nil {
+				addIssue(models.ProductAttributeSizeUnit, value, err)
+			} else {
+				attributes.SizeUnit = &unit.Name
+			}
+		case ItemCustomFieldPack:
+			if packOf, err := strconv.Atoi(value); err != nil {
+				addIssue(models.ProductAttributePackOf, value, fmt.Errorf("Pack %q is not a whole number", value))
+			} else {
+				attributes.PackOf = &packOf
+			}
+		case ItemCustomFieldHazmat:
+			if hazardous, err := parseYesNo(value); err != nil {
+				addIssue(models.ProductAttributeHazardous, value, err)
+			} else {
+				attributes.Hazardous = &hazardous
+			}
+		case ItemCustomFieldDensity:
+			if density, err := strconv.ParseFloat(value, 64); err != nil {
+				addIssue(models.ProductAttributeDensity, value, fmt.Errorf("Density %q is not a number", value))
+			} else {
+				attributes.Density = &density
+			}
+		default:
+			continue
 		}
+		found = true
+	}
+	if !found {
+		return nil, issues
+	}
+	return attributes, issues
+}
+
+func parseYesNo(value string) (bool, error) {
+	switch strings.ToUpper(value) {
+	case "YES", "Y", "TRUE", "1":
+		return true, nil
+	case "NO", "N", "FALSE", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("Hazmat %q is not yes or no", value)
+}
 
-		product.SKU = splitString[0]
-		product.Size = size
-		product.SizeUnit = splitString[2]
-		product.PackOf = packOf
+// getLegacyAttributes parses the attributes of the name, the SKU and the parent of the item.
+//
+// Before the custom fields each product was inputted with its SKU as one entire string:
+// `SKU - Size - SizeUnit - PackOf`. A SKU without the 4 parts is kept as is, without size, unit or pack.
+//
+// Returns:
+//   - *models.ProductAttributes: the attributes
+//   - []*models.ProductSyncIssue: the parts of the SKU that could not be parsed
+func (qb *QBItem) getLegacyAttributes(product *models.Product, brands *models.BrandCatalog) (*models.ProductAttributes, []*models.ProductSyncIssue) {
+	attributes := &models.ProductAttributes{ProductID: qb.ID, Source: constants.ProductAttributeSourceLegacy}
+	issues := make([]*models.ProductSyncIssue, 0)
+	addIssue := func(field, value string, err error) {
+		issues = append(issues, models.NewProductSyncIssue(product, field, attributes.Source, constants.ProductSyncIssueError, value, err.Error()))
+	}
+
+	if prefix, _, ok := qb.splitName(); ok {
+		if brand, found := brands.Find(prefix); found {
+			attributes.Brand = &brand.Name
+		}
+	}
+	if qb.ParentRef != nil {
+		attributes.Category = &qb.ParentRef.Name
+	}
+
+	sku := qb.SKU
+	splitString := strings.SplitN(qb.SKU, "-", 4)
+	if len(splitString) != 4 {
+		attributes.SKU = &sku
+		return attributes, issues
+	}
+	sku = strings.TrimSpace(splitString[0])
+	attributes.SKU = &sku
+	sizeValue, unitValue, packValue := strings.TrimSpace(splitString[1]), strings.TrimSpace(splitString[2]), strings.TrimSpace(splitString[3])
+	if size, err := strconv.ParseFloat(sizeValue, 64); err != nil {
+		addIssue(models.ProductAttributeSize, sizeValue, fmt.Errorf("Size %q of the SKU %q is not a number", sizeValue, qb.SKU))
+	} else {
+		attributes.Size = &size
+	}
+	if unit, err := units.Parse(unitValue); err != nil {
+		addIssue(models.ProductAttributeSizeUnit, unitValue, fmt.Errorf("SKU %q: %w", qb.SKU, err))
+	} else {
+		attributes.SizeUnit = &unit.Name
+	}
+	if packOf, err := strconv.Atoi(packValue); err != nil {
+		addIssue(models.ProductAttributePackOf, packValue, fmt.Errorf("Pack %q of the SKU %q is not a whole number", packValue, qb.SKU))
 	} else {
-		product.SKU = qb.SKU
-		product.Size = 0.0
-		product.SizeUnit = "GAL"
-		product.PackOf = 1
+		attributes.PackOf = &packOf
 	}
+	return attributes, issues
 }
 
 // parseSlugAndNameKeyInto parses product name into firestore product
@@ -126,14 +233,21 @@ func (qb *QBItem) parseSlugAndNameKeyInto(product *models.Product) {
 	product.Slug = fmt.Sprintf("%s-%s", slug, qb.ID)
 }
 
-func (qb *QBItem) parseCategoryInto(product *models.Product) {
-	if qb.ParentRef == nil {
-		return
-	}
-	product.Category = qb.ParentRef.Name
-}
-
-func (qb *QBItem) MapToProduct() *models.Product {
+// MapToProduct maps a quickbooks item to a firestore product. The structured attributes come from the override
+// document of the product first, then the custom fields of the item, then the legacy name and SKU strings.
+//
+// The brand is replaced by the display name of the matching brand of the catalog, a brand that is not in the
+// catalog is reported.
+//
+// Parameters:
+//   - override: the override document of the product in firestore, nil if there is none
+//   - brands: the brand catalog
+//
+// Returns:
+//   - *models.Product: the mapped product
+//   - []*models.ProductSyncIssue: the attributes that could not be parsed or are missing. With an error the
+//     attributes of the product can not be trusted, see models.HasProductSyncErrors
+func (qb *QBItem) MapToProduct(override *models.ProductAttributes, brands *models.BrandCatalog) (*models.Product, []*models.ProductSyncIssue) {
 	product := &models.Product{
 		ID:            qb.ID,
 		IsActive:      qb.Active,
@@ -143,10 +257,20 @@ func (qb *QBItem) MapToProduct() *models.Product {
 		Price:         qb.UnitPrice,
 		PurchasePrice: qb.PurchaseCost,
 		Desc:          qb.Description,
+		Name:          qb.Name,
+	}
+	customFieldAttributes, customFieldIssues := qb.getCustomFieldAttributes(product)
+	legacyAttributes, legacyIssues := qb.getLegacyAttributes(product, brands)
+	issues := models.ApplyProductAttributes(product, []*models.ProductAttributes{override, customFieldAttributes, legacyAttributes}, append(customFieldIssues, legacyIssues...))
+	if product.Brand != "" {
+		if brand, found := brands.Find(product.Brand); found {
+			product.Brand = brand.Name
+		} else {
+			message := fmt.Sprintf("Brand %q is not an active brand of the catalog", product.Brand)
+			issues = append(issues, models.NewProductSyncIssue(product, models.ProductAttributeBrand, "", constants.ProductSyncIssueWarning, product.Brand, message))
+		}
 	}
-	qb.parseNameInto(product)
-	qb.parseSKUInto(product)
+	qb.parseNameInto(product, brands)
 	qb.parseSlugAndNameKeyInto(product)
-	qb.parseCategoryInto(product)
-	return product
+	return product, issues
 }
@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/quickbooks/qbmodels"
+)
+
+var testBrands = models.NewBrandCatalog([]*models.Brand{
+	{ID: "microtech", Name: "Microtech", Aliases: []string{"Micro Tech"}, IsActive: true},
+	{ID: "acme", Name: "Acme", IsActive: true},
+})
+
+func getIssueFields(issues []*models.ProductSyncIssue, severity string) map[string]string {
+	fields := make(map[string]string)
+	for _, issue := range issues {
+		if issue.Severity == severity {
+			fields[issue.Field] = issue.Source
+		}
+	}
+	return fields
+}
+
+func TestMapToProductFromLegacyStrings(t *testing.T) {
+	item := &qbmodels.QBItem{ID: "1", Name: "Microtech - Floor Cleaner", SKU: "MTFC - 5 - GAL - 4", ParentRef: &qbmodels.QBItemRef{Name: "Cleaners"}}
+	product, issues := item.MapToProduct(nil, testBrands)
+	if models.HasProductSyncErrors(issues) {
+		t.Fatalf("expected no errors, got %+v", getIssueFields(issues, constants.ProductSyncIssueError))
+	}
+	if product.Brand != "Microtech" || product.Name != "Floor Cleaner" || product.SKU != "MTFC" || product.Size != 5 || product.SizeUnit != "GAL" || product.PackOf != 4 || product.Category != "Cleaners" {
+		t.Errorf("unexpected product %+v", product)
+	}
+	if _, ok := getIssueFields(issues, constants.ProductSyncIssueWarning)[models.ProductAttributeHazardous]; !ok || !product.Hazardous {
+		t.Error("expected a warning for the missing hazmat and the product to default to hazardous")
+	}
+}
+
+func TestMapToProductKeepsStoredSizeUnit(t *testing.T) {
+	for _, sku := range []string{"MTFC - 5 - GAL - 4", "MTFC - 5 - gallons - 4"} {
+		item := &qbmodels.QBItem{ID: "1", Name: "Microtech - Floor Cleaner", SKU: sku, ParentRef: &qbmodels.QBItemRef{Name: "Cleaners"}}
+		product, _ := item.MapToProduct(nil, testBrands)
+		stored := *product
+		stored.SizeUnit = "GAL"
+		if updated := models.GetUpdatedProductDetails(product, &stored); updated["sizeUnit"] != nil {
+			t.Errorf("%q: expected the stored GAL product to sync unchanged, got %v", sku, updated)
+		}
+	}
+}
+
+func TestMapToProductReportsMalformedSKU(t *testing.T) {
+	tests := []struct {
+		name   string
+		sku    string
+		fields []string
+	}{
+		{"no size", "MT-FC", []string{models.ProductAttributeSize, models.ProductAttributeSizeUnit, models.ProductAttributePackOf}},
+		{"size not a number", "MT-five-GAL-4", []string{models.ProductAttributeSize}},
+		{"unknown unit", "MT-5-BUCKET-4", []string{models.ProductAttributeSizeUnit}},
+		{"pack not a number", "MT-5-GAL-four", []string{models.ProductAttributePackOf}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, issues := (&qbmodels.QBItem{ID: "1", Name: "Cleaner", SKU: test.sku}).MapToProduct(nil, testBrands)
+			errors := getIssueFields(issues, constants.ProductSyncIssueError)
+			if len(errors) != len(test.fields) {
+				t.Errorf("expected errors for %v, got %v", test.fields, errors)
+			}
+			for _, field := range test.fields {
+				if _, ok := errors[field]; !ok {
+					t.Errorf("expected an error for %s, got %v", field, errors)
+				}
+			}
+		})
+	}
+}
+
+func TestMapToProductSourcePriority(t *testing.T) {
+	item := &qbmodels.QBItem{
+		ID:   "1",
+		Name: "Acme - Degreaser",
+		SKU:  "ACDG - 1 - BUCKET - 2",
+		CustomField: []qbmodels.QBItemCustomField{
+			{Name: "Brand", Value: "Acme"},
+			{Name: "Size", Value: "2.5"},
+			{Name: "Unit", Value: "qt"},
+			{Name: "Hazmat", Value: "No"},
+			{Name: "Density", Value: "abc"},
+		},
+	}
+	packOf, density := 6, 9.5
+	override := &models.ProductAttributes{PackOf: &packOf, Density: &density}
+
+	product, issues := item.MapToProduct(override, testBrands)
+	if models.HasProductSyncErrors(issues) {
+		t.Fatalf("expected the invalid values to be replaced by the higher sources, got %v", getIssueFields(issues, constants.ProductSyncIssueError))
+	}
+	if product.Brand != "Acme" || product.Name != "Degreaser" || product.SKU != "ACDG" || product.Size != 2.5 || product.SizeUnit != "QT" || product.PackOf != 6 || product.Hazardous || product.Density != 9.5 {
+		t.Errorf("unexpected product %+v", product)
+	}
+
+	_, issues = item.MapToProduct(nil, testBrands)
+	errors := getIssueFields(issues, constants.ProductSyncIssueError)
+	if errors[models.ProductAttributeDensity] != constants.ProductAttributeSourceCustomField || errors[models.ProductAttributePackOf] != "" {
+		t.Errorf("expected an error for the density custom field only, got %v", errors)
+	}
+	if _, ok := errors[models.ProductAttributeSizeUnit]; ok {
+		t.Errorf("expected no error for the legacy unit replaced by the unit custom field, got %v", errors)
+	}
+}
+
+func TestMapToProductMatchesTheBrandCatalog(t *testing.T) {
+	product, issues := (&qbmodels.QBItem{ID: "1", Name: "micro tech - Glass Cleaner", SKU: "MTGC-1-GAL-4"}).MapToProduct(nil, testBrands)
+	if product.Brand != "Microtech" || product.Name != "Glass Cleaner" {
+		t.Errorf("expected the alias to be the Microtech brand, got %q and %q", product.Brand, product.Name)
+	}
+	if _, ok := getIssueFields(issues, constants.ProductSyncIssueWarning)[models.ProductAttributeBrand]; ok {
+		t.Error("expected no brand warning for a brand of the catalog")
+	}
+
+	item := &qbmodels.QBItem{
+		ID:          "2",
+		Name:        "Unknown - Glass Cleaner",
+		SKU:         "UGC-1-GAL-4",
+		CustomField: []qbmodels.QBItemCustomField{{Name: "Brand", Value: "Unknown"}},
+	}
+	product, issues = item.MapToProduct(nil, testBrands)
+	if product.Brand != "Unknown" || product.Name != "Glass Cleaner" {
+		t.Errorf("expected the brand of the custom field to be kept, got %q and %q", product.Brand, product.Name)
+	}
+	if _, ok := getIssueFields(issues, constants.ProductSyncIssueWarning)[models.ProductAttributeBrand]; !ok {
+		t.Error("expected a warning for a brand that is not in the catalog")
+	}
+}
@@ -1,6 +1,30 @@
 package qbmodels
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Entity names sent by quickbooks in the webhook notifications.
+const (
+	EntityCustomer = "Customer"
+	EntityItem     = "Item"
+)
+
+// Operations sent by quickbooks in the webhook notifications.
+const (
+	OperationCreate  = "Create"
+	OperationUpdate  = "Update"
+	OperationDelete  = "Delete"
+	OperationMerge   = "Merge"
+	OperationVoid    = "Void"
+	OperationEmailed = "Emailed"
+)
 
 type QBWebHookResp struct {
 	EventNotifications []EventNotification `json:"eventNotifications"`
@@ -15,13 +39,222 @@ type DataChangeEvent struct {
 	Entities []Entity `json:"entities"`
 }
 
+// Entity is a single change notification for one quickbooks entity.
+//
+// RealmID is not part of the entity in the quickbooks payload, it is copied from the parent
+// EventNotification so each entity can be published and processed on its own. DeletedID is
+// only sent for Merge operations and holds the ID of the entity that was merged into ID.
 type Entity struct {
+	RealmID        string `json:"realmId,omitempty"`
 	Name           string `json:"name"`
 	ID             string `json:"id"`
 	Operation      string `json:"operation"`
 	LastUpdatedUTC string `json:"lastUpdated"`
+	DeletedID      string `json:"deletedId,omitempty"`
 }
 
 func (e *Entity) ToBytes() ([]byte, error) {
 	return json.Marshal(e)
-}
\ No newline at end of file
+}
+
+// GetLastUpdatedTime parses the lastUpdated timestamp sent by quickbooks.
+func (e *Entity) GetLastUpdatedTime() (time.Time, error) {
+	lastUpdated, err := time.Parse(time.RFC3339, e.LastUpdatedUTC)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid lastUpdated %q for %s %s: %w", e.LastUpdatedUTC, e.Name, e.ID, err)
+	}
+	return lastUpdated.UTC(), nil
+}
+
+// GetKey returns the idempotency key of the entity: realm, entity name and entity id.
+// Firestore document ids cannot contain '/', so '|' is used as the separator.
+func (e *Entity) GetKey() string {
+	return fmt.Sprintf("%s|%s|%s", e.RealmID, e.Name, e.ID)
+}
+
+// ToEntities flattens all the notifications into a list of entities with the realm id set.
+func (r *QBWebHookResp) ToEntities() []Entity {
+	entities := make([]Entity, 0)
+	for _, notification := range r.EventNotifications {
+		for _, entity := range notification.DataChangeEvent.Entities {
+			entity.RealmID = notification.RealmID
+			entities = append(entities, entity)
+		}
+	}
+	return entities
+}
+
+// QBCloudEvent is a single event of the newer CloudEvents webhook payload format. The payload
+// is a json array of these events instead of the `eventNotifications` object.
+//
+// Reference: https://developer.intuit.com/app/developer/qbo/docs/develop/webhooks
+type QBCloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"` // e.g. "qbo.customer.created.v1"
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Time            string          `json:"time"`
+	IntuitEntityID  string          `json:"intuitentityid"`
+	IntuitAccountID string          `json:"intuitaccountid"` // realm id
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// cloudEventOperations maps the CloudEvents type verbs to the operations used by the
+// legacy payload so both formats are dispatched the same way.
+var cloudEventOperations = map[string]string{
+	"created": OperationCreate,
+	"updated": OperationUpdate,
+	"deleted": OperationDelete,
+	"merged":  OperationMerge,
+	"voided":  OperationVoid,
+	"emailed": OperationEmailed,
+}
+
+// ToEntity converts the cloud event to an Entity. The type is of the form
+// `qbo.<entity>.<operation>.<version>`. An operation without a legacy name is kept as sent so it is
+// reported as unsupported when processed instead of failing the whole payload.
+func (ce *QBCloudEvent) ToEntity() (*Entity, error) {
+	parts := strings.Split(ce.Type, ".")
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid cloud event type %q", ce.Type)
+	}
+	operation, ok := cloudEventOperations[strings.ToLower(parts[2])]
+	if !ok {
+		operation = parts[2]
+	}
+	entity := &Entity{
+		RealmID:        ce.IntuitAccountID,
+		Name:           getCloudEventEntityName(parts[1]),
+		ID:             ce.IntuitEntityID,
+		Operation:      operation,
+		LastUpdatedUTC: ce.Time,
+	}
+	if operation == OperationMerge && len(ce.Data) > 0 {
+		var data struct {
+			DeletedID string `json:"deletedId"`
+		}
+		if err := json.Unmarshal(ce.Data, &data); err == nil {
+			entity.DeletedID = data.DeletedID
+		}
+	}
+	return entity, nil
+}
+
+// getCloudEventEntityName converts the lowercase entity of the cloud event type to the entity
+// name used by the legacy payload. ("customer" -> "Customer")
+func getCloudEventEntityName(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// ParseWebhookPayload parses the body of a quickbooks webhook request into a flat list of entities.
+// Both the legacy `eventNotifications` payload and the CloudEvents payload (json array) are supported.
+// A cloud event with an invalid type is logged and skipped so it does not drop the other events.
+func ParseWebhookPayload(body []byte) ([]Entity, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil, errors.New("empty webhook payload")
+	}
+
+	if trimmed[0] == '[' {
+		var cloudEvents []QBCloudEvent
+		if err := json.Unmarshal(trimmed, &cloudEvents); err != nil {
+			return nil, fmt.Errorf("invalid cloud events webhook payload: %w", err)
+		}
+		entities := make([]Entity, 0, len(cloudEvents))
+		for _, cloudEvent := range cloudEvents {
+			entity, err := cloudEvent.ToEntity()
+			if err != nil {
+				log.Printf("Skipping webhook cloud event %s: %v", cloudEvent.ID, err)
+				continue
+			}
+			entities = append(entities, *entity)
+		}
+		return entities, nil
+	}
+
+	var webhookResp QBWebHookResp
+	if err := json.Unmarshal(trimmed, &webhookResp); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	return webhookResp.ToEntities(), nil
+}
+
+// WebhookClaimTimeout is how long a notification stays claimed while it is processed. A claim older than
+// this was left by a crashed request and can be taken over.
+const WebhookClaimTimeout = 10 * time.Minute
+
+// ProcessedEntity is the record stored in firestore for every entity processed from a webhook. It is
+// used to skip duplicate and out of order notifications.
+//
+// A notification is claimed before it is processed so the same notification delivered twice at the same
+// time is only processed once. The claim is cleared when the notification is processed or fails.
+type ProcessedEntity struct {
+	RealmID     string    `json:"realmId" firestore:"realmId"`
+	Name        string    `json:"name" firestore:"name"`
+	ID          string    `json:"id" firestore:"id"`
+	Operation   string    `json:"operation" firestore:"operation"`
+	LastUpdated time.Time `json:"lastUpdated" firestore:"lastUpdated"`
+	ProcessedAt time.Time `json:"processedAt" firestore:"processedAt"`
+
+	ClaimedOperation   string    `json:"claimedOperation" firestore:"claimedOperation"`
+	ClaimedLastUpdated time.Time `json:"claimedLastUpdated" firestore:"claimedLastUpdated"`
+	ClaimedAt          time.Time `json:"claimedAt" firestore:"claimedAt"` // Zero when no notification is being processed
+}
+
+func NewProcessedEntity(e *Entity, lastUpdated time.Time) *ProcessedEntity {
+	return &ProcessedEntity{
+		RealmID:     e.RealmID,
+		Name:        e.Name,
+		ID:          e.ID,
+		Operation:   e.Operation,
+		LastUpdated: lastUpdated,
+		ProcessedAt: time.Now().UTC(),
+	}
+}
+
+func (p *ProcessedEntity) ToMap() map[string]any {
+	return map[string]any{
+		"realmId":     p.RealmID,
+		"name":        p.Name,
+		"id":          p.ID,
+		"operation":   p.Operation,
+		"lastUpdated": p.LastUpdated,
+		"processedAt": p.ProcessedAt,
+
+		"claimedOperation":   p.ClaimedOperation,
+		"claimedLastUpdated": p.ClaimedLastUpdated,
+		"claimedAt":          p.ClaimedAt,
+	}
+}
+
+// IsClaimed returns true if a notification of the entity is being processed.
+func (p *ProcessedEntity) IsClaimed(now time.Time) bool {
+	return !p.ClaimedAt.IsZero() && now.Before(p.ClaimedAt.Add(WebhookClaimTimeout))
+}
+
+// IsClaimedFor returns true if the claim is for the same notification of the entity.
+func (p *ProcessedEntity) IsClaimedFor(e *Entity, lastUpdated time.Time) bool {
+	return p.ClaimedOperation == e.Operation && p.ClaimedLastUpdated.Equal(lastUpdated)
+}
+
+// WithClaim returns a copy of the record claimed for the notification of the entity. The record is nil for
+// an entity that was never processed.
+func (p *ProcessedEntity) WithClaim(e *Entity, lastUpdated, now time.Time) *ProcessedEntity {
+	claimed := &ProcessedEntity{RealmID: e.RealmID, Name: e.Name, ID: e.ID}
+	if p != nil {
+		*claimed = *p
+	}
+	claimed.ClaimedOperation, claimed.ClaimedLastUpdated, claimed.ClaimedAt = e.Operation, lastUpdated, now
+	return claimed
+}
+
+// WithoutClaim returns a copy of the record with the claim cleared.
+func (p *ProcessedEntity) WithoutClaim() *ProcessedEntity {
+	released := *p
+	released.ClaimedOperation, released.ClaimedLastUpdated, released.ClaimedAt = "", time.Time{}, time.Time{}
+	return &released
+}
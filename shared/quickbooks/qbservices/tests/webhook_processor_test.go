@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/quickbooks/qbmodels"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/quickbooks/qbservices"
+)
+
+// memoryStore is an in memory WebhookEntityStore used to test the processor without firestore.
+type memoryStore struct {
+	processed map[string]*qbmodels.ProcessedEntity
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{processed: make(map[string]*qbmodels.ProcessedEntity)}
+}
+
+func (m *memoryStore) UpdateProcessed(ctx context.Context, key string, update func(processed *qbmodels.ProcessedEntity) (*qbmodels.ProcessedEntity, error)) error {
+	updated, err := update(m.processed[key])
+	if err != nil || updated == nil {
+		return err
+	}
+	m.processed[key] = updated
+	return nil
+}
+
+func newTestProcessor(calls *[]string) *qbservices.WebhookProcessor {
+	record := func(ctx context.Context, e *qbmodels.Entity) error {
+		*calls = append(*calls, e.Operation+":"+e.ID+":"+e.LastUpdatedUTC)
+		return nil
+	}
+	return &qbservices.WebhookProcessor{
+		Store: newMemoryStore(),
+		Handlers: map[string]*qbservices.EntityHandlers{
+			qbmodels.EntityCustomer: {Create: record, Update: record, Delete: record, Merge: record},
+		},
+	}
+}
+
+func TestWebhookProcessorSkipsDuplicatesAndStale(t *testing.T) {
+	var calls []string
+	processor := newTestProcessor(&calls)
+	ctx := context.Background()
+
+	newer := qbmodels.Entity{RealmID: "1", Name: "Customer", ID: "5", Operation: "Update", LastUpdatedUTC: "2025-01-02T10:00:00.000Z"}
+	older := qbmodels.Entity{RealmID: "1", Name: "Customer", ID: "5", Operation: "Update", LastUpdatedUTC: "2025-01-01T10:00:00.000Z"}
+
+	if result, err := processor.ProcessEntity(ctx, &newer); err != nil || result != qbservices.EntityProcessed {
+		t.Fatalf("expected processed, got %s, %v", result, err)
+	}
+	if result, _ := processor.ProcessEntity(ctx, &newer); result != qbservices.EntityDuplicate {
+		t.Errorf("expected duplicate, got %s", result)
+	}
+	if result, _ := processor.ProcessEntity(ctx, &older); result != qbservices.EntityStale {
+		t.Errorf("expected stale, got %s", result)
+	}
+	if len(calls) != 1 {
+		t.Errorf("expected the handler to be called once, got %v", calls)
+	}
+}
+
+func TestWebhookProcessorOrdersBatchAndSkipsUnsupported(t *testing.T) {
+	var calls []string
+	processor := newTestProcessor(&calls)
+
+	entities := []qbmodels.Entity{
+		{RealmID: "1", Name: "Customer", ID: "5", Operation: "Update", LastUpdatedUTC: "2025-01-02T10:00:00Z"},
+		{RealmID: "1", Name: "Customer", ID: "5", Operation: "Create", LastUpdatedUTC: "2025-01-01T10:00:00Z"},
+		{RealmID: "1", Name: "Invoice", ID: "9", Operation: "Create", LastUpdatedUTC: "2025-01-01T10:00:00Z"},
+	}
+	results, err := processor.ProcessEntities(context.Background(), entities)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 2 || calls[0] != "Create:5:2025-01-01T10:00:00Z" || calls[1] != "Update:5:2025-01-02T10:00:00Z" {
+		t.Errorf("expected create then update, got %v", calls)
+	}
+	if results["1|Invoice|9"] != qbservices.EntityUnsupported {
+		t.Errorf("expected invoice to be unsupported, got %s", results["1|Invoice|9"])
+	}
+}
+
+func TestWebhookProcessorRetriesFailedEntity(t *testing.T) {
+	fail := true
+	processor := &qbservices.WebhookProcessor{
+		Store: newMemoryStore(),
+		Handlers: map[string]*qbservices.EntityHandlers{
+			qbmodels.EntityItem: {Update: func(ctx context.Context, e *qbmodels.Entity) error {
+				if fail {
+					return errors.New("quickbooks unavailable")
+				}
+				return nil
+			}},
+		},
+	}
+	entity := qbmodels.Entity{RealmID: "1", Name: "Item", ID: "68", Operation: "Update", LastUpdatedUTC: "2025-01-01T10:00:00Z"}
+
+	if _, err := processor.ProcessEntity(context.Background(), &entity); err == nil {
+		t.Fatal("expected an error from the failing handler")
+	}
+	fail = false
+	if result, err := processor.ProcessEntity(context.Background(), &entity); err != nil || result != qbservices.EntityProcessed {
+		t.Errorf("expected the retry to be processed, got %s, %v", result, err)
+	}
+}
+
+func TestParseWebhookPayload(t *testing.T) {
+	legacy := `{"eventNotifications":[{"realmId":"123","dataChangeEvent":{"entities":[
+		{"name":"Customer","id":"1","operation":"Merge","lastUpdated":"2025-01-01T10:00:00.000Z","deletedId":"2"}]}}]}`
+	entities, err := qbmodels.ParseWebhookPayload([]byte(legacy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entities) != 1 || entities[0].RealmID != "123" || entities[0].DeletedID != "2" || entities[0].Operation != qbmodels.OperationMerge {
+		t.Errorf("unexpected legacy entities: %+v", entities)
+	}
+
+	cloudEvents := `[{"specversion":"1.0","id":"a","source":"intuit","type":"qbo.item.updated.v1",
+		"time":"2025-01-01T10:00:00Z","intuitentityid":"68","intuitaccountid":"123"}]`
+	entities, err = qbmodels.ParseWebhookPayload([]byte(cloudEvents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entities) != 1 || entities[0].Name != qbmodels.EntityItem || entities[0].Operation != qbmodels.OperationUpdate || entities[0].ID != "68" {
+		t.Errorf("unexpected cloud event entities: %+v", entities)
+	}
+}
+
+func TestWebhookProcessorClaimsEntity(t *testing.T) {
+	store := newMemoryStore()
+	entity := qbmodels.Entity{RealmID: "1", Name: "Item", ID: "68", Operation: "Update", LastUpdatedUTC: "2025-01-01T10:00:00Z"}
+	var claimed *qbmodels.ProcessedEntity
+	processor := &qbservices.WebhookProcessor{
+		Store: store,
+		Handlers: map[string]*qbservices.EntityHandlers{
+			qbmodels.EntityItem: {Update: func(ctx context.Context, e *qbmodels.Entity) error {
+				claimed = store.processed[e.GetKey()]
+				return nil
+			}},
+		},
+	}
+	lastUpdated, _ := entity.GetLastUpdatedTime()
+
+	//The same notification claimed by another request is skipped
+	store.processed[entity.GetKey()] = (*qbmodels.ProcessedEntity)(nil).WithClaim(&entity, lastUpdated, time.Now().UTC())
+	if result, err := processor.ProcessEntity(context.Background(), &entity); err != nil || result != qbservices.EntityInProgress {
+		t.Fatalf("expected in progress, got %s, %v", result, err)
+	}
+
+	//Another notification of the entity is retried later
+	newer := entity
+	newer.LastUpdatedUTC = "2025-01-02T10:00:00Z"
+	if _, err := processor.ProcessEntity(context.Background(), &newer); err == nil {
+		t.Error("expected an error while another notification is being processed")
+	}
+
+	//An expired claim is taken over and cleared once the notification is processed
+	store.processed[entity.GetKey()].ClaimedAt = time.Now().Add(-qbmodels.WebhookClaimTimeout)
+	if result, err := processor.ProcessEntity(context.Background(), &newer); err != nil || result != qbservices.EntityProcessed {
+		t.Fatalf("expected processed, got %s, %v", result, err)
+	}
+	if claimed == nil || !claimed.IsClaimedFor(&newer, lastUpdated.AddDate(0, 0, 1)) {
+		t.Errorf("expected the notification to be claimed while handled, got %+v", claimed)
+	}
+	if processed := store.processed[entity.GetKey()]; !processed.ClaimedAt.IsZero() || processed.Operation != "Update" {
+		t.Errorf("expected the claim to be cleared, got %+v", processed)
+	}
+}
+
+func TestParseWebhookPayloadKeepsOtherEvents(t *testing.T) {
+	cloudEvents := `[{"id":"a","type":"qbo.customer.reactivated.v1","time":"2025-01-01T10:00:00Z","intuitentityid":"5","intuitaccountid":"123"},
+		{"id":"b","type":"invalid","time":"2025-01-01T10:00:00Z","intuitentityid":"6","intuitaccountid":"123"},
+		{"id":"c","type":"qbo.customer.updated.v1","time":"2025-01-01T10:00:00Z","intuitentityid":"7","intuitaccountid":"123"}]`
+	entities, err := qbmodels.ParseWebhookPayload([]byte(cloudEvents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entities) != 2 || entities[0].ID != "5" || entities[1].Operation != qbmodels.OperationUpdate {
+		t.Fatalf("expected the unknown operation and the update, got %+v", entities)
+	}
+
+	var calls []string
+	results, err := newTestProcessor(&calls).ProcessEntities(context.Background(), entities)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results["123|Customer|5"] != qbservices.EntityUnsupported || results["123|Customer|7"] != qbservices.EntityProcessed {
+		t.Errorf("expected the unknown operation to be unsupported and the update processed, got %v", results)
+	}
+}
@@ -0,0 +1,344 @@
+package qbservices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/quickbooks/qbmodels"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/repositories"
+)
+
+// Result of processing a single webhook entity.
+const (
+	EntityProcessed   = "PROCESSED"
+	EntityDuplicate   = "DUPLICATE"   // Same entity, operation and lastUpdated was already processed
+	EntityInProgress  = "IN_PROGRESS" // Same notification is being processed by another request
+	EntityStale       = "STALE"       // A newer notification for the entity was already processed
+	EntityUnsupported = "UNSUPPORTED" // No handler registered for the entity name or operation
+)
+
+// WebhookEntityStore keeps track of the processed webhook entities. The default store is backed by
+// firestore, tests can pass an in memory store.
+type WebhookEntityStore interface {
+	// UpdateProcessed atomically replaces the record of the key with the record returned by update, a nil
+	// record leaves it unchanged. The record passed to update is nil if the entity was never processed.
+	UpdateProcessed(ctx context.Context, key string, update func(processed *qbmodels.ProcessedEntity) (*qbmodels.ProcessedEntity, error)) error
+}
+
+type firestoreWebhookEntityStore struct{}
+
+func (firestoreWebhookEntityStore) UpdateProcessed(ctx context.Context, key string, update func(processed *qbmodels.ProcessedEntity) (*qbmodels.ProcessedEntity, error)) error {
+	return repositories.UpdateProcessedWebhookEntity(ctx, key, update)
+}
+
+// EntityHandlerFunc handles a single webhook entity operation.
+type EntityHandlerFunc func(ctx context.Context, entity *qbmodels.Entity) error
+
+// EntityHandlers contains the handler of each operation for one entity type. A nil handler means
+// the operation is not supported for the entity and the notification is skipped.
+type EntityHandlers struct {
+	Create EntityHandlerFunc
+	Update EntityHandlerFunc
+	Delete EntityHandlerFunc
+	Merge  EntityHandlerFunc
+}
+
+func (h *EntityHandlers) get(operation string) EntityHandlerFunc {
+	switch operation {
+	case qbmodels.OperationCreate:
+		return h.Create
+	case qbmodels.OperationUpdate:
+		return h.Update
+	case qbmodels.OperationDelete:
+		return h.Delete
+	case qbmodels.OperationMerge:
+		return h.Merge
+	default:
+		return nil
+	}
+}
+
+// WebhookProcessor dispatches the webhook entities to the handlers of their entity type while skipping
+// duplicate and out of order notifications. QuickBooks often re-sends the same notification and does not
+// guarantee the order of delivery.
+type WebhookProcessor struct {
+	Store    WebhookEntityStore
+	Handlers map[string]*EntityHandlers // Keyed by the entity name, e.g. "Customer"
+}
+
+// NewWebhookProcessor creates a processor backed by firestore with the default handlers for
+// customers and items. Deletes and merges deactivate the firestore document instead of deleting it
+// since existing orders still reference it.
+func NewWebhookProcessor() *WebhookProcessor {
+	return &WebhookProcessor{
+		Store: firestoreWebhookEntityStore{},
+		Handlers: map[string]*EntityHandlers{
+			qbmodels.EntityCustomer: {
+				Create: handleCustomerUpsert,
+				Update: handleCustomerUpsert,
+				Delete: handleCustomerDelete,
+				Merge:  handleCustomerMerge,
+			},
+			qbmodels.EntityItem: {
+				Create: handleItemUpsert,
+				Update: handleItemUpsert,
+				Delete: handleItemDelete,
+				Merge:  handleItemMerge,
+			},
+		},
+	}
+}
+
+// ProcessEntity processes a single webhook entity.
+//
+// The entity is skipped when the stored record for the same (realm, entity, id) key has a newer lastUpdated
+// time (stale) or the same time and operation (duplicate). Otherwise the notification is claimed in the
+// record before the handler runs, so the same notification delivered twice at once is only processed by one
+// request. The record is saved once the handler succeeds and the claim is released if it fails, so a failed
+// notification is processed again when quickbooks retries it.
+//
+// Returns:
+//   - string: one of EntityProcessed, EntityDuplicate, EntityInProgress, EntityStale or EntityUnsupported
+//   - error: error if the entity is invalid, another notification of the entity is being processed or the
+//     handler failed
+func (p *WebhookProcessor) ProcessEntity(ctx context.Context, entity *qbmodels.Entity) (string, error) {
+	entityHandlers, ok := p.Handlers[entity.Name]
+	if !ok {
+		return EntityUnsupported, nil
+	}
+	handler := entityHandlers.get(entity.Operation)
+	if handler == nil {
+		return EntityUnsupported, nil
+	}
+
+	lastUpdated, err := entity.GetLastUpdatedTime()
+	if err != nil {
+		return "", err
+	}
+
+	result, err := p.claim(ctx, entity, lastUpdated)
+	if err != nil || result != "" {
+		return result, err
+	}
+
+	if err := handler(ctx, entity); err != nil {
+		err = fmt.Errorf("failed to process %s %s of %s %s: %w", entity.Operation, entity.Name, entity.RealmID, entity.ID, err)
+		return "", errors.Join(err, p.release(ctx, entity, lastUpdated))
+	}
+
+	err = p.Store.UpdateProcessed(ctx, entity.GetKey(), func(processed *qbmodels.ProcessedEntity) (*qbmodels.ProcessedEntity, error) {
+		//Never move the lastUpdated time backwards, a newer notification may have been processed after the
+		//claim of this one expired
+		if processed != nil && processed.LastUpdated.After(lastUpdated) {
+			return getReleased(processed, entity, lastUpdated), nil
+		}
+		return qbmodels.NewProcessedEntity(entity, lastUpdated), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return EntityProcessed, nil
+}
+
+// claim claims the notification of the entity in its record.
+//
+// Returns:
+//   - string: the reason the entity is skipped, empty if it was claimed
+//   - error: error if another notification of the entity is being processed or the update failed
+func (p *WebhookProcessor) claim(ctx context.Context, entity *qbmodels.Entity, lastUpdated time.Time) (string, error) {
+	var result string
+	err := p.Store.UpdateProcessed(ctx, entity.GetKey(), func(processed *qbmodels.ProcessedEntity) (*qbmodels.ProcessedEntity, error) {
+		now := time.Now().UTC()
+		result = ""
+		if processed != nil {
+			if processed.LastUpdated.After(lastUpdated) {
+				result = EntityStale
+				return nil, nil
+			}
+			if processed.LastUpdated.Equal(lastUpdated) && processed.Operation == entity.Operation {
+				result = EntityDuplicate
+				return nil, nil
+			}
+			if processed.IsClaimed(now) {
+				if processed.IsClaimedFor(entity, lastUpdated) {
+					result = EntityInProgress
+					return nil, nil
+				}
+				//Fail so quickbooks retries the notification once the other one is processed
+				return nil, fmt.Errorf("%s %s of %s is being processed, %s will be retried", entity.Name, entity.ID, entity.RealmID, entity.Operation)
+			}
+		}
+		return processed.WithClaim(entity, lastUpdated, now), nil
+	})
+	return result, err
+}
+
+// release clears the claim of a notification that failed.
+func (p *WebhookProcessor) release(ctx context.Context, entity *qbmodels.Entity, lastUpdated time.Time) error {
+	return p.Store.UpdateProcessed(ctx, entity.GetKey(), func(processed *qbmodels.ProcessedEntity) (*qbmodels.ProcessedEntity, error) {
+		if processed == nil {
+			return nil, nil
+		}
+		return getReleased(processed, entity, lastUpdated), nil
+	})
+}
+
+// getReleased returns the record without the claim of the notification, nil if the notification no longer
+// holds the claim.
+func getReleased(processed *qbmodels.ProcessedEntity, entity *qbmodels.Entity, lastUpdated time.Time) *qbmodels.ProcessedEntity {
+	if !processed.IsClaimedFor(entity, lastUpdated) {
+		return nil
+	}
+	return processed.WithoutClaim()
+}
+
+// ProcessEntities processes all the entities of a webhook in the order they were last updated in
+// quickbooks. Every entity is processed even if one of them fails; the errors are joined.
+//
+// Returns:
+//   - map[string]string: the result of each entity keyed by the entity key
+//   - error: joined errors of all the failed entities
+func (p *WebhookProcessor) ProcessEntities(ctx context.Context, entities []qbmodels.Entity) (map[string]string, error) {
+	sorted := make([]qbmodels.Entity, len(entities))
+	copy(sorted, entities)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		// Entities with an invalid time sort first, ProcessEntity reports the error for them
+		a, _ := sorted[i].GetLastUpdatedTime()
+		b, _ := sorted[j].GetLastUpdatedTime()
+		return a.Before(b)
+	})
+
+	results := make(map[string]string)
+	var errs []error
+	for i := range sorted {
+		result, err := p.ProcessEntity(ctx, &sorted[i])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results[sorted[i].GetKey()] = result
+	}
+	return results, errors.Join(errs...)
+}
+
+// ProcessWebhookPayload parses a webhook request body in either the legacy or the CloudEvents format and
+// processes all of its entities.
+func (p *WebhookProcessor) ProcessWebhookPayload(ctx context.Context, body []byte) (map[string]string, error) {
+	entities, err := qbmodels.ParseWebhookPayload(body)
+	if err != nil {
+		return nil, err
+	}
+	return p.ProcessEntities(ctx, entities)
+}
+
+// handleCustomerUpsert fetches the latest customer from quickbooks, syncs it to firestore and recomputes
+// the per customer prices of the customer.
+func handleCustomerUpsert(ctx context.Context, entity *qbmodels.Entity) error {
+	customer, err := GetQBCustomerFromEntityID(entity.ID)
+	if err != nil {
+		return err
+	}
+	var resp qbmodels.QBCustomersResponse
+	resp.QueryResponse.Customer = []qbmodels.QBCustomer{*customer}
+	if err := repositories.SyncQuickbookCustomerRespToFirestore(&resp, ctx); err != nil {
+		return err
+	}
+	_, err = repositories.RecomputeProductsPricesPerCustomer(ctx, &models.PriceRecomputeOptions{
+		CustomerIDs: []string{entity.ID},
+		Source:      constants.PriceChangeSourceQuickBooks,
+	})
+	return err
+}
+
+// handleItemUpsert fetches the latest item from quickbooks, syncs it to firestore and recomputes the per
+// customer prices of the item.
+func handleItemUpsert(ctx context.Context, entity *qbmodels.Entity) error {
+	item, err := GetQBProductFromEntityID(entity.ID)
+	if err != nil {
+		return err
+	}
+	var resp qbmodels.QBItemsResponse
+	resp.QueryResponse.Item = []qbmodels.QBItem{*item}
+	if _, err := repositories.SyncQuickbookProductRespToFirestore(&resp, ctx, false); err != nil {
+		return err
+	}
+	_, err = repositories.RecomputeProductsPricesPerCustomer(ctx, &models.PriceRecomputeOptions{
+		ProductIDs: []string{entity.ID},
+		Source:     constants.PriceChangeSourceQuickBooks,
+	})
+	return err
+}
+
+// handleCustomerMerge syncs the surviving customer and moves the orders, users and prices of the merged
+// customer (entity.DeletedID) to it.
+func handleCustomerMerge(ctx context.Context, entity *qbmodels.Entity) error {
+	if entity.DeletedID == "" {
+		return errors.New("merge notification is missing the deleted id")
+	}
+	if err := handleCustomerUpsert(ctx, entity); err != nil {
+		return err
+	}
+	updated, err := repositories.MergeCustomerInFirestore(ctx, entity.DeletedID, entity.ID)
+	if err != nil {
+		return err
+	}
+
+	auditEntry := models.NewAuditEntry(constants.AuditActionMerge, constants.CustomersCollection, entity.DeletedID, constants.AuditSourceQuickBooks)
+	auditEntry.SetTargetID(entity.ID)
+	for collection, count := range updated {
+		auditEntry.AddDetail(collection, count)
+	}
+	return repositories.CreateAuditEntryInFirestore(ctx, auditEntry)
+}
+
+// handleCustomerDelete deactivates the customer and removes its per customer prices.
+func handleCustomerDelete(ctx context.Context, entity *qbmodels.Entity) error {
+	if err := repositories.DeactivateCustomerInFirestore(ctx, entity.ID); err != nil {
+		return err
+	}
+	deleted, err := repositories.DeleteProductPricesInFirestore(ctx, "customerId", entity.ID)
+	if err != nil {
+		return err
+	}
+
+	auditEntry := models.NewAuditEntry(constants.AuditActionDeactivate, constants.CustomersCollection, entity.ID, constants.AuditSourceQuickBooks)
+	auditEntry.AddDetail(constants.ProductsPricesPerCustCollection, deleted)
+	return repositories.CreateAuditEntryInFirestore(ctx, auditEntry)
+}
+
+// handleItemMerge syncs the surviving item and deactivates the merged item (entity.DeletedID). Prices of
+// the merged item are removed since they were set for a different product.
+func handleItemMerge(ctx context.Context, entity *qbmodels.Entity) error {
+	if entity.DeletedID == "" {
+		return errors.New("merge notification is missing the deleted id")
+	}
+	if err := handleItemUpsert(ctx, entity); err != nil {
+		return err
+	}
+	return deactivateItem(ctx, entity.DeletedID, entity.ID, constants.AuditActionMerge)
+}
+
+// handleItemDelete deactivates the item and removes its per customer prices.
+func handleItemDelete(ctx context.Context, entity *qbmodels.Entity) error {
+	return deactivateItem(ctx, entity.ID, "", constants.AuditActionDeactivate)
+}
+
+func deactivateItem(ctx context.Context, productID, targetID, action string) error {
+	if err := repositories.DeactivateProductInFirestore(ctx, productID); err != nil {
+		return err
+	}
+	deleted, err := repositories.DeleteProductPricesInFirestore(ctx, "productId", productID)
+	if err != nil {
+		return err
+	}
+
+	auditEntry := models.NewAuditEntry(action, constants.ProductsCollection, productID, constants.AuditSourceQuickBooks)
+	auditEntry.SetTargetID(targetID)
+	auditEntry.AddDetail(constants.ProductsPricesPerCustCollection, deleted)
+	return repositories.CreateAuditEntryInFirestore(ctx, auditEntry)
+}
@@ -0,0 +1,31 @@
+package reporting
+
+import "time"
+
+// Period is a reporting period. From is inclusive and To is exclusive.
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// NewMonthPeriod returns the period of a calendar month in the given location.
+func NewMonthPeriod(year int, month time.Month, location *time.Location) Period {
+	from := time.Date(year, month, 1, 0, 0, 0, 0, location)
+	return Period{From: from, To: from.AddDate(0, 1, 0)}
+}
+
+// GetPreviousMonth returns the calendar month before the month the period starts in.
+func (p Period) GetPreviousMonth() Period {
+	from := time.Date(p.From.Year(), p.From.Month(), 1, 0, 0, 0, 0, p.From.Location()).AddDate(0, -1, 0)
+	return Period{From: from, To: from.AddDate(0, 1, 0)}
+}
+
+// Contains returns true if the time is within the period.
+func (p Period) Contains(t time.Time) bool {
+	return !t.Before(p.From) && t.Before(p.To)
+}
+
+// Format returns the period as "January 1, 2025 - January 31, 2025"
+func (p Period) Format() string {
+	return p.From.Format("January 2, 2006") + " - " + p.To.AddDate(0, 0, -1).Format("January 2, 2006")
+}
@@ -0,0 +1,235 @@
+package reporting
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+// Dimension is what the orders of a sales report are grouped by.
+type Dimension string
+
+const (
+	DimensionCustomer Dimension = "CUSTOMER"
+	DimensionBrand    Dimension = "BRAND"
+	DimensionCategory Dimension = "CATEGORY"
+	DimensionProduct  Dimension = "PRODUCT"
+)
+
+// SalesMetrics are the totals of a group of order items. Units is the ordered quantity, the quantity the
+// prices are per. Sales are before tax and COGS uses the purchase price of the products.
+type SalesMetrics struct {
+	Units int     `json:"units"`
+	Sales float64 `json:"sales"`
+	COGS  float64 `json:"cogs"`
+}
+
+func (m *SalesMetrics) add(item *models.Product) {
+	m.Units += item.Quantity
+	m.Sales = roundToCents(m.Sales + item.GetTotalPrice())
+	m.COGS = roundToCents(m.COGS + item.GetTotalPurchasePrice())
+}
+
+func (m *SalesMetrics) merge(other SalesMetrics) {
+	m.Units += other.Units
+	m.Sales = roundToCents(m.Sales + other.Sales)
+	m.COGS = roundToCents(m.COGS + other.COGS)
+}
+
+func (m SalesMetrics) GetGrossMargin() float64 {
+	return roundToCents(m.Sales - m.COGS)
+}
+
+// GetMarginPercent returns the gross margin as a percent of the sales, 0 when there are no sales.
+func (m SalesMetrics) GetMarginPercent() float64 {
+	if m.Sales == 0 {
+		return 0
+	}
+	return roundToCents(m.GetGrossMargin() / m.Sales * 100)
+}
+
+// SalesRow is a single group of a sales report, e.g. a customer or a brand. Previous is set by
+// SalesReport.CompareWith.
+type SalesRow struct {
+	Key      string        `json:"key"`
+	Label    string        `json:"label"`
+	Current  SalesMetrics  `json:"current"`
+	Previous *SalesMetrics `json:"previous,omitempty"`
+}
+
+// GetSalesChangePercent returns the change of the sales from the previous period in percent. Returns false
+// when there is nothing to compare with or the previous period has no sales.
+func (r *SalesRow) GetSalesChangePercent() (float64, bool) {
+	if r.Previous == nil || r.Previous.Sales == 0 {
+		return 0, false
+	}
+	return roundToCents((r.Current.Sales - r.Previous.Sales) / r.Previous.Sales * 100), true
+}
+
+// SalesReport aggregates the delivered orders of a period by a dimension.
+type SalesReport struct {
+	Dimension      Dimension     `json:"dimension"`
+	Period         Period        `json:"period"`
+	Rows           []*SalesRow   `json:"rows"`
+	Totals         SalesMetrics  `json:"totals"`
+	PreviousPeriod *Period       `json:"previousPeriod,omitempty"`
+	PreviousTotals *SalesMetrics `json:"previousTotals,omitempty"`
+}
+
+// NewSalesReport aggregates the orders by the dimension. Only delivered orders last updated (delivered) within
+// the period are counted, the items of the orders must be complete (see Order.ToCompleteOrderItemsFromMinimal).
+//
+// The rows are sorted by sales, highest first.
+func NewSalesReport(orders []*models.Order, dimension Dimension, period Period) (*SalesReport, error) {
+	report := &SalesReport{Dimension: dimension, Period: period, Rows: make([]*SalesRow, 0)}
+	rowMap := make(map[string]*SalesRow)
+	for _, order := range orders {
+		if order.Status != constants.OrderStatusDelivered || !period.Contains(order.UpdatedAt) {
+			continue
+		}
+		for _, item := range order.Items {
+			key, label, err := getDimensionKey(dimension, order, item)
+			if err != nil {
+				return nil, err
+			}
+			row, ok := rowMap[key]
+			if !ok {
+				row = &SalesRow{Key: key, Label: label}
+				rowMap[key] = row
+				report.Rows = append(report.Rows, row)
+			}
+			row.Current.add(item)
+			report.Totals.add(item)
+		}
+	}
+	report.sortRows()
+	return report, nil
+}
+
+// getDimensionKey returns the key the item is grouped by and the label shown in the report.
+func getDimensionKey(dimension Dimension, order *models.Order, item *models.Product) (string, string, error) {
+	switch dimension {
+	case DimensionCustomer:
+		if order.Customer == nil {
+			return "", "", errors.New("Order " + order.ID + " has no customer")
+		}
+		return order.Customer.ID, order.Customer.Name, nil
+	case DimensionBrand:
+		return item.Brand, getLabelOrDefault(item.Brand, "No Brand"), nil
+	case DimensionCategory:
+		return item.Category, getLabelOrDefault(item.Category, "Uncategorized"), nil
+	case DimensionProduct:
+		return item.ID, item.GetShortDescription(), nil
+	default:
+		return "", "", errors.New("Invalid report dimension " + string(dimension))
+	}
+}
+
+func getLabelOrDefault(label, defaultLabel string) string {
+	if label == "" {
+		return defaultLabel
+	}
+	return label
+}
+
+// CompareWith sets the metrics of the previous report (e.g. the previous month) on the matching rows. Groups
+// that only sold in the previous period are added with empty current metrics.
+func (r *SalesReport) CompareWith(previous *SalesReport) {
+	rowMap := make(map[string]*SalesRow, len(r.Rows))
+	for _, row := range r.Rows {
+		row.Previous = &SalesMetrics{}
+		rowMap[row.Key] = row
+	}
+	for _, previousRow := range previous.Rows {
+		row, ok := rowMap[previousRow.Key]
+		if !ok {
+			row = &SalesRow{Key: previousRow.Key, Label: previousRow.Label, Previous: &SalesMetrics{}}
+			r.Rows = append(r.Rows, row)
+		}
+		row.Previous.merge(previousRow.Current)
+	}
+	previousPeriod := previous.Period
+	previousTotals := previous.Totals
+	r.PreviousPeriod = &previousPeriod
+	r.PreviousTotals = &previousTotals
+	r.sortRows()
+}
+
+func (r *SalesReport) sortRows() {
+	sort.SliceStable(r.Rows, func(i, j int) bool {
+		if r.Rows[i].Current.Sales != r.Rows[j].Current.Sales {
+			return r.Rows[i].Current.Sales > r.Rows[j].Current.Sales
+		}
+		return r.Rows[i].Label < r.Rows[j].Label
+	})
+}
+
+// ToTable converts the report to a table that can be exported to csv, xlsx or pdf. The previous sales and
+// the sales change columns are only added after CompareWith.
+func (r *SalesReport) ToTable() *Table {
+	nameWidth := 66.0
+	if r.PreviousTotals != nil {
+		nameWidth = 32
+	}
+	table := &Table{
+		Title:    "SALES REPORT BY " + string(r.Dimension),
+		Subtitle: r.Period.Format(),
+		Columns: []Column{
+			{Header: string(r.Dimension), Width: nameWidth, Format: ColumnFormatText},
+			{Header: "UNITS", Width: 16, Format: ColumnFormatInteger},
+			{Header: "SALES", Width: 26, Format: ColumnFormatCurrency},
+			{Header: "COGS", Width: 26, Format: ColumnFormatCurrency},
+			{Header: "GROSS MARGIN", Width: 26, Format: ColumnFormatCurrency},
+			{Header: "MARGIN %", Width: 18, Format: ColumnFormatPercent},
+		},
+		Rows: make([][]any, 0, len(r.Rows)),
+	}
+	if r.PreviousTotals != nil {
+		table.Columns = append(table.Columns,
+			Column{Header: "PREVIOUS SALES", Width: 18, Format: ColumnFormatCurrency},
+			Column{Header: "SALES CHANGE %", Width: 16, Format: ColumnFormatPercent},
+		)
+	}
+
+	for _, row := range r.Rows {
+		table.Rows = append(table.Rows, r.getTableRow(row))
+	}
+
+	table.Summary = []SummaryLine{
+		{Label: "UNITS", Value: r.Totals.Units, Format: ColumnFormatInteger},
+		{Label: "SALES", Value: r.Totals.Sales, Format: ColumnFormatCurrency},
+		{Label: "COGS", Value: r.Totals.COGS, Format: ColumnFormatCurrency},
+		{Label: "GROSS MARGIN", Value: r.Totals.GetGrossMargin(), Format: ColumnFormatCurrency},
+		{Label: "MARGIN %", Value: r.Totals.GetMarginPercent(), Format: ColumnFormatPercent},
+	}
+	if r.PreviousTotals != nil {
+		totalsRow := &SalesRow{Current: r.Totals, Previous: r.PreviousTotals}
+		table.Summary = append(table.Summary, SummaryLine{Label: "PREVIOUS SALES", Value: r.PreviousTotals.Sales, Format: ColumnFormatCurrency})
+		if change, ok := totalsRow.GetSalesChangePercent(); ok {
+			table.Summary = append(table.Summary, SummaryLine{Label: "SALES CHANGE %", Value: change, Format: ColumnFormatPercent})
+		}
+	}
+	return table
+}
+
+func (r *SalesReport) getTableRow(row *SalesRow) []any {
+	values := []any{row.Label, row.Current.Units, row.Current.Sales, row.Current.COGS, row.Current.GetGrossMargin(), row.Current.GetMarginPercent()}
+	if r.PreviousTotals == nil {
+		return values
+	}
+	var previousSales, change any
+	if row.Previous != nil {
+		previousSales = row.Previous.Sales
+	}
+	if value, ok := row.GetSalesChangePercent(); ok {
+		change = value
+	}
+	return append(values, previousSales, change)
+}
+
+func roundToCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
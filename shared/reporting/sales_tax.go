@@ -0,0 +1,192 @@
+package reporting
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+// Differences between the order tax and the quickbooks tax up to this amount are treated as rounding.
+const taxReconciliationTolerance = 0.01
+
+// Jurisdiction is where the sales tax of an order is owed, the ship to address of the customer.
+type Jurisdiction struct {
+	State string `json:"state"`
+	City  string `json:"city"`
+	Zip   string `json:"zip"`
+}
+
+func newJurisdiction(customer *models.Customer) Jurisdiction {
+	if customer == nil {
+		return Jurisdiction{}
+	}
+	return Jurisdiction{
+		State: strings.ToUpper(strings.TrimSpace(customer.State)),
+		City:  strings.ToUpper(strings.TrimSpace(customer.City)),
+		Zip:   strings.TrimSpace(customer.Zip),
+	}
+}
+
+// SalesTaxRow is the sales tax liability of a single jurisdiction. QuickBooksTax only includes the orders
+// with a quickbooks invoice, ReconciledTax is the order tax of the same orders.
+type SalesTaxRow struct {
+	Jurisdiction   Jurisdiction `json:"jurisdiction"`
+	Orders         int          `json:"orders"`
+	TaxableSales   float64      `json:"taxableSales"`
+	ExemptSales    float64      `json:"exemptSales"`
+	TaxCollected   float64      `json:"taxCollected"`
+	InvoicedOrders int          `json:"invoicedOrders"`
+	ReconciledTax  float64      `json:"reconciledTax"`
+	QuickBooksTax  float64      `json:"quickBooksTax"`
+}
+
+// GetTaxDifference returns the quickbooks tax minus the order tax of the invoiced orders.
+func (r *SalesTaxRow) GetTaxDifference() float64 {
+	return roundToCents(r.QuickBooksTax - r.ReconciledTax)
+}
+
+// TaxDiscrepancy is an invoiced order whose tax does not match the quickbooks invoice.
+type TaxDiscrepancy struct {
+	OrderID       string  `json:"orderId"`
+	QBInvoiceID   string  `json:"qbInvoiceId"`
+	OrderTax      float64 `json:"orderTax"`
+	QuickBooksTax float64 `json:"quickBooksTax"`
+}
+
+// SalesTaxReport is the sales tax liability of a filing period grouped by jurisdiction.
+type SalesTaxReport struct {
+	Period           Period            `json:"period"`
+	Rows             []*SalesTaxRow    `json:"rows"`
+	Totals           SalesTaxRow       `json:"totals"`
+	UninvoicedOrders []string          `json:"uninvoicedOrders"` // Delivered orders without a quickbooks invoice
+	Discrepancies    []*TaxDiscrepancy `json:"discrepancies"`
+}
+
+// NewSalesTaxReport groups the delivered and invoiced orders last updated within the period by jurisdiction.
+// An order without a tax rate is an exempt sale, otherwise its subtotal is a taxable sale.
+//
+// Params:
+//   - orders: orders with their customers
+//   - quickBooksTaxes: the tax of the quickbooks invoices keyed by invoice id (see qbmodels.Invoice.GetTotalTax).
+//     Orders whose invoice is missing from the map are not reconciled
+//   - period: the filing period
+//
+// Returns:
+//   - *SalesTaxReport: rows sorted by state, city and zip
+func NewSalesTaxReport(orders []*models.Order, quickBooksTaxes map[string]float64, period Period) *SalesTaxReport {
+	report := &SalesTaxReport{
+		Period:           period,
+		Rows:             make([]*SalesTaxRow, 0),
+		UninvoicedOrders: make([]string, 0),
+		Discrepancies:    make([]*TaxDiscrepancy, 0),
+	}
+	rowMap := make(map[Jurisdiction]*SalesTaxRow)
+	for _, order := range orders {
+		if !period.Contains(order.UpdatedAt) {
+			continue
+		}
+		if order.Status != constants.OrderStatusDelivered && order.QBInvoiceID == "" {
+			continue
+		}
+		jurisdiction := newJurisdiction(order.Customer)
+		row, ok := rowMap[jurisdiction]
+		if !ok {
+			row = &SalesTaxRow{Jurisdiction: jurisdiction}
+			rowMap[jurisdiction] = row
+			report.Rows = append(report.Rows, row)
+		}
+		addOrderToSalesTaxRow(row, order)
+		addOrderToSalesTaxRow(&report.Totals, order)
+
+		quickBooksTax, invoiced := quickBooksTaxes[order.QBInvoiceID]
+		if order.QBInvoiceID == "" || !invoiced {
+			if order.QBInvoiceID == "" {
+				report.UninvoicedOrders = append(report.UninvoicedOrders, order.ID)
+			}
+			continue
+		}
+		reconcileOrderTax(row, order, quickBooksTax)
+		reconcileOrderTax(&report.Totals, order, quickBooksTax)
+		if math.Abs(quickBooksTax-order.TaxAmount) > taxReconciliationTolerance {
+			report.Discrepancies = append(report.Discrepancies, &TaxDiscrepancy{
+				OrderID:       order.ID,
+				QBInvoiceID:   order.QBInvoiceID,
+				OrderTax:      order.TaxAmount,
+				QuickBooksTax: quickBooksTax,
+			})
+		}
+	}
+
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i].Jurisdiction, report.Rows[j].Jurisdiction
+		if a.State != b.State {
+			return a.State < b.State
+		}
+		if a.City != b.City {
+			return a.City < b.City
+		}
+		return a.Zip < b.Zip
+	})
+	return report
+}
+
+func addOrderToSalesTaxRow(row *SalesTaxRow, order *models.Order) {
+	row.Orders++
+	if order.TaxRate == 0 {
+		row.ExemptSales = roundToCents(row.ExemptSales + order.SubTotal)
+	} else {
+		row.TaxableSales = roundToCents(row.TaxableSales + order.SubTotal)
+	}
+	row.TaxCollected = roundToCents(row.TaxCollected + order.TaxAmount)
+}
+
+func reconcileOrderTax(row *SalesTaxRow, order *models.Order, quickBooksTax float64) {
+	row.InvoicedOrders++
+	row.ReconciledTax = roundToCents(row.ReconciledTax + order.TaxAmount)
+	row.QuickBooksTax = roundToCents(row.QuickBooksTax + quickBooksTax)
+}
+
+// ToTable converts the report to a table that can be exported to csv or pdf.
+func (r *SalesTaxReport) ToTable() *Table {
+	table := &Table{
+		Title:    "SALES TAX LIABILITY REPORT",
+		Subtitle: r.Period.Format(),
+		Columns: []Column{
+			{Header: "STATE", Width: 14, Format: ColumnFormatText},
+			{Header: "CITY", Width: 30, Format: ColumnFormatText},
+			{Header: "ZIP", Width: 16, Format: ColumnFormatText},
+			{Header: "ORDERS", Width: 14, Format: ColumnFormatInteger},
+			{Header: "TAXABLE SALES", Width: 24, Format: ColumnFormatCurrency},
+			{Header: "EXEMPT SALES", Width: 22, Format: ColumnFormatCurrency},
+			{Header: "TAX COLLECTED", Width: 22, Format: ColumnFormatCurrency},
+			{Header: "QUICKBOOKS TAX", Width: 20, Format: ColumnFormatCurrency},
+			{Header: "DIFFERENCE", Width: 18, Format: ColumnFormatCurrency},
+		},
+		Rows: make([][]any, 0, len(r.Rows)),
+	}
+	for _, row := range r.Rows {
+		table.Rows = append(table.Rows, []any{
+			row.Jurisdiction.State,
+			row.Jurisdiction.City,
+			row.Jurisdiction.Zip,
+			row.Orders,
+			row.TaxableSales,
+			row.ExemptSales,
+			row.TaxCollected,
+			row.QuickBooksTax,
+			row.GetTaxDifference(),
+		})
+	}
+	table.Summary = []SummaryLine{
+		{Label: "TAXABLE SALES", Value: r.Totals.TaxableSales, Format: ColumnFormatCurrency},
+		{Label: "EXEMPT SALES", Value: r.Totals.ExemptSales, Format: ColumnFormatCurrency},
+		{Label: "TAX COLLECTED", Value: r.Totals.TaxCollected, Format: ColumnFormatCurrency},
+		{Label: "QUICKBOOKS TAX", Value: r.Totals.QuickBooksTax, Format: ColumnFormatCurrency},
+		{Label: "ORDERS NOT INVOICED", Value: len(r.UninvoicedOrders), Format: ColumnFormatInteger},
+		{Label: "ORDERS WITH DIFFERENCES", Value: len(r.Discrepancies), Format: ColumnFormatInteger},
+	}
+	return table
+}
@@ -0,0 +1,151 @@
+// package reporting aggregates orders into internal reports. Every report can be converted to a Table which
+// is exported to CSV, XLSX or rendered to PDF with layout.TableReport.
+package reporting
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/pdfgen/layout"
+)
+
+// ColumnFormat decides how the values of a column are written to each output.
+type ColumnFormat int
+
+const (
+	ColumnFormatText     ColumnFormat = iota // string values
+	ColumnFormatInteger                      // int values
+	ColumnFormatCurrency                     // float64 dollar values
+	ColumnFormatPercent                      // float64 values, 12.5 is 12.5%
+)
+
+// Column of a report table. Width is the width of the column in the pdf.
+type Column struct {
+	Header string
+	Width  float64
+	Format ColumnFormat
+}
+
+// Table is the output independent form of a report. The values of each row follow the order and the
+// format of the columns, a nil value is written as an empty cell.
+type Table struct {
+	Title    string
+	Subtitle string
+	Columns  []Column
+	Rows     [][]any
+	Summary  []SummaryLine // Totals shown below the table in the pdf and as the last rows of the csv/xlsx
+}
+
+// SummaryLine is a single total of a report, e.g. "TOTAL SALES: $100.00"
+type SummaryLine struct {
+	Label  string
+	Value  any
+	Format ColumnFormat
+}
+
+// formatValue formats a value for the pdf, currencies get a dollar sign and percents a percent sign.
+func formatValue(value any, format ColumnFormat) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		switch format {
+		case ColumnFormatCurrency:
+			return models.FormatAmount(v)
+		case ColumnFormatPercent:
+			return fmt.Sprintf("%.1f%%", v)
+		default:
+			return fmt.Sprintf("%.2f", v)
+		}
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// formatRawValue formats a value for csv, numbers are written without any symbols so they can be summed in
+// a spreadsheet.
+func formatRawValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return fmt.Sprintf("%.2f", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func (t *Table) getHeaders() []string {
+	headers := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		headers[i] = column.Header
+	}
+	return headers
+}
+
+// getSummaryRows returns the summary as rows with the label in the first column and the value in the
+// second, used by the csv and xlsx outputs.
+func (t *Table) getSummaryRows() [][]any {
+	rows := make([][]any, 0, len(t.Summary))
+	for _, line := range t.Summary {
+		row := make([]any, len(t.Columns))
+		row[0] = line.Label
+		if len(row) > 1 {
+			row[1] = line.Value
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// WriteCSV writes the headers, the rows and then the summary lines as csv.
+func (t *Table) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(t.getHeaders()); err != nil {
+		return err
+	}
+	rows := append(append([][]any{}, t.Rows...), t.getSummaryRows()...)
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = formatRawValue(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ToPDFLayout converts the table to the generic pdf report layout.
+func (t *Table) ToPDFLayout() *layout.TableReport {
+	report := &layout.TableReport{
+		Title:       t.Title,
+		Subtitle:    t.Subtitle,
+		Headers:     t.getHeaders(),
+		CellWidths:  make([]float64, len(t.Columns)),
+		TableValues: make([][]string, len(t.Rows)),
+	}
+	for i, column := range t.Columns {
+		report.CellWidths[i] = column.Width
+	}
+	for i, row := range t.Rows {
+		report.TableValues[i] = make([]string, len(row))
+		for j, value := range row {
+			report.TableValues[i][j] = formatValue(value, t.Columns[j].Format)
+		}
+	}
+	for _, line := range t.Summary {
+		report.SummaryLabels = append(report.SummaryLabels, line.Label)
+		report.SummaryValues = append(report.SummaryValues, formatValue(line.Value, line.Format))
+	}
+	return report
+}
+
+// RenderToPDF renders the table with layout.TableReport, implements pdfgen.PDFGen
+func (t *Table) RenderToPDF() ([]byte, error) {
+	return t.ToPDFLayout().RenderToPDF()
+}
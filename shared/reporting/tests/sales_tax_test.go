@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/reporting"
+)
+
+func newTaxedOrder(id, status, city, qbInvoiceID string, subTotal, taxRate float64) *models.Order {
+	return &models.Order{
+		ID:          id,
+		Customer:    &models.Customer{ID: "c" + id, State: "ca", City: " " + city + " ", Zip: "93721"},
+		Status:      status,
+		QBInvoiceID: qbInvoiceID,
+		SubTotal:    subTotal,
+		TaxRate:     taxRate,
+		TaxAmount:   subTotal * taxRate / 100,
+		UpdatedAt:   time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestNewSalesTaxReport(t *testing.T) {
+	period := reporting.NewMonthPeriod(2025, time.March, time.UTC)
+	outsidePeriod := newTaxedOrder("6", constants.OrderStatusDelivered, "Fresno", "", 100, 8)
+	outsidePeriod.UpdatedAt = time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	orders := []*models.Order{
+		newTaxedOrder("1", constants.OrderStatusDelivered, "Fresno", "qb1", 100, 8),
+		newTaxedOrder("2", constants.OrderStatusDelivered, "fresno", "qb2", 200, 0),
+		newTaxedOrder("3", constants.OrderStatusApproved, "Clovis", "qb3", 50, 10),
+		newTaxedOrder("4", constants.OrderStatusApproved, "Clovis", "", 500, 10),
+		newTaxedOrder("5", constants.OrderStatusDelivered, "Clovis", "", 20, 10),
+		outsidePeriod,
+	}
+	quickBooksTaxes := map[string]float64{"qb1": 8, "qb2": 0, "qb3": 5.5}
+
+	report := reporting.NewSalesTaxReport(orders, quickBooksTaxes, period)
+	if len(report.Rows) != 2 {
+		t.Fatalf("expected 2 jurisdictions, got %d", len(report.Rows))
+	}
+
+	clovis, fresno := report.Rows[0], report.Rows[1]
+	if clovis.Jurisdiction.City != "CLOVIS" || fresno.Jurisdiction.City != "FRESNO" {
+		t.Fatalf("unexpected jurisdictions %+v, %+v", clovis.Jurisdiction, fresno.Jurisdiction)
+	}
+	if fresno.Orders != 2 || fresno.TaxableSales != 100 || fresno.ExemptSales != 200 || fresno.TaxCollected != 8 {
+		t.Errorf("unexpected fresno row %+v", fresno)
+	}
+	if clovis.Orders != 2 || clovis.TaxableSales != 70 || clovis.TaxCollected != 7 {
+		t.Errorf("unexpected clovis row %+v", clovis)
+	}
+	if clovis.GetTaxDifference() != 0.5 {
+		t.Errorf("expected a clovis difference of 0.5, got %v", clovis.GetTaxDifference())
+	}
+	if report.Totals.TaxCollected != 15 || report.Totals.QuickBooksTax != 13.5 {
+		t.Errorf("unexpected totals %+v", report.Totals)
+	}
+	if len(report.UninvoicedOrders) != 1 || report.UninvoicedOrders[0] != "5" {
+		t.Errorf("expected order 5 to not be invoiced, got %v", report.UninvoicedOrders)
+	}
+	if len(report.Discrepancies) != 1 || report.Discrepancies[0].OrderID != "3" {
+		t.Errorf("expected a discrepancy for order 3, got %v", report.Discrepancies)
+	}
+	if table := report.ToTable(); len(table.Rows) != 2 || len(table.Columns) != len(table.Rows[0]) {
+		t.Errorf("unexpected table %+v", table)
+	}
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/reporting"
+)
+
+func newOrder(customerID string, status string, updatedAt time.Time, items ...*models.Product) *models.Order {
+	return &models.Order{
+		ID:        customerID + updatedAt.String(),
+		Customer:  &models.Customer{ID: customerID, Name: "Customer " + customerID},
+		Status:    status,
+		UpdatedAt: updatedAt,
+		Items:     items,
+	}
+}
+
+func TestNewSalesReport(t *testing.T) {
+	period := reporting.NewMonthPeriod(2025, time.March, time.UTC)
+	orders := []*models.Order{
+		newOrder("1", constants.OrderStatusDelivered, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+			&models.Product{ID: "p1", Brand: "Clorox", Quantity: 2, Price: 10, PurchasePrice: 6},
+			&models.Product{ID: "p2", Brand: "Zep", Quantity: 1, Price: 50, PurchasePrice: 40},
+		),
+		newOrder("2", constants.OrderStatusDelivered, time.Date(2025, 3, 31, 23, 0, 0, 0, time.UTC),
+			&models.Product{ID: "p1", Brand: "Clorox", Quantity: 3, Price: 10, PurchasePrice: 6},
+		),
+		newOrder("3", constants.OrderStatusApproved, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+			&models.Product{ID: "p1", Brand: "Clorox", Quantity: 100, Price: 10, PurchasePrice: 6},
+		),
+		newOrder("4", constants.OrderStatusDelivered, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			&models.Product{ID: "p1", Brand: "Clorox", Quantity: 100, Price: 10, PurchasePrice: 6},
+		),
+	}
+
+	report, err := reporting.NewSalesReport(orders, reporting.DimensionBrand, period)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(report.Rows))
+	}
+	clorox := report.Rows[0] // Equal sales are sorted by label
+	if clorox.Label != "Clorox" || clorox.Current.Units != 5 || clorox.Current.Sales != 50 || clorox.Current.COGS != 30 {
+		t.Errorf("unexpected clorox row %+v", clorox)
+	}
+	if clorox.Current.GetGrossMargin() != 20 || clorox.Current.GetMarginPercent() != 40 {
+		t.Errorf("unexpected clorox margin %v %v", clorox.Current.GetGrossMargin(), clorox.Current.GetMarginPercent())
+	}
+	if report.Totals.Sales != 100 || report.Totals.COGS != 70 || report.Totals.Units != 6 {
+		t.Errorf("unexpected totals %+v", report.Totals)
+	}
+
+	if _, err := reporting.NewSalesReport(orders, reporting.Dimension("REGION"), period); err == nil {
+		t.Error("expected an error for an invalid dimension")
+	}
+}
+
+func TestSalesReportCompareWith(t *testing.T) {
+	period := reporting.NewMonthPeriod(2025, time.March, time.UTC)
+	current, _ := reporting.NewSalesReport([]*models.Order{
+		newOrder("1", constants.OrderStatusDelivered, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), &models.Product{ID: "p1", Quantity: 3, Price: 10}),
+	}, reporting.DimensionCustomer, period)
+	previous, _ := reporting.NewSalesReport([]*models.Order{
+		newOrder("1", constants.OrderStatusDelivered, time.Date(2025, 2, 5, 0, 0, 0, 0, time.UTC), &models.Product{ID: "p1", Quantity: 2, Price: 10}),
+		newOrder("2", constants.OrderStatusDelivered, time.Date(2025, 2, 6, 0, 0, 0, 0, time.UTC), &models.Product{ID: "p1", Quantity: 1, Price: 10}),
+	}, reporting.DimensionCustomer, period.GetPreviousMonth())
+
+	current.CompareWith(previous)
+	if len(current.Rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(current.Rows))
+	}
+	change, ok := current.Rows[0].GetSalesChangePercent()
+	if !ok || change != 50 {
+		t.Errorf("expected a 50%% sales change, got %v %v", change, ok)
+	}
+	change, ok = current.Rows[1].GetSalesChangePercent()
+	if !ok || change != -100 {
+		t.Errorf("expected a -100%% sales change for the customer without current sales, got %v %v", change, ok)
+	}
+
+	table := current.ToTable()
+	if len(table.Columns) != 8 || len(table.Rows) != 2 {
+		t.Errorf("unexpected table %d columns %d rows", len(table.Columns), len(table.Rows))
+	}
+}
+
+func TestNewMonthPeriod(t *testing.T) {
+	period := reporting.NewMonthPeriod(2025, time.January, time.UTC)
+	previous := period.GetPreviousMonth()
+	if !previous.From.Equal(time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)) || !previous.To.Equal(period.From) {
+		t.Errorf("unexpected previous month %+v", previous)
+	}
+	if period.Format() != "January 1, 2025 - January 31, 2025" {
+		t.Errorf("unexpected format %s", period.Format())
+	}
+}
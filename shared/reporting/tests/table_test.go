@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/reporting"
+)
+
+func newTestTable() *reporting.Table {
+	return &reporting.Table{
+		Title: "TEST",
+		Columns: []reporting.Column{
+			{Header: "NAME", Width: 60, Format: reporting.ColumnFormatText},
+			{Header: "UNITS", Width: 30, Format: reporting.ColumnFormatInteger},
+			{Header: "SALES", Width: 30, Format: reporting.ColumnFormatCurrency},
+		},
+		Rows: [][]any{
+			{"A & B", 2, 10.5},
+			{"C, D", 1, nil},
+		},
+		Summary: []reporting.SummaryLine{{Label: "SALES", Value: 10.5, Format: reporting.ColumnFormatCurrency}},
+	}
+}
+
+func TestTableWriteCSV(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := newTestTable().WriteCSV(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	expected := "NAME,UNITS,SALES\nA & B,2,10.50\n\"C, D\",1,\nSALES,10.50,\n"
+	if buffer.String() != expected {
+		t.Errorf("unexpected csv:\n%s", buffer.String())
+	}
+}
+
+func TestTableWriteXLSX(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := newTestTable().WriteXLSX(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sheet string
+	for _, file := range reader.File {
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			rc, _ := file.Open()
+			content, _ := io.ReadAll(rc)
+			rc.Close()
+			sheet = string(content)
+		}
+	}
+	for _, expected := range []string{`<c r="A2" t="inlineStr"><is><t>A &amp; B</t></is></c>`, `<c r="B2"><v>2</v></c>`, `<c r="C2"><v>10.5</v></c>`, `<row r="4">`} {
+		if !strings.Contains(sheet, expected) {
+			t.Errorf("expected sheet to contain %s", expected)
+		}
+	}
+}
+
+func TestTableToPDFLayout(t *testing.T) {
+	report := newTestTable().ToPDFLayout()
+	if report.TableValues[0][2] != "$10.50" || report.TableValues[1][2] != "" || report.SummaryValues[0] != "$10.50" {
+		t.Errorf("unexpected pdf values %+v %+v", report.TableValues, report.SummaryValues)
+	}
+}
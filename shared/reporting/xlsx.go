@@ -0,0 +1,96 @@
+package reporting
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// The minimum set of parts of an xlsx workbook with a single worksheet. Strings are written inline so no
+// shared strings part is needed.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Report" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+)
+
+// WriteXLSX writes the headers, the rows and then the summary lines as a single sheet xlsx workbook. Numbers
+// are written as number cells so they can be summed in a spreadsheet.
+func (t *Table) WriteXLSX(w io.Writer) error {
+	zipWriter := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", []byte(xlsxContentTypes)},
+		{"_rels/.rels", []byte(xlsxRootRels)},
+		{"xl/workbook.xml", []byte(xlsxWorkbook)},
+		{"xl/_rels/workbook.xml.rels", []byte(xlsxWorkbookRels)},
+		{"xl/worksheets/sheet1.xml", t.getXLSXSheet()},
+	}
+	for _, part := range parts {
+		partWriter, err := zipWriter.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := partWriter.Write(part.content); err != nil {
+			return err
+		}
+	}
+	return zipWriter.Close()
+}
+
+func (t *Table) getXLSXSheet() []byte {
+	var sheet bytes.Buffer
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	headers := make([]any, len(t.Columns))
+	for i, header := range t.getHeaders() {
+		headers[i] = header
+	}
+	rows := append([][]any{headers}, t.Rows...)
+	rows = append(rows, t.getSummaryRows()...)
+	for i, row := range rows {
+		fmt.Fprintf(&sheet, `<row r="%d">`, i+1)
+		for j, value := range row {
+			writeXLSXCell(&sheet, getXLSXCellRef(j, i+1), value)
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+	return sheet.Bytes()
+}
+
+func writeXLSXCell(sheet *bytes.Buffer, ref string, value any) {
+	switch v := value.(type) {
+	case nil:
+		return
+	case int:
+		fmt.Fprintf(sheet, `<c r="%s"><v>%d</v></c>`, ref, v)
+	case float64:
+		fmt.Fprintf(sheet, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		fmt.Fprintf(sheet, `<c r="%s" t="inlineStr"><is><t>`, ref)
+		xml.EscapeText(sheet, []byte(fmt.Sprintf("%v", v)))
+		sheet.WriteString(`</t></is></c>`)
+	}
+}
+
+// getXLSXCellRef returns the reference of a cell, e.g. column 0 row 1 is A1 and column 27 row 3 is AB3
+func getXLSXCellRef(column, row int) string {
+	name := ""
+	for column >= 0 {
+		name = string(rune('A'+column%26)) + name
+		column = column/26 - 1
+	}
+	return fmt.Sprintf("%s%d", name, row)
+}
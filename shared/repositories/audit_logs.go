@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	firebase_shared "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/firebase"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+// CreateAuditEntryInFirestore adds a new audit entry to firestore collection ('audit_logs').
+//
+// Params:
+//   - ctx: context
+//   - entry: *models.AuditEntry
+//
+// Returns:
+//   - error: error
+func CreateAuditEntryInFirestore(ctx context.Context, entry *models.AuditEntry) error {
+	_, _, err := firebase_shared.FirestoreClient.Collection(constants.AuditLogsCollection).Add(ctx, entry.ToMap())
+	return err
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	firebase_shared "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/firebase"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+// FetchAllBrandsFromFirestore fetches every brand, active or not, from the firestore collection ('brands').
+//
+// Params:
+//   - ctx: context
+//
+// Returns:
+//   - []*models.Brand
+//   - error
+func FetchAllBrandsFromFirestore(ctx context.Context) ([]*models.Brand, error) {
+	docs, err := firebase_shared.FirestoreClient.Collection(constants.BrandsCollection).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	brands := make([]*models.Brand, 0, len(docs))
+	for _, doc := range docs {
+		var brand models.Brand
+		if err := doc.DataTo(&brand); err != nil {
+			return nil, fmt.Errorf("error decoding brand %s: %v", doc.Ref.ID, err)
+		}
+		brands = append(brands, &brand)
+	}
+	return brands, nil
+}
+
+// FetchBrandCatalogFromFirestore fetches the brands as a catalog to look them up by name or alias.
+//
+// Params:
+//   - ctx: context
+//
+// Returns:
+//   - *models.BrandCatalog
+//   - error
+func FetchBrandCatalogFromFirestore(ctx context.Context) (*models.BrandCatalog, error) {
+	brands, err := FetchAllBrandsFromFirestore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewBrandCatalog(brands), nil
+}
+
+// CreateBrandInFirestore creates a brand in the firestore collection ('brands').
+//
+// Params:
+//   - ctx: context
+//   - brand: the brand, see models.NewBrand
+//
+// Returns:
+//   - error: if a brand with the same id already exists or the write fails
+func CreateBrandInFirestore(ctx context.Context, brand *models.Brand) error {
+	_, err := firebase_shared.FirestoreClient.Collection(constants.BrandsCollection).Doc(brand.ID).Create(ctx, brand.ToMap())
+	return err
+}
+
+// UpdateBrandInFirestore updates the display name, aliases, logo or active flag of a brand. Renaming a brand
+// does not rename it in the products, they get the new name on the next quickbooks sync.
+//
+// Params:
+//   - ctx: context
+//   - brandID: id of the brand
+//   - details: the changed fields
+//
+// Returns:
+//   - error
+func UpdateBrandInFirestore(ctx context.Context, brandID string, details map[string]any) error {
+	details["updatedAt"] = firestore.ServerTimestamp
+	_, err := firebase_shared.FirestoreClient.Collection(constants.BrandsCollection).Doc(brandID).Set(ctx, details, firestore.MergeAll)
+	return err
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"errors"
+
+	"cloud.google.com/go/firestore"
+)
+
+// bulkWriterJobs collects the jobs of a BulkWriter. A BulkWriter only reports a failed write through the
+// results of its job, so the jobs are checked once the writes are done.
+type bulkWriterJobs []*firestore.BulkWriterJob
+
+// add adds the job of a write, it returns the error of a write that could not be queued.
+func (jobs *bulkWriterJobs) add(job *firestore.BulkWriterJob, err error) error {
+	if err != nil {
+		return err
+	}
+	*jobs = append(*jobs, job)
+	return nil
+}
+
+// end ends the BulkWriter, which waits for all the writes, and returns the joined errors of the failed
+// writes.
+func (jobs bulkWriterJobs) end(bulkWriter *firestore.BulkWriter) error {
+	bulkWriter.End()
+	var errs []error
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// succeeded returns the number of jobs whose write succeeded, it waits for the writes so it is called after
+// end.
+func (jobs bulkWriterJobs) succeeded() int {
+	count := 0
+	for _, job := range jobs {
+		if _, err := job.Results(); err == nil {
+			count++
+		}
+	}
+	return count
+}
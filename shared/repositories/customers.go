@@ -21,6 +21,9 @@ import (
 // Returns:
 //   - *firestore_models.Customer
 //   - error: error
+//
+// Note: the access of the caller is not checked, a request of a scoped caller goes through
+// services.FetchCustomerForCaller.
 func FetchCustomerFromFirestore(id string, ctx context.Context) (*models.Customer, error) {
 
 	docSnapshot, err := firebase_shared.FirestoreClient.Collection(constants.CustomersCollection).Doc(id).Get(ctx)
@@ -36,6 +39,9 @@ func FetchCustomerFromFirestore(id string, ctx context.Context) (*models.Custome
 }
 
 // Only fetches active customers from firestore collection ('customers')
+//
+// Note: the access of the caller is not checked, a request of a scoped caller goes through
+// services.FetchCustomersForCaller.
 func FetchAllCustomersFromFirestore(ctx context.Context) ([]*models.Customer, error) {
 
 	customers, err := firebase_shared.FirestoreClient.Collection(constants.CustomersCollection).Where("isActive", "==", true).Documents(ctx).GetAll()
@@ -69,16 +75,16 @@ func SyncQuickbookCustomerRespToFirestore(qbItemsResponse *qbmodels.QBCustomersR
 	}
 
 	bulkWriter := firebase_shared.FirestoreClient.BulkWriter(ctx)
+	defer bulkWriter.End()
 
+	var jobs bulkWriterJobs
 	for _, customer := range qbItemsResponse.QueryResponse.Customer {
 		docRef := firebase_shared.FirestoreClient.Collection(constants.CustomersCollection).Doc(customer.ID)
-		_, err := bulkWriter.Set(docRef, customer.MapToCustomer().ToMap(), firestore.MergeAll)
-		if err != nil {
+		if err := jobs.add(bulkWriter.Set(docRef, customer.MapToCustomer().ToMap(), firestore.MergeAll)); err != nil {
 			return err
 		}
 	}
-	bulkWriter.Flush()
-	return nil
+	return jobs.end(bulkWriter)
 }
 
 //UpdateCustomerInFirestore updates current customer document in firestore. 
@@ -94,6 +100,9 @@ func SyncQuickbookCustomerRespToFirestore(qbItemsResponse *qbmodels.QBCustomersR
 // better to just pass the type any. In Addition to this, before calling this, make sure to always
 // and always double check if the key matches with the `firestore` key in the struct otherwise this
 // will create a new key with that value in document. 
+//
+// Note: the access of the caller is not checked, a request of a scoped caller goes through
+// services.UpdateCustomerForCaller.
 func UpdateCustomerInFirestore(ctx context.Context, customerID string, details any) error {
 	_, err := firebase_shared.FirestoreClient.Collection(constants.CustomersCollection).Doc(customerID).Set(ctx, details, firestore.MergeAll)
 	if err != nil {
@@ -114,4 +123,105 @@ func UpdateCustomerInFirestore(ctx context.Context, customerID string, details a
 func CreateCustomerInFirestore(ctx context.Context, customer *models.Customer) error {
 	_, err := firebase_shared.FirestoreClient.Collection(constants.CustomersCollection).Doc(customer.ID).Set(ctx, customer.ToMap())
 	return err
-}
\ No newline at end of file
+}
+
+// DeactivateCustomerInFirestore marks a customer as inactive in firestore collection ('customers').
+// Customers are never deleted from firestore since existing orders still reference them.
+//
+// Params:
+//   - ctx: context
+//   - customerID: id of the customer to deactivate
+//
+// Returns:
+//   - error: error
+func DeactivateCustomerInFirestore(ctx context.Context, customerID string) error {
+	return UpdateCustomerInFirestore(ctx, customerID, map[string]any{
+		"isActive":  false,
+		"updatedAt": firestore.ServerTimestamp,
+	})
+}
+
+// MergeCustomerInFirestore moves everything that references a merged customer to the surviving customer
+// and deactivates the merged customer. Used when two customers are merged in quickbooks. The merged customer
+// may be missing from firestore, only the surviving customer must exist.
+//
+// The following are re-pointed from mergedID to survivingID:
+//   - orders ('customerId')
+//   - user accounts ('customers')
+//   - products prices per customer
+//
+// Params:
+//   - ctx: context
+//   - mergedID: id of the customer that no longer exists in quickbooks
+//   - survivingID: id of the customer that mergedID was merged into
+//
+// Returns:
+//   - map[string]int: number of documents updated per collection, used for the audit entry
+//   - error: error
+func MergeCustomerInFirestore(ctx context.Context, mergedID, survivingID string) (map[string]int, error) {
+	customers, _, err := FetchCustomersByIDs(ctx, []string{mergedID, survivingID})
+	if err != nil {
+		return nil, err
+	}
+	survivingCustomer, ok := customers[survivingID]
+	if !ok {
+		return nil, fmt.Errorf("Surviving customer %s does not exist in firestore, sync it from quickbooks before the merge", survivingID)
+	}
+
+	updated := make(map[string]int)
+	updated[constants.OrdersCollection], err = ReassignOrdersCustomerInFirestore(ctx, mergedID, survivingCustomer)
+	if err != nil {
+		return updated, err
+	}
+	updated[constants.UsersCollection], err = ReassignUsersCustomerInFirestore(ctx, mergedID, survivingID)
+	if err != nil {
+		return updated, err
+	}
+	updated[constants.ProductsPricesPerCustCollection], err = ReassignProductPricesCustomerInFirestore(ctx, mergedID, survivingID)
+	if err != nil {
+		return updated, err
+	}
+	//A missing merged customer is not created just to deactivate it
+	if _, ok := customers[mergedID]; !ok {
+		return updated, nil
+	}
+	return updated, DeactivateCustomerInFirestore(ctx, mergedID)
+}
+
+// FetchCustomersByIDs fetches the customers with the given ids from firestore collection ('customers'),
+// including inactive customers. Missing customers, e.g. a customer merged and deleted in quickbooks, do not
+// fail the fetch and are returned in a separate list.
+//
+// Params:
+//   - ctx: context
+//   - customerIDs: ids of the customers
+//
+// Returns:
+//   - map[string]*models.Customer: key is the customer id, the missing customers are not in it
+//   - []string: ids of the missing customers
+//   - error: error
+func FetchCustomersByIDs(ctx context.Context, customerIDs []string) (map[string]*models.Customer, []string, error) {
+	docRefs := make([]*firestore.DocumentRef, len(customerIDs))
+	for i, customerID := range customerIDs {
+		docRefs[i] = firebase_shared.FirestoreClient.Collection(constants.CustomersCollection).Doc(customerID)
+	}
+	docSnapshots, err := firebase_shared.FirestoreClient.GetAll(ctx, docRefs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	customerMap := make(map[string]*models.Customer, len(docSnapshots))
+	notFound := make([]string, 0)
+	for i, docSnapshot := range docSnapshots {
+		if !docSnapshot.Exists() {
+			notFound = append(notFound, customerIDs[i])
+			continue
+		}
+		var customer models.Customer
+		if err := docSnapshot.DataTo(&customer); err != nil {
+			return nil, nil, fmt.Errorf("Error getting customer %s: %v", customerIDs[i], err)
+		}
+		customerMap[customer.ID] = &customer
+	}
+	return customerMap, notFound, nil
+}
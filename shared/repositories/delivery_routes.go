@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	firebase_shared "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/firebase"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+// CreateDeliveryRouteInFirestore saves a planned route in the firestore collection ('delivery_routes') and
+// sets the schedule of every order of the route, in a single transaction.
+//
+// Params:
+//   - ctx: context
+//   - route: the planned route, its orders must have their schedule set
+//
+// Returns:
+//   - error: if an order is no longer approved or is already scheduled on another route, or the write fails
+func CreateDeliveryRouteInFirestore(ctx context.Context, route *models.DeliveryRoute) error {
+	client := firebase_shared.FirestoreClient
+	return client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		orderRefs := make([]*firestore.DocumentRef, len(route.Stops))
+		for i, stop := range route.Stops {
+			orderRefs[i] = client.Collection(constants.OrdersCollection).Doc(stop.OrderID)
+		}
+		docs, err := tx.GetAll(orderRefs)
+		if err != nil {
+			return err
+		}
+		//Check the orders did not change since the route was planned
+		for _, doc := range docs {
+			if !doc.Exists() {
+				return fmt.Errorf("order with ID %s not found", doc.Ref.ID)
+			}
+			var order models.Order
+			if err := doc.DataTo(&order); err != nil {
+				return err
+			}
+			if order.Status != constants.OrderStatusApproved {
+				return fmt.Errorf("Order %s is %s, only approved orders can be scheduled for delivery", doc.Ref.ID, order.Status)
+			}
+			if order.Schedule != nil && order.Schedule.RouteID != route.ID {
+				return fmt.Errorf("Order %s is already scheduled for delivery on route %s", doc.Ref.ID, order.Schedule.RouteID)
+			}
+		}
+
+		if err := tx.Set(client.Collection(constants.DeliveryRoutesCollection).Doc(route.ID), route); err != nil {
+			return err
+		}
+		for i, stop := range route.Stops {
+			err := tx.Update(orderRefs[i], []firestore.Update{
+				{Path: "schedule", Value: stop.Order.Schedule},
+				{Path: "updatedAt", Value: route.UpdatedAt},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FetchDeliveryRouteFromFirestore fetches a route with the detailed order of every stop, used to print the
+// route sheet.
+//
+// Params:
+//   - ctx: context
+//   - routeID: id of the route
+//
+// Returns:
+//   - *models.DeliveryRoute
+//   - error: if the route or any of its orders can not be fetched
+func FetchDeliveryRouteFromFirestore(ctx context.Context, routeID string) (*models.DeliveryRoute, error) {
+	doc, err := firebase_shared.FirestoreClient.Collection(constants.DeliveryRoutesCollection).Doc(routeID).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var route models.DeliveryRoute
+	if err := doc.DataTo(&route); err != nil {
+		return nil, fmt.Errorf("failed to decode the delivery route %s: %w", routeID, err)
+	}
+	for _, stop := range route.Stops {
+		stop.Order, err = FetchDetailedOrderFromFirestore(stop.OrderID, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &route, nil
+}
+
+// FetchDeliveryRoutesForDriverFromFirestore fetches the routes assigned to a driver on a day, without the
+// orders of the stops.
+//
+// Params:
+//   - ctx: context
+//   - driverUID: uid of the driver
+//   - deliveryDate: the day of the deliveries, only the date is used
+//
+// Returns:
+//   - []*models.DeliveryRoute
+//   - error
+//
+// Note: the query requires a composite index on ('driverUid', 'deliveryDate').
+func FetchDeliveryRoutesForDriverFromFirestore(ctx context.Context, driverUID string, deliveryDate time.Time) ([]*models.DeliveryRoute, error) {
+	day := time.Date(deliveryDate.Year(), deliveryDate.Month(), deliveryDate.Day(), 0, 0, 0, 0, time.UTC)
+	docs, err := firebase_shared.FirestoreClient.Collection(constants.DeliveryRoutesCollection).
+		Where("driverUid", "==", driverUID).
+		Where("deliveryDate", "==", day).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	routes := make([]*models.DeliveryRoute, 0, len(docs))
+	for _, doc := range docs {
+		var route models.DeliveryRoute
+		if err := doc.DataTo(&route); err != nil {
+			return nil, fmt.Errorf("failed to decode the delivery route %s: %w", doc.Ref.ID, err)
+		}
+		routes = append(routes, &route)
+	}
+	return routes, nil
+}
+
+// FetchDeliveryRoutesForVehicleFromFirestore fetches the routes of a vehicle on a day with the detailed order
+// of every stop, used to check the combined load of the vehicle.
+//
+// Params:
+//   - ctx: context
+//   - vehicleID: id of the vehicle
+//   - deliveryDate: the day of the deliveries, only the date is used
+//
+// Returns:
+//   - []*models.DeliveryRoute
+//   - error: if the routes or any of their orders can not be fetched
+//
+// Note: the query requires a composite index on ('vehicleId', 'deliveryDate').
+func FetchDeliveryRoutesForVehicleFromFirestore(ctx context.Context, vehicleID string, deliveryDate time.Time) ([]*models.DeliveryRoute, error) {
+	day := time.Date(deliveryDate.Year(), deliveryDate.Month(), deliveryDate.Day(), 0, 0, 0, 0, time.UTC)
+	docs, err := firebase_shared.FirestoreClient.Collection(constants.DeliveryRoutesCollection).
+		Where("vehicleId", "==", vehicleID).
+		Where("deliveryDate", "==", day).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	routes := make([]*models.DeliveryRoute, 0, len(docs))
+	for _, doc := range docs {
+		var route models.DeliveryRoute
+		if err := doc.DataTo(&route); err != nil {
+			return nil, fmt.Errorf("failed to decode the delivery route %s: %w", doc.Ref.ID, err)
+		}
+		for _, stop := range route.Stops {
+			stop.Order, err = FetchDetailedOrderFromFirestore(stop.OrderID, ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		routes = append(routes, &route)
+	}
+	return routes, nil
+}
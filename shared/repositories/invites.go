@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"firebase.google.com/go/v4/auth"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	firebase_shared "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/firebase"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+// CreateInviteInFirestore creates a new invite in firestore collection ('invites') and sets its id.
+//
+// Params:
+//   - ctx: context
+//   - invite: the pending invite
+//
+// Returns:
+//   - error: error
+func CreateInviteInFirestore(ctx context.Context, invite *models.Invite) error {
+	docRef := firebase_shared.FirestoreClient.Collection(constants.InvitesCollection).NewDoc()
+	invite.ID = docRef.ID
+	_, err := docRef.Create(ctx, invite)
+	return err
+}
+
+// UpdateInviteInFirestore replaces an invite in firestore collection ('invites').
+func UpdateInviteInFirestore(ctx context.Context, invite *models.Invite) error {
+	_, err := firebase_shared.FirestoreClient.Collection(constants.InvitesCollection).Doc(invite.ID).Set(ctx, invite)
+	return err
+}
+
+// FetchInviteFromFirestore fetches an invite from firestore collection ('invites').
+//
+// Params:
+//   - ctx: context
+//   - inviteID: id of the invite
+//
+// Returns:
+//   - *models.Invite
+//   - error: if the invite does not exist or the read fails
+func FetchInviteFromFirestore(ctx context.Context, inviteID string) (*models.Invite, error) {
+	docSnapshot, err := firebase_shared.FirestoreClient.Collection(constants.InvitesCollection).Doc(inviteID).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var invite models.Invite
+	if err := docSnapshot.DataTo(&invite); err != nil {
+		return nil, fmt.Errorf("Error getting invite %s: %v", inviteID, err)
+	}
+	return &invite, nil
+}
+
+// FetchPendingInviteForEmailFromFirestore fetches the pending invite of an email, expired or not.
+//
+// Returns:
+//   - *models.Invite: nil if the email has no pending invite
+//   - error: error
+func FetchPendingInviteForEmailFromFirestore(ctx context.Context, email string) (*models.Invite, error) {
+	docSnapshots, err := firebase_shared.FirestoreClient.Collection(constants.InvitesCollection).
+		Where("email", "==", email).
+		Where("status", "==", constants.InviteStatusPending).
+		Limit(1).
+		Documents(ctx).GetAll()
+	if err != nil || len(docSnapshots) == 0 {
+		return nil, err
+	}
+	var invite models.Invite
+	if err := docSnapshots[0].DataTo(&invite); err != nil {
+		return nil, fmt.Errorf("Error getting invite %s: %v", docSnapshots[0].Ref.ID, err)
+	}
+	return &invite, nil
+}
+
+// AcceptInviteInFirestore creates the account of the invited user and marks the invite accepted in a single
+// transaction, so an invite is never accepted twice.
+//
+// Params:
+//   - ctx: context
+//   - inviteID: id of the invite
+//   - uid: firebase uid of the signed in user
+//   - expiresAt: expiry of the token sent back by the user, see models.Invite.MatchesToken
+//   - now: time of the acceptance
+//
+// Returns:
+//   - *models.UserAccount: the created account
+//   - error: if the invite cannot be accepted or the transaction fails
+func AcceptInviteInFirestore(ctx context.Context, inviteID, uid string, expiresAt, now time.Time) (*models.UserAccount, error) {
+	inviteRef := firebase_shared.FirestoreClient.Collection(constants.InvitesCollection).Doc(inviteID)
+	userRef := firebase_shared.FirestoreClient.Collection(constants.UsersCollection).Doc(uid)
+
+	var userAccount *models.UserAccount
+	err := firebase_shared.FirestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docSnapshot, err := tx.Get(inviteRef)
+		if err != nil {
+			return err
+		}
+		var invite models.Invite
+		if err := docSnapshot.DataTo(&invite); err != nil {
+			return err
+		}
+		if err := invite.CanAccept(uid, now); err != nil {
+			return err
+		}
+		if !invite.MatchesToken(expiresAt) {
+			return fmt.Errorf("Invite link was replaced by a newer one, please use the link of the latest email")
+		}
+
+		userAccount = invite.ToUserAccount()
+		if err := tx.Create(userRef, userAccount); err != nil {
+			return err
+		}
+		return tx.Update(inviteRef, []firestore.Update{
+			{Path: "status", Value: constants.InviteStatusAccepted},
+			{Path: "acceptedAt", Value: now},
+			{Path: "updatedAt", Value: now},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userAccount, nil
+}
+
+// CreateInvitedUserInFirebase creates the firebase user of an invite without a password, the user sets it
+// from the password setup link, see GeneratePasswordSetupLink.
+//
+// Returns:
+//   - string: the uid of the user
+//   - error: if the email is already used or the user could not be created
+func CreateInvitedUserInFirebase(ctx context.Context, invite *models.Invite) (string, error) {
+	params := (&auth.UserToCreate{}).Email(invite.Email).DisplayName(invite.Name)
+	userRecord, err := firebase_shared.AuthClient.CreateUser(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	return userRecord.UID, nil
+}
+
+// DeleteUserInFirebase deletes a firebase user, used for the users of revoked invites that never signed in.
+// A user that was already deleted is not an error so the revoke can be retried.
+func DeleteUserInFirebase(ctx context.Context, uid string) error {
+	err := firebase_shared.AuthClient.DeleteUser(ctx, uid)
+	if auth.IsUserNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// GeneratePasswordSetupLink generates the firebase link the invited user sets their password with. Once the
+// password is set the user is sent to the continue url.
+//
+// Params:
+//   - ctx: context
+//   - email: email of the user
+//   - continueURL: url opened after the password is set, it must be an authorized domain of the project
+//
+// Returns:
+//   - string: the link
+//   - error: error
+func GeneratePasswordSetupLink(ctx context.Context, email, continueURL string) (string, error) {
+	return firebase_shared.AuthClient.PasswordResetLinkWithSettings(ctx, email, &auth.ActionCodeSettings{URL: continueURL})
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	"cloud.google.com/go/storage"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
 	firebase_shared "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/firebase"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/utils"
 )
 
 // CreateOrderInFirestore creates a new order document in the Firestore "orders" collection.
@@ -23,6 +27,9 @@ import (
 //
 // Returns:
 //   - error: Returns an error if the Firestore insertion fails; otherwise, returns nil.
+//
+// Note: the access of the caller is not checked, a request of a scoped caller goes through
+// services.CreateOrderForCaller.
 func CreateOrderInFirestore(order *models.Order, ctx context.Context) error {
 
 	_, err := firebase_shared.FirestoreClient.Collection(constants.OrdersCollection).Doc(order.ID).Set(ctx, order.ToMap())
@@ -89,6 +96,50 @@ func UploadOrderFileToStorage(orderID string, base64Str string, fileName string,
 	return nil
 }
 
+// UploadDeliveryImagesToStorage uploads the full resolution delivery images of an order to Cloud Storage
+// under the path "orders/{orderID}/delivery_images/image-{n}.{ext}".
+//
+// Parameters:
+//   - ctx: the context for the upload operation
+//   - orderID: the Firestore order document ID
+//   - images: the original bytes of the delivery images
+//
+// Returns:
+//   - []string: the download url of every image, in the same order as the images
+//   - error: if the upload of any image fails
+//
+// Note:
+//   - Every image gets a firebase download token so the urls work without making the bucket public
+func UploadDeliveryImagesToStorage(ctx context.Context, orderID string, images [][]byte) ([]string, error) {
+	bucket, err := firebase_shared.StorageClient.Bucket(firebase_shared.StorageBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, 0, len(images))
+	for i, imageBytes := range images {
+		imageType := utils.DetectImageType(imageBytes)
+		objectName := fmt.Sprintf("%s/%s/%s/image-%d.%s", constants.OrdersCollection, orderID, constants.DeliveryImagesFolder, i+1, imageType)
+		token, err := utils.GenerateRandomSecret()
+		if err != nil {
+			return nil, err
+		}
+
+		writer := bucket.Object(objectName).NewWriter(ctx)
+		writer.ContentType = "image/" + imageType
+		writer.Metadata = map[string]string{"firebaseStorageDownloadTokens": token}
+		if _, err := writer.Write(imageBytes); err != nil {
+			writer.Close()
+			return nil, fmt.Errorf("failed to write delivery image %d to Cloud Storage: %w", i+1, err)
+		}
+		if err := writer.Close(); err != nil {
+			return nil, fmt.Errorf("failed to close writer: %w", err)
+		}
+		urls = append(urls, fmt.Sprintf("https://firebasestorage.googleapis.com/v0/b/%s/o/%s?alt=media&token=%s", firebase_shared.StorageBucket, url.PathEscape(objectName), token))
+	}
+	return urls, nil
+}
+
 // UpdateOrderInFirestore updates specific fields of an order in Firestore.
 //
 // Parameters:
@@ -100,6 +151,9 @@ func UploadOrderFileToStorage(orderID string, base64Str string, fileName string,
 //   - This function uses `firestore.MergeAll` to update only the specified fields in the existing document.
 //   - Pass only the necessary fields in `details`. Passing the entire order object may cause errors if it includes
 //     fields that are not mapped correctly or are excluded with the `firestore:"-"` tag.
+//
+// Note: the access of the caller is not checked, a request of a scoped caller goes through
+// services.UpdateOrderForCaller.
 func UpdateOrderInFirestore(ctx context.Context, orderID string, updates []firestore.Update) error {
 	_, err := firebase_shared.FirestoreClient.Collection(constants.OrdersCollection).Doc(orderID).Update(ctx, updates)
 	if err != nil {
@@ -148,6 +202,9 @@ func FetchOrderFromFirestore(orderID string, ctx context.Context) (*models.Order
 // Returns:
 // - A pointer to the enriched `Order` struct containing full customer and product information.
 // - An error if any part of the fetch or conversion fails.
+//
+// Note: the access of the caller is not checked, a request of a scoped caller goes through
+// services.FetchOrderForCaller.
 func FetchDetailedOrderFromFirestore(orderID string, ctx context.Context) (*models.Order, error) {
 	docSnapshot, err := firebase_shared.FirestoreClient.Collection(constants.OrdersCollection).Doc(orderID).Get(ctx)
 	if err != nil {
@@ -197,3 +254,133 @@ func DoesShippingManifestExist(ctx context.Context, orderID string) (bool, error
 	}
 	return true, nil
 }
+
+// ReassignOrdersCustomerInFirestore points every order of a customer to another customer. Used when
+// customers are merged in quickbooks.
+//
+// Parameters:
+//   - ctx: Context for Firestore operations.
+//   - fromCustomerID: id of the customer the orders currently belong to.
+//   - toCustomer: customer the orders are moved to.
+//
+// Returns:
+//   - int: number of orders updated.
+//   - error: if fetching or updating the orders fails.
+func ReassignOrdersCustomerInFirestore(ctx context.Context, fromCustomerID string, toCustomer *models.Customer) (int, error) {
+	docs, err := firebase_shared.FirestoreClient.Collection(constants.OrdersCollection).Where("customerId", "==", fromCustomerID).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+
+	bulkWriter := firebase_shared.FirestoreClient.BulkWriter(ctx)
+	defer bulkWriter.End()
+
+	var jobs bulkWriterJobs
+	for _, doc := range docs {
+		err := jobs.add(bulkWriter.Update(doc.Ref, []firestore.Update{
+			{Path: "customerId", Value: toCustomer.ID},
+			{Path: "customerName", Value: strings.ToLower(toCustomer.Name)},
+		}))
+		if err != nil {
+			return 0, err
+		}
+	}
+	if err := jobs.end(bulkWriter); err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// FetchDeliveredOrdersFromFirestore fetches the delivered orders last updated within [from, to) with their
+// customers and complete items. Used by the reports, which count an order when it is delivered.
+//
+// Parameters:
+//   - ctx: Context for Firestore operations.
+//   - from: inclusive start of the period.
+//   - to: exclusive end of the period.
+//
+// Returns:
+//   - []*models.Order: the detailed orders.
+//   - error: if fetching the orders, customers or products fails.
+func FetchDeliveredOrdersFromFirestore(ctx context.Context, from, to time.Time) ([]*models.Order, error) {
+	return FetchDetailedOrdersByStatusFromFirestore(ctx, []string{constants.OrderStatusDelivered}, from, to)
+}
+
+// FetchDetailedOrdersByStatusFromFirestore fetches the orders with any of the statuses last updated within
+// [from, to) with their customers and complete items.
+//
+// Parameters:
+//   - ctx: Context for Firestore operations.
+//   - statuses: order statuses, at most 30 (firestore 'in' limit).
+//   - from: inclusive start of the period.
+//   - to: exclusive end of the period.
+//
+// Returns:
+//   - []*models.Order: the detailed orders.
+//   - error: if fetching the orders, customers or products fails.
+//
+// Note: the query requires a composite index on ('status', 'updatedAt').
+//
+// Note: the access of the caller is not checked, a request of a scoped caller goes through
+// services.FetchOrdersForCaller.
+func FetchDetailedOrdersByStatusFromFirestore(ctx context.Context, statuses []string, from, to time.Time) ([]*models.Order, error) {
+	docs, err := firebase_shared.FirestoreClient.Collection(constants.OrdersCollection).
+		Where("status", "in", statuses).
+		Where("updatedAt", ">=", from).
+		Where("updatedAt", "<", to).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]*models.Order, 0, len(docs))
+	customerIDs := make([]string, 0)
+	productIDs := make([]string, 0)
+	orderCustomers := make(map[string]*models.Customer, len(docs)) // Customer stored in the order, by order id
+	seenCustomers := make(map[string]bool)
+	seenProducts := make(map[string]bool)
+	for _, doc := range docs {
+		var order models.Order
+		if err := doc.DataTo(&order); err != nil {
+			return nil, fmt.Errorf("Error decoding order %s: %v", doc.Ref.ID, err)
+		}
+		order.SetID(doc.Ref.ID)
+		orders = append(orders, &order)
+
+		customerID, _ := doc.Data()["customerId"].(string)
+		customerName, _ := doc.Data()["customerName"].(string)
+		orderCustomers[order.ID] = &models.Customer{ID: customerID, Name: customerName}
+		if !seenCustomers[customerID] {
+			seenCustomers[customerID] = true
+			customerIDs = append(customerIDs, customerID)
+		}
+		for _, productID := range order.ToProductIDs() {
+			if !seenProducts[productID] {
+				seenProducts[productID] = true
+				productIDs = append(productIDs, productID)
+			}
+		}
+	}
+	if len(orders) == 0 {
+		return orders, nil
+	}
+
+	//Orders of a missing customer keep the customer stored in the order
+	customerMap, _, err := FetchCustomersByIDs(ctx, customerIDs)
+	if err != nil {
+		return nil, err
+	}
+	productMap, err := FetchAllProductsByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		if customer, ok := customerMap[orderCustomers[order.ID].ID]; ok {
+			order.SetCustomer(customer)
+		} else {
+			order.SetCustomer(orderCustomers[order.ID])
+		}
+		order.ToCompleteOrderItemsFromMinimal(productMap)
+	}
+	return orders, nil
+}
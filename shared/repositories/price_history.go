@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	firebase_shared "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/firebase"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// addPriceChangeToBulkWriter appends a price change to the price history subcollection ('price_history')
+// of the parent document.
+func addPriceChangeToBulkWriter(bulkWriter *firestore.BulkWriter, parentRef *firestore.DocumentRef, priceChange *models.PriceChange) (*firestore.BulkWriterJob, error) {
+	docRef := parentRef.Collection(constants.PriceHistorySubcollection).NewDoc()
+	return bulkWriter.Create(docRef, priceChange.ToMap())
+}
+
+// FetchPriceChangesForCustomerSince fetches the price changes of a customer made after the given time from
+// all the price history subcollections ('price_history'), oldest first.
+//
+// Params:
+//   - ctx: context
+//   - customerID: id of the customer
+//   - since: only changes after this time are returned, zero for all changes
+//
+// Returns:
+//   - []*models.PriceChange
+//   - error
+func FetchPriceChangesForCustomerSince(ctx context.Context, customerID string, since time.Time) ([]*models.PriceChange, error) {
+	docs, err := firebase_shared.FirestoreClient.CollectionGroup(constants.PriceHistorySubcollection).
+		Where("customerId", "==", customerID).
+		Where("changedAt", ">", since).
+		OrderBy("changedAt", firestore.Asc).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	priceChanges := make([]*models.PriceChange, len(docs))
+	for i, doc := range docs {
+		var priceChange models.PriceChange
+		if err := doc.DataTo(&priceChange); err != nil {
+			return nil, fmt.Errorf("error decoding price change %s: %v", doc.Ref.Path, err)
+		}
+		priceChanges[i] = &priceChange
+	}
+	return priceChanges, nil
+}
+
+// FetchLastPriceChangeNoticeTime fetches the time of the last price change notice sent to an admin for a
+// customer from firestore collection ('price_change_notices'). Returns a zero time if no notice was ever sent.
+func FetchLastPriceChangeNoticeTime(ctx context.Context, customerID, adminEmail string) (time.Time, error) {
+	doc, err := firebase_shared.FirestoreClient.Collection(constants.PriceChangeNoticesCollection).Doc(getPriceChangeNoticeID(customerID, adminEmail)).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	var notice struct {
+		SentAt time.Time `firestore:"sentAt"`
+	}
+	if err := doc.DataTo(&notice); err != nil {
+		return time.Time{}, err
+	}
+	return notice.SentAt, nil
+}
+
+// SavePriceChangeNoticeTime saves the time of the last price change included in the notice sent to an admin
+// for a customer.
+//
+// Params:
+//   - ctx: context
+//   - customerID: id of the customer
+//   - adminEmail: email of the admin the notice was sent to
+//   - sentAt: changedAt time of the latest price change included in the notice
+//
+// Returns:
+//   - error: error
+func SavePriceChangeNoticeTime(ctx context.Context, customerID, adminEmail string, sentAt time.Time) error {
+	_, err := firebase_shared.FirestoreClient.Collection(constants.PriceChangeNoticesCollection).Doc(getPriceChangeNoticeID(customerID, adminEmail)).Set(ctx, map[string]any{
+		"customerId": customerID,
+		"adminEmail": adminEmail,
+		"sentAt":     sentAt,
+	})
+	return err
+}
+
+// getPriceChangeNoticeID returns the id of the notice document of an admin for a customer.
+func getPriceChangeNoticeID(customerID, adminEmail string) string {
+	return fmt.Sprintf("%s_%s", customerID, strings.ToLower(adminEmail))
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	firebase_shared "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/firebase"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+// FetchAllPriceRulesFromFirestore fetches all active price rules from firestore collection ('price_rules').
+func FetchAllPriceRulesFromFirestore(ctx context.Context) ([]*models.PriceRule, error) {
+	docs, err := firebase_shared.FirestoreClient.Collection(constants.PriceRulesCollection).Where("isActive", "==", true).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return decodePriceRules(docs)
+}
+
+// FetchPriceRulesForCustomerFromFirestore fetches the active price rules that apply to a customer, the rules
+// set for the customer and the rules set for all customers (empty 'customerId').
+//
+// Params:
+//   - ctx: context
+//   - customerID: id of the customer
+//
+// Returns:
+//   - []*models.PriceRule
+//   - error
+func FetchPriceRulesForCustomerFromFirestore(ctx context.Context, customerID string) ([]*models.PriceRule, error) {
+	docs, err := firebase_shared.FirestoreClient.Collection(constants.PriceRulesCollection).
+		Where("isActive", "==", true).
+		Where("customerId", "in", []string{customerID, ""}).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return decodePriceRules(docs)
+}
+
+// FetchPriceRuleFromFirestore fetches a single price rule from firestore collection ('price_rules').
+func FetchPriceRuleFromFirestore(ctx context.Context, ruleID string) (*models.PriceRule, error) {
+	doc, err := firebase_shared.FirestoreClient.Collection(constants.PriceRulesCollection).Doc(ruleID).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := decodePriceRules([]*firestore.DocumentSnapshot{doc})
+	if err != nil {
+		return nil, err
+	}
+	return rules[0], nil
+}
+
+// CreatePriceRuleInFirestore validates and creates a new price rule in firestore collection ('price_rules').
+//
+// Params:
+//   - ctx: context
+//   - rule: *models.PriceRule, the ID is set from the created document
+//
+// Returns:
+//   - error: error
+func CreatePriceRuleInFirestore(ctx context.Context, rule *models.PriceRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	docRef := firebase_shared.FirestoreClient.Collection(constants.PriceRulesCollection).NewDoc()
+	rule.ID = docRef.ID
+	_, err := docRef.Set(ctx, rule.ToMap())
+	return err
+}
+
+// UpdatePriceRuleInFirestore writes an updated price rule to firestore collection ('price_rules'). The typed
+// fields of the rule are written so every field keeps its firestore type, see models.PriceRule.WithDetails.
+//
+// Params:
+//   - ctx: context
+//   - rule: *models.PriceRule, the updated rule with the ID of its document
+//
+// Returns:
+//   - error: error
+func UpdatePriceRuleInFirestore(ctx context.Context, rule *models.PriceRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	data := rule.ToMap()
+	delete(data, "createdAt") //Keep the creation time of the rule
+	_, err := firebase_shared.FirestoreClient.Collection(constants.PriceRulesCollection).Doc(rule.ID).Set(ctx, data, firestore.MergeAll)
+	return err
+}
+
+func decodePriceRules(docs []*firestore.DocumentSnapshot) ([]*models.PriceRule, error) {
+	rules := make([]*models.PriceRule, len(docs))
+	for i, doc := range docs {
+		var rule models.PriceRule
+		if err := doc.DataTo(&rule); err != nil {
+			return nil, fmt.Errorf("error decoding price rule %s: %v", doc.Ref.ID, err)
+		}
+		rule.ID = doc.Ref.ID
+		rules[i] = &rule
+	}
+	return rules, nil
+}
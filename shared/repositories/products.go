@@ -2,7 +2,9 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
@@ -43,6 +45,9 @@ func FetchAllProductsByIDs(ctx context.Context, productIDs []string) (map[string
 }
 
 // FetchAllProductsFromFirestore fetches all products from firestore from collection ('products')
+//
+// Note: the access of the caller is not checked, a request of a scoped caller goes through
+// services.FetchProductsForCaller.
 func FetchAllProductsFromFirestore(ctx context.Context) ([]*models.Product, error) {
 
 	products, err := firebase_shared.FirestoreClient.Collection(constants.ProductsCollection).Where("isActive", "==", true).Documents(ctx).GetAll()
@@ -61,41 +66,176 @@ func FetchAllProductsFromFirestore(ctx context.Context) ([]*models.Product, erro
 	return productList, nil
 }
 
+// FetchProductsByBrandsFromFirestore fetches the active products of the brands from firestore collection
+// ('products'), e.g. the brands assigned to a user.
+//
+// Params:
+//   - ctx: context
+//   - brands: []string, display names of the brands
+//
+// Returns:
+//   - []*models.Product
+//   - error
+//
+// Note: the query requires a composite index on ('isActive', 'brand').
+func FetchProductsByBrandsFromFirestore(ctx context.Context, brands []string) ([]*models.Product, error) {
+	productList := make([]*models.Product, 0)
+	//Firestore allows at most 30 values in an 'in' filter
+	for start := 0; start < len(brands); start += 30 {
+		end := min(start+30, len(brands))
+		products, err := firebase_shared.FirestoreClient.Collection(constants.ProductsCollection).
+			Where("isActive", "==", true).
+			Where("brand", "in", brands[start:end]).
+			Documents(ctx).GetAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range products {
+			var item models.Product
+			if err := product.DataTo(&item); err != nil {
+				return nil, fmt.Errorf("error decoding product %s: %v", product.Ref.ID, err)
+			}
+			productList = append(productList, &item)
+		}
+	}
+	return productList, nil
+}
+
 // SyncQuickbookProductRespToFirestore syncs quickbook product response to firestore
 // collection ('products')
 //
+// The structured attributes of the products come from their override document in the collection
+// ('product_attributes') first, then from quickbooks. The brands are matched against the collection ('brands'). A new product with invalid attributes is not created and
+// an existing one keeps its stored attributes while its other fields are still synced. Every issue is kept in
+// the sync report. The report of a full sync is always saved in the collection ('product_sync_reports'), the
+// report of a partial sync (e.g. a webhook) only when it has issues, rejections or failed writes.
+//
 // Params:
 //   - qbItemsResponse: *qbmodels.QBItemsResponse, a mapped response from quickbooks
 //   - ctx: context
+//   - fullSync: whether the response holds all the products of quickbooks
 //
 // Returns:
+//   - *models.ProductSyncReport: the report, nil if there was nothing to sync. It is also returned with the
+//     error of failed writes, the created and updated counts only hold the successful writes.
 //   - error: error
-func SyncQuickbookProductRespToFirestore(qbItemsResponse *qbmodels.QBItemsResponse, ctx context.Context) error {
+func SyncQuickbookProductRespToFirestore(qbItemsResponse *qbmodels.QBItemsResponse, ctx context.Context, fullSync bool) (*models.ProductSyncReport, error) {
 	if qbItemsResponse == nil || qbItemsResponse.QueryResponse.Item == nil {
-		return nil
+		return nil, nil
+	}
+
+	productIDs := make([]string, len(qbItemsResponse.QueryResponse.Item))
+	for i, item := range qbItemsResponse.QueryResponse.Item {
+		productIDs[i] = item.ID
+	}
+	overrides, err := FetchProductAttributeOverridesFromFirestore(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	brands, err := FetchBrandCatalogFromFirestore(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	bulkWriter := firebase_shared.FirestoreClient.BulkWriter(ctx)
-	defer bulkWriter.End() 
 
+	report := models.NewProductSyncReport()
+	//All the jobs are checked once the writes are done, the product jobs are also kept by kind to count them
+	var jobs, createJobs, updateJobs bulkWriterJobs
 	for _, item := range qbItemsResponse.QueryResponse.Item {
 		docRef := firebase_shared.FirestoreClient.Collection(constants.ProductsCollection).Doc(item.ID)
+		updatedProduct, issues := item.MapToProduct(overrides[item.ID], brands)
+		report.AddIssues(issues)
+		hasErrors := models.HasProductSyncErrors(issues)
 		originalProduct, _ := FetchProductFromFirestore(ctx, item.ID)
 
 		if originalProduct == nil {
+			if hasErrors {
+				report.Rejected++
+				continue
+			}
 			// new product -> bulk create
-			bulkWriter.Set(docRef, item.MapToProduct().ToMap(), firestore.MergeAll)
+			if err := createJobs.add(bulkWriter.Set(docRef, updatedProduct.ToMap(), firestore.MergeAll)); err != nil {
+				bulkWriter.End()
+				return nil, err
+			}
 		} else {
+			if hasErrors {
+				models.CopyProductAttributes(updatedProduct, originalProduct)
+				report.KeptStored++
+			}
 			// existing product -> update only changed fields
-			updatedValues := models.GetUpdatedProductDetails(item.MapToProduct(), originalProduct)
+			updatedValues := models.GetUpdatedProductDetails(updatedProduct, originalProduct)
 			if updatedValues != nil {
-				bulkWriter.Set(docRef, updatedValues, firestore.MergeAll)
+				if err := updateJobs.add(bulkWriter.Set(docRef, updatedValues, firestore.MergeAll)); err != nil {
+					bulkWriter.End()
+					return nil, err
+				}
+			} else {
+				report.Unchanged++
+			}
+			// Keep the old list price in the price history
+			if priceChange := models.GetProductPriceChange(updatedProduct, originalProduct, constants.PriceChangeSourceQuickBooks); priceChange != nil {
+				if err := jobs.add(addPriceChangeToBulkWriter(bulkWriter, docRef, priceChange)); err != nil {
+					bulkWriter.End()
+					return nil, err
+				}
 			}
 		}
 	}
 
-	bulkWriter.Flush()
-	return nil
+	jobs = append(jobs, createJobs...)
+	jobs = append(jobs, updateJobs...)
+	writeErr := jobs.end(bulkWriter)
+	report.Created = createJobs.succeeded()
+	report.Updated = updateJobs.succeeded()
+	report.Failed = len(createJobs) + len(updateJobs) - report.Created - report.Updated
+	report.FinishedAt = time.Now().UTC()
+
+	if report.ShouldSave(fullSync) {
+		reportRef := firebase_shared.FirestoreClient.Collection(constants.ProductSyncReportsCollection).NewDoc()
+		report.ID = reportRef.ID
+		if _, err := reportRef.Create(ctx, report); err != nil {
+			return nil, errors.Join(writeErr, err)
+		}
+	}
+	return report, writeErr
+}
+
+// FetchProductAttributeOverridesFromFirestore fetches the override documents of the structured attributes of
+// the products from the collection ('product_attributes').
+//
+// Params:
+//   - ctx: context
+//   - productIDs: []string, product ids
+//
+// Returns:
+//   - map[string]*models.ProductAttributes: keyed by product id, products without an override are not in it
+//   - error
+func FetchProductAttributeOverridesFromFirestore(ctx context.Context, productIDs []string) (map[string]*models.ProductAttributes, error) {
+	docRefs := make([]*firestore.DocumentRef, len(productIDs))
+	for i, productID := range productIDs {
+		docRefs[i] = firebase_shared.FirestoreClient.Collection(constants.ProductAttributesCollection).Doc(productID)
+	}
+	docSnapshots, err := firebase_shared.FirestoreClient.GetAll(ctx, docRefs)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := make(map[string]*models.ProductAttributes)
+	for i, docSnapshot := range docSnapshots {
+		if !docSnapshot.Exists() {
+			continue
+		}
+		var attributes models.ProductAttributes
+		if err := docSnapshot.DataTo(&attributes); err != nil {
+			return nil, fmt.Errorf("error decoding product attributes %s: %v", productIDs[i], err)
+		}
+		attributes.ProductID = productIDs[i]
+		attributes.Source = constants.ProductAttributeSourceOverride
+		overrides[productIDs[i]] = &attributes
+	}
+	return overrides, nil
 }
 
 // FetchProductFromFirestore fetches a single product from firestore. 
@@ -106,6 +246,9 @@ func SyncQuickbookProductRespToFirestore(qbItemsResponse *qbmodels.QBItemsRespon
 //
 // Returns:
 //   - *models.Product, error
+//
+// Note: the access of the caller is not checked, a request of a scoped caller goes through
+// services.FetchProductForCaller.
 func FetchProductFromFirestore(ctx context.Context, productID string) (*models.Product, error) {
 	doc, err := firebase_shared.FirestoreClient.Collection(constants.ProductsCollection).Doc(productID).Get(ctx)
 	if err != nil {
@@ -132,6 +275,9 @@ func FetchProductFromFirestore(ctx context.Context, productID string) (*models.P
 // better to just pass the type any. In Addition to this, before calling this, make sure to always
 // and always double check if the key matches with the `firestore` key in the struct otherwise this
 // will create a new key with that value in document. 
+//
+// Note: the access of the caller is not checked, a request of a scoped caller goes through
+// services.UpdateProductForCaller.
 func UpdateProductInFirestore(ctx context.Context, productID string, details any) error {
 	_, err := firebase_shared.FirestoreClient.Collection(constants.ProductsCollection).Doc(productID).Set(ctx, details, firestore.MergeAll)
 	if err != nil {
@@ -152,4 +298,20 @@ func UpdateProductInFirestore(ctx context.Context, productID string, details any
 func CreateProductInFirestore(ctx context.Context, product *models.Product) error {
 	_, err := firebase_shared.FirestoreClient.Collection(constants.ProductsCollection).Doc(product.ID).Set(ctx, product.ToMap())
 	return err
-}
\ No newline at end of file
+}
+
+// DeactivateProductInFirestore marks a product as inactive in firestore collection ('products'). Products
+// are never deleted from firestore since existing orders still reference them.
+//
+// Params:
+//   - ctx: context
+//   - productID: id of the product to deactivate
+//
+// Returns:
+//   - error: error
+func DeactivateProductInFirestore(ctx context.Context, productID string) error {
+	return UpdateProductInFirestore(ctx, productID, map[string]any{
+		"isActive":  false,
+		"updatedAt": firestore.ServerTimestamp,
+	})
+}
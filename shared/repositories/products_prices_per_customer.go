@@ -2,93 +2,381 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"time"
 
+	"cloud.google.com/go/firestore"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
 	firebase_shared "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/firebase"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"google.golang.org/api/iterator"
 )
 
-func SaveProductsPricesPerCustomerToFirestore(ctx context.Context) error {
-	customers, err := FetchAllCustomersFromFirestore(ctx)
+// SaveProductsPricesPerCustomerToFirestore recomputes the price of every product for every customer. Prefer
+// RecomputeProductsPricesPerCustomer with the changed products or customers when they are known.
+//
+// Params:
+//   - ctx: context
+//   - source: what caused the recompute, constants.PriceChangeSourceQuickBooks or constants.PriceChangeSourceManual
+//
+// Returns:
+//   - error
+func SaveProductsPricesPerCustomerToFirestore(ctx context.Context, source string) error {
+	_, err := RecomputeProductsPricesPerCustomer(ctx, &models.PriceRecomputeOptions{Source: source})
+	return err
+}
+
+// RecomputeProductsPricesPerCustomer resolves the price of the products for the customers from the price
+// rules and saves the prices that changed to firestore collection ('products_prices_per_customer'). The saved
+// price is the price of a single unit today, quantity breaks are applied when the order is priced.
+//
+// Only the products and customers in the options are recomputed. Customers are streamed one at a time and
+// the existing prices are read per customer, so memory only grows with the number of products. Every changed
+// price is appended to the price history of the row with the old and the new price.
+//
+// A failure for one customer does not stop the others, all the errors are joined.
+//
+// Params:
+//   - ctx: context
+//   - options: *models.PriceRecomputeOptions, set DryRun to only report what would change and Rules to price
+//     with rules that are not stored yet
+//
+// Returns:
+//   - *models.PriceRecomputeReport: what changed, or would change in dry run mode
+//   - error: joined errors of all the failed customers and writes
+func RecomputeProductsPricesPerCustomer(ctx context.Context, options *models.PriceRecomputeOptions) (*models.PriceRecomputeReport, error) {
+	products, err := fetchProductsForPriceRecompute(ctx, options.ProductIDs)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	products, err := FetchAllProductsFromFirestore(ctx)
+	storedRules, err := FetchAllPriceRulesFromFirestore(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	productPricesPerCustomer, err := firebase_shared.FirestoreClient.Collection(constants.ProductsPricesPerCustCollection).Documents(ctx).GetAll()
-	if err != nil {
-		return err
+	rules := options.GetRules(storedRules)
+
+	report := models.NewPriceRecomputeReport(options.DryRun)
+	if len(products) == 0 {
+		return report, nil
 	}
 
-	exists := make(map[string]*models.ProductPricePerCustomer)
-	for _, doc := range productPricesPerCustomer {
-		var productPricePerCustomer models.ProductPricePerCustomer
-		doc.DataTo(&productPricePerCustomer)
-		key := fmt.Sprintf("%s|%s", productPricePerCustomer.ProductID, productPricePerCustomer.CustomerID)
-		exists[key] = &productPricePerCustomer
+	var bulkWriter *firestore.BulkWriter
+	if !options.DryRun {
+		bulkWriter = firebase_shared.FirestoreClient.BulkWriter(ctx)
+		defer bulkWriter.End()
 	}
 
-	counter := 0
-	bulkWriter := firebase_shared.FirestoreClient.BulkWriter(ctx)
+	now := time.Now().UTC()
+	var errs []error
+	err = forEachCustomerForPriceRecompute(ctx, options.CustomerIDs, func(customer *models.Customer) {
+		priceList := models.NewPriceList(customer.ID, products, getPriceRulesForCustomer(rules, customer.ID))
+		if err := recomputeCustomerPrices(ctx, priceList, options, now, report, bulkWriter); err != nil {
+			errs = append(errs, fmt.Errorf("customer %s: %w", customer.ID, err))
+		}
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
 
-	for _, product := range products {
-		for _, customer := range customers {
-			key := fmt.Sprintf("%s|%s", product.ID, customer.ID)
-			if originalProductPricePerCustomer, ok := exists[key]; ok {
-				//Only continue if the price is different
-				if originalProductPricePerCustomer.Price == product.Price {
-					continue
-				}
-			}
+	sort.Slice(report.Changes, func(i, j int) bool {
+		if report.Changes[i].CustomerID != report.Changes[j].CustomerID {
+			return report.Changes[i].CustomerID < report.Changes[j].CustomerID
+		}
+		return report.Changes[i].ProductID < report.Changes[j].ProductID
+	})
+	return report, errors.Join(errs...)
+}
+
+// recomputeCustomerPrices recomputes the prices of one customer and waits for its writes to finish.
+func recomputeCustomerPrices(ctx context.Context, priceList *models.PriceList, options *models.PriceRecomputeOptions, now time.Time, report *models.PriceRecomputeReport, bulkWriter *firestore.BulkWriter) error {
+	existing, errs := fetchExistingCustomerPrices(ctx, priceList.CustomerID, options.ProductIDs)
+	collection := firebase_shared.FirestoreClient.Collection(constants.ProductsPricesPerCustCollection)
+	jobs := make([]*firestore.BulkWriterJob, 0)
+	report.Customers++
+
+	for _, product := range priceList.Products {
+		key := fmt.Sprintf("%s|%s", product.ID, priceList.CustomerID)
+		original, ok := existing[key]
+		if ok && original == nil {
+			// The existing price could not be decoded, the error is already reported
+			continue
+		}
+		resolvedPrice, _ := priceList.Resolve(product.ID, 1, now)
+
+		priceChange, changed := report.AddPrice(product, priceList.CustomerID, original, resolvedPrice, options.Source)
+		if !changed || bulkWriter == nil {
+			continue
+		}
 
-			productToAdd := models.CreateProductPricePerCustomer(product, customer.ID).ToMap()
-			docRef := firebase_shared.FirestoreClient.Collection(constants.ProductsPricesPerCustCollection).Doc(key)
-			_, err := bulkWriter.Set(docRef, productToAdd)
+		docRef := collection.Doc(key)
+		if priceChange != nil {
+			job, err := bulkWriter.Create(docRef.Collection(constants.PriceHistorySubcollection).NewDoc(), priceChange.ToMap())
 			if err != nil {
-				return err
+				errs = append(errs, err)
+				continue
 			}
+			jobs = append(jobs, job)
+		}
+		job, err := bulkWriter.Set(docRef, models.CreateProductPricePerCustomer(product, priceList.CustomerID, resolvedPrice).ToMap())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
 
-			counter++
-			if counter%500 == 0 {
-				bulkWriter.Flush()
-				bulkWriter = firebase_shared.FirestoreClient.BulkWriter(ctx)
+	if bulkWriter != nil && len(jobs) > 0 {
+		bulkWriter.Flush()
+		for _, job := range jobs {
+			if _, err := job.Results(); err != nil {
+				errs = append(errs, err)
 			}
 		}
 	}
-	// Flush remaining docs
-	if counter%500 != 0 {
-		bulkWriter.Flush()
+	return errors.Join(errs...)
+}
+
+// fetchExistingCustomerPrices fetches the existing per customer prices of a customer keyed by the document id.
+// The prices are read directly by their id when the products are known, otherwise all the prices of the
+// customer are streamed. A price that fails to decode is kept in the map as nil and its error is returned.
+func fetchExistingCustomerPrices(ctx context.Context, customerID string, productIDs []string) (map[string]*models.ProductPricePerCustomer, []error) {
+	existing := make(map[string]*models.ProductPricePerCustomer)
+	var errs []error
+
+	decode := func(doc *firestore.DocumentSnapshot) {
+		var productPricePerCustomer models.ProductPricePerCustomer
+		if err := doc.DataTo(&productPricePerCustomer); err != nil {
+			existing[doc.Ref.ID] = nil
+			errs = append(errs, fmt.Errorf("error decoding price %s: %v", doc.Ref.ID, err))
+			return
+		}
+		existing[doc.Ref.ID] = &productPricePerCustomer
+	}
+
+	collection := firebase_shared.FirestoreClient.Collection(constants.ProductsPricesPerCustCollection)
+	if len(productIDs) > 0 {
+		docRefs := make([]*firestore.DocumentRef, len(productIDs))
+		for i, productID := range productIDs {
+			docRefs[i] = collection.Doc(fmt.Sprintf("%s|%s", productID, customerID))
+		}
+		docs, err := firebase_shared.FirestoreClient.GetAll(ctx, docRefs)
+		if err != nil {
+			return existing, append(errs, err)
+		}
+		for _, doc := range docs {
+			if doc.Exists() {
+				decode(doc)
+			}
+		}
+		return existing, errs
+	}
+
+	docIterator := collection.Where("customerId", "==", customerID).Documents(ctx)
+	defer docIterator.Stop()
+	for {
+		doc, err := docIterator.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return existing, append(errs, err)
+		}
+		decode(doc)
+	}
+	return existing, errs
+}
+
+// fetchProductsForPriceRecompute fetches the active products to recompute, all of them if productIDs is empty.
+// Products that do not exist or are inactive are skipped.
+func fetchProductsForPriceRecompute(ctx context.Context, productIDs []string) ([]*models.Product, error) {
+	if len(productIDs) == 0 {
+		return FetchAllProductsFromFirestore(ctx)
+	}
+
+	docRefs := make([]*firestore.DocumentRef, len(productIDs))
+	for i, productID := range productIDs {
+		docRefs[i] = firebase_shared.FirestoreClient.Collection(constants.ProductsCollection).Doc(productID)
+	}
+	docs, err := firebase_shared.FirestoreClient.GetAll(ctx, docRefs)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	products := make([]*models.Product, 0, len(docs))
+	for _, doc := range docs {
+		if !doc.Exists() {
+			continue
+		}
+		var product models.Product
+		if err := doc.DataTo(&product); err != nil {
+			return nil, fmt.Errorf("error decoding product %s: %v", doc.Ref.ID, err)
+		}
+		if product.IsActive {
+			products = append(products, &product)
+		}
+	}
+	return products, nil
+}
+
+// forEachCustomerForPriceRecompute calls fn for every active customer to recompute, all of them if customerIDs
+// is empty. All the customers are streamed instead of being read into memory at once.
+func forEachCustomerForPriceRecompute(ctx context.Context, customerIDs []string, fn func(customer *models.Customer)) error {
+	collection := firebase_shared.FirestoreClient.Collection(constants.CustomersCollection)
+	var errs []error
+
+	if len(customerIDs) > 0 {
+		docRefs := make([]*firestore.DocumentRef, len(customerIDs))
+		for i, customerID := range customerIDs {
+			docRefs[i] = collection.Doc(customerID)
+		}
+		docs, err := firebase_shared.FirestoreClient.GetAll(ctx, docRefs)
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			if !doc.Exists() {
+				continue
+			}
+			var customer models.Customer
+			if err := doc.DataTo(&customer); err != nil {
+				errs = append(errs, fmt.Errorf("error decoding customer %s: %v", doc.Ref.ID, err))
+				continue
+			}
+			if customer.IsActive {
+				fn(&customer)
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	docIterator := collection.Where("isActive", "==", true).Documents(ctx)
+	defer docIterator.Stop()
+	for {
+		doc, err := docIterator.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		var customer models.Customer
+		if err := doc.DataTo(&customer); err != nil {
+			errs = append(errs, fmt.Errorf("error decoding customer %s: %v", doc.Ref.ID, err))
+			continue
+		}
+		fn(&customer)
+	}
+	return errors.Join(errs...)
+}
+
+// getPriceRulesForCustomer filters the rules set for the customer and the rules set for all customers.
+func getPriceRulesForCustomer(rules []*models.PriceRule, customerID string) []*models.PriceRule {
+	customerRules := make([]*models.PriceRule, 0)
+	for _, rule := range rules {
+		if rule.CustomerID == "" || rule.CustomerID == customerID {
+			customerRules = append(customerRules, rule)
+		}
+	}
+	return customerRules
 }
 
-// GetProductPricesFromCustomerID returns a map of product id to price.
-// Returning a map is done to avoid multiple calls to firestore and to search in O(1)
+// GetProductPricesFromCustomerID returns the price list of a customer: the active products with their list
+// prices and the price rules that apply to the customer. The price list is fetched once per order so every
+// item is resolved in memory, see models.PriceList.Resolve.
 //
 // Params:
 //   - customerID: ID of the customer
 //   - ctx: context
 //
 // Returns:
-//   - map of product id to price
+//   - *models.PriceList
 //   - error
-func GetProductPricesFromCustomerID(customerID string, ctx context.Context) (map[string]float64, error) {
-
-	docs, err := firebase_shared.FirestoreClient.Collection(constants.ProductsPricesPerCustCollection).Where("customerId", "==", customerID).Documents(ctx).GetAll()
+//
+// Note: the access of the caller is not checked, a request of a scoped caller goes through
+// services.FetchPriceListForCaller.
+func GetProductPricesFromCustomerID(customerID string, ctx context.Context) (*models.PriceList, error) {
+	products, err := FetchAllProductsFromFirestore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := FetchPriceRulesForCustomerFromFirestore(ctx, customerID)
 	if err != nil {
 		return nil, err
 	}
-	pricesMap := make(map[string]float64) //Map of product id to price
+	return models.NewPriceList(customerID, products, rules), nil
+}
+
+// ReassignProductPricesCustomerInFirestore moves the per customer prices of a customer to another customer.
+// Used when customers are merged in quickbooks. If the surviving customer already has a price for a product,
+// its own price is kept and the merged customer's price is dropped.
+//
+// Params:
+//   - ctx: context
+//   - fromCustomerID: id of the merged customer
+//   - toCustomerID: id of the surviving customer
+//
+// Returns:
+//   - int: number of prices moved or removed
+//   - error
+func ReassignProductPricesCustomerInFirestore(ctx context.Context, fromCustomerID, toCustomerID string) (int, error) {
+	collection := firebase_shared.FirestoreClient.Collection(constants.ProductsPricesPerCustCollection)
+
+	fromDocs, err := collection.Where("customerId", "==", fromCustomerID).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	toDocs, err := collection.Where("customerId", "==", toCustomerID).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	existing := make(map[string]struct{})
+	for _, doc := range toDocs {
+		existing[doc.Ref.ID] = struct{}{}
+	}
+
+	bulkWriter := firebase_shared.FirestoreClient.BulkWriter(ctx)
+	defer bulkWriter.End()
+
+	var jobs bulkWriterJobs
+	for _, doc := range fromDocs {
+		key, data := models.ReassignProductPricePerCustomer(doc.Data(), toCustomerID)
+		if _, ok := existing[key]; !ok {
+			if err := jobs.add(bulkWriter.Set(collection.Doc(key), data)); err != nil {
+				return 0, err
+			}
+		}
+		if err := jobs.add(bulkWriter.Delete(doc.Ref)); err != nil {
+			return 0, err
+		}
+	}
+	if err := jobs.end(bulkWriter); err != nil {
+		return 0, err
+	}
+	return len(fromDocs), nil
+}
+
+// DeleteProductPricesInFirestore deletes all the per customer prices where the field equals the value.
+// Used to clean up the stale prices of a deleted customer ("customerId") or product ("productId").
+//
+// Returns the number of prices deleted.
+func DeleteProductPricesInFirestore(ctx context.Context, field, value string) (int, error) {
+	docs, err := firebase_shared.FirestoreClient.Collection(constants.ProductsPricesPerCustCollection).Where(field, "==", value).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+
+	bulkWriter := firebase_shared.FirestoreClient.BulkWriter(ctx)
+	defer bulkWriter.End()
+
+	var jobs bulkWriterJobs
 	for _, doc := range docs {
-		var product models.ProductPricePerCustomer
-		err := doc.DataTo(&product)
-		if err != nil {
-			return nil, err
+		if err := jobs.add(bulkWriter.Delete(doc.Ref)); err != nil {
+			return 0, err
 		}
-		pricesMap[product.ProductID] = product.Price
 	}
-	return pricesMap, nil
+	if err := jobs.end(bulkWriter); err != nil {
+		return 0, err
+	}
+	return len(docs), nil
 }
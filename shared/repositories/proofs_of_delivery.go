@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	firebase_shared "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/firebase"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+// CreateProofOfDeliveryInFirestore saves the proof of delivery of an order in the firestore collection
+// ('proofs_of_delivery'), using the order id as the document id. A second delivery of the same order
+// replaces the first one.
+//
+// Params:
+//   - ctx: context
+//   - proof: the proof of delivery to save
+//
+// Returns:
+//   - error: if the write fails
+func CreateProofOfDeliveryInFirestore(ctx context.Context, proof *models.ProofOfDelivery) error {
+	_, err := firebase_shared.FirestoreClient.Collection(constants.ProofsOfDeliveryCollection).Doc(proof.OrderID).Set(ctx, proof)
+	return err
+}
+
+// FetchProofOfDeliveryFromFirestore fetches the proof of delivery of an order.
+//
+// Params:
+//   - ctx: context
+//   - orderID: id of the delivered order
+//
+// Returns:
+//   - *models.ProofOfDelivery
+//   - error: if the order has no proof of delivery or the read fails
+func FetchProofOfDeliveryFromFirestore(ctx context.Context, orderID string) (*models.ProofOfDelivery, error) {
+	doc, err := firebase_shared.FirestoreClient.Collection(constants.ProofsOfDeliveryCollection).Doc(orderID).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var proof models.ProofOfDelivery
+	if err := doc.DataTo(&proof); err != nil {
+		return nil, fmt.Errorf("failed to decode the proof of delivery of order %s: %w", orderID, err)
+	}
+	return &proof, nil
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+
+	"cloud.google.com/go/firestore"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	firebase_shared "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/firebase"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/quickbooks/qbmodels"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpdateProcessedWebhookEntity updates the processed record of a webhook entity in firestore collection
+// ('quickbooks_webhook_entities') in a transaction, so the record is checked and written atomically even
+// when quickbooks delivers the same notification to two requests at once.
+//
+// Params:
+//   - ctx: context
+//   - key: idempotency key of the entity (see qbmodels.Entity.GetKey)
+//   - update: returns the new record from the stored one, the stored record is nil if the entity was never
+//     processed. A nil record leaves it unchanged. It can be called again if the transaction is retried.
+//
+// Returns:
+//   - error: error returned by update or the transaction
+func UpdateProcessedWebhookEntity(ctx context.Context, key string, update func(processed *qbmodels.ProcessedEntity) (*qbmodels.ProcessedEntity, error)) error {
+	docRef := firebase_shared.FirestoreClient.Collection(constants.QuickBooksWebhookEntityCollection).Doc(key)
+
+	return firebase_shared.FirestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docSnapshot, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		var processed *qbmodels.ProcessedEntity
+		if docSnapshot != nil && docSnapshot.Exists() {
+			processed = &qbmodels.ProcessedEntity{}
+			if err := docSnapshot.DataTo(processed); err != nil {
+				return err
+			}
+		}
+		updated, err := update(processed)
+		if err != nil || updated == nil {
+			return err
+		}
+		return tx.Set(docRef, updated.ToMap())
+	})
+}
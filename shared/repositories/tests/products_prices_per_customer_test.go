@@ -4,12 +4,13 @@ import (
 	"context"
 	"testing"
 
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/repositories"
 )
 
 func TestProductsPricesPerCustomer(t *testing.T) {
 	
-	err := repositories.SaveProductsPricesPerCustomerToFirestore(context.Background())
+	err := repositories.SaveProductsPricesPerCustomerToFirestore(context.Background(), constants.PriceChangeSourceManual)
 	if err != nil {
 		t.Error(err)
 	}
@@ -17,9 +18,9 @@ func TestProductsPricesPerCustomer(t *testing.T) {
 
 func TestGetProductPricesFromCustomerID(t *testing.T) {
 	
-	pricesMap, err := repositories.GetProductPricesFromCustomerID("1",context.Background())
+	priceList, err := repositories.GetProductPricesFromCustomerID("1",context.Background())
 	if err != nil {
 		t.Error(err)
 	}
-	t.Log("Fetched Map is", pricesMap)
+	t.Log("Fetched price list is", priceList)
 }
\ No newline at end of file
@@ -35,7 +35,7 @@ func TestSyncProductsToFirestore(t *testing.T) {
 		t.Error(err)
 	}
 
-	err = repositories.SyncQuickbookProductRespToFirestore(&qbItemResponse, context.Background())
+	_, err = repositories.SyncQuickbookProductRespToFirestore(&qbItemResponse, context.Background(), true)
 
 	if err != nil {
 		t.Error(err)
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 
+	"cloud.google.com/go/firestore"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
 	firebase_shared "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/firebase"
 	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
@@ -28,3 +29,98 @@ func FetchAssignedAdminsForCustomer(ctx context.Context, customerID string) ([]*
 	}
 	return userAccounts, nil
 }
+
+// ReassignUsersCustomerInFirestore replaces a customer id with another in the `customers` list of every
+// user it is assigned to. Used when customers are merged in quickbooks.
+//
+// Returns the number of users updated.
+func ReassignUsersCustomerInFirestore(ctx context.Context, fromCustomerID, toCustomerID string) (int, error) {
+	docSnapshots, err := firebase_shared.FirestoreClient.Collection(constants.UsersCollection).
+		Where("customers", "array-contains", fromCustomerID).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, docSnapshot := range docSnapshots {
+		var userAccount models.UserAccount
+		if err := docSnapshot.DataTo(&userAccount); err != nil {
+			return 0, err
+		}
+		customers := userAccount.ReplaceCustomer(fromCustomerID, toCustomerID)
+		_, err := docSnapshot.Ref.Update(ctx, []firestore.Update{{Path: "customers", Value: customers}})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(docSnapshots), nil
+}
+
+// FetchUserAccountFromFirestore fetches the account of a user from the firestore collection ('users').
+//
+// Params:
+//   - ctx: context
+//   - uid: firebase uid of the user
+//
+// Returns:
+//   - *models.UserAccount
+//   - error: if the user does not exist or the read fails
+func FetchUserAccountFromFirestore(ctx context.Context, uid string) (*models.UserAccount, error) {
+	docSnapshot, err := firebase_shared.FirestoreClient.Collection(constants.UsersCollection).Doc(uid).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var userAccount models.UserAccount
+	if err := docSnapshot.DataTo(&userAccount); err != nil {
+		return nil, err
+	}
+	return &userAccount, nil
+}
+
+// UpdateUserPermissionsInFirestore replaces the permission overrides of a user in the firestore collection
+// ('users'). The custom claims of the user must be synced after, see SetUserClaimsInFirebase.
+//
+// Params:
+//   - ctx: context
+//   - uid: firebase uid of the user
+//   - granted: permissions added to the role
+//   - revoked: permissions removed from the role
+//
+// Returns:
+//   - error: if the user does not exist or the update fails
+func UpdateUserPermissionsInFirestore(ctx context.Context, uid string, granted, revoked []string) error {
+	_, err := firebase_shared.FirestoreClient.Collection(constants.UsersCollection).Doc(uid).Update(ctx, []firestore.Update{
+		{Path: "grantedPermissions", Value: granted},
+		{Path: "revokedPermissions", Value: revoked},
+	})
+	return err
+}
+
+// SetUserClaimsInFirebase sets the role and the permissions of the account as the custom claims of the
+// firebase user. The other claims of the user are kept, see models.UserAccount.MergeClaims. The claims reach
+// the user on their next token refresh.
+//
+// Params:
+//   - ctx: context
+//   - uid: firebase uid of the user
+//   - userAccount: the stored account of the user
+//
+// Returns:
+//   - error: if the claims could not be set
+func SetUserClaimsInFirebase(ctx context.Context, uid string, userAccount *models.UserAccount) error {
+	userRecord, err := firebase_shared.AuthClient.GetUser(ctx, uid)
+	if err != nil {
+		return err
+	}
+	return firebase_shared.AuthClient.SetCustomUserClaims(ctx, uid, userAccount.MergeClaims(userRecord.CustomClaims))
+}
+
+// RemoveUserClaimsInFirebase removes the role and the permissions claims of the firebase user, the other
+// claims are kept. Used to undo SetUserClaimsInFirebase when the account could not be created.
+func RemoveUserClaimsInFirebase(ctx context.Context, uid string) error {
+	userRecord, err := firebase_shared.AuthClient.GetUser(ctx, uid)
+	if err != nil {
+		return err
+	}
+	return firebase_shared.AuthClient.SetCustomUserClaims(ctx, uid, models.RemoveAccountClaims(userRecord.CustomClaims))
+}
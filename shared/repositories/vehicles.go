@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	firebase_shared "github.com/HarshMohanSason/AHSChemicalsGCShared/shared/firebase"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+)
+
+// FetchVehicleFromFirestore fetches a vehicle from the firestore collection ('vehicles').
+//
+// Params:
+//   - ctx: context
+//   - vehicleID: id of the vehicle
+//
+// Returns:
+//   - *models.Vehicle
+//   - error: if the vehicle does not exist or the read fails
+func FetchVehicleFromFirestore(ctx context.Context, vehicleID string) (*models.Vehicle, error) {
+	doc, err := firebase_shared.FirestoreClient.Collection(constants.VehiclesCollection).Doc(vehicleID).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var vehicle models.Vehicle
+	if err := doc.DataTo(&vehicle); err != nil {
+		return nil, fmt.Errorf("failed to decode the vehicle %s: %w", vehicleID, err)
+	}
+	return &vehicle, nil
+}
+
+// FetchActiveVehiclesFromFirestore fetches the vehicles in service, e.g. to pick the vehicle of a route.
+//
+// Params:
+//   - ctx: context
+//
+// Returns:
+//   - []*models.Vehicle
+//   - error
+func FetchActiveVehiclesFromFirestore(ctx context.Context) ([]*models.Vehicle, error) {
+	docs, err := firebase_shared.FirestoreClient.Collection(constants.VehiclesCollection).
+		Where("isActive", "==", true).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	vehicles := make([]*models.Vehicle, 0, len(docs))
+	for _, doc := range docs {
+		var vehicle models.Vehicle
+		if err := doc.DataTo(&vehicle); err != nil {
+			return nil, fmt.Errorf("failed to decode the vehicle %s: %w", doc.Ref.ID, err)
+		}
+		vehicles = append(vehicles, &vehicle)
+	}
+	return vehicles, nil
+}
@@ -0,0 +1,40 @@
+package create_email
+
+import (
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/send_email"
+)
+
+// CreatePriceChangeNoticeAdminEmail creates the notice sent to an assigned admin listing the price changes
+// of their customers since the last notice.
+//
+// Parameters:
+//   - admin: the admin receiving the notice
+//   - priceChanges: price changes of the admin's customers, oldest first
+//   - customerNames: map of customer id to customer name
+//
+// Returns:
+//   - *send_email.EmailMetaData
+func CreatePriceChangeNoticeAdminEmail(admin *models.UserAccount, priceChanges []*models.PriceChange, customerNames map[string]string) *send_email.EmailMetaData {
+	changes := make([]map[string]any, 0)
+	for _, priceChange := range priceChanges {
+		changes = append(changes, map[string]any{
+			"customer_name": customerNames[priceChange.CustomerID],
+			"product_name":  priceChange.ProductName,
+			"old_price":     priceChange.GetFormattedOldPrice(),
+			"new_price":     priceChange.GetFormattedNewPrice(),
+			"source":        priceChange.Source,
+			"changed_at":    priceChange.ChangedAt.Format("01/02/2006"),
+		})
+	}
+
+	emailData := &send_email.EmailMetaData{
+		Recipients: map[string]string{admin.Email: admin.Name},
+		Data: map[string]any{
+			"name":    admin.Name,
+			"changes": changes,
+		},
+		TemplateID: send_email.PRICE_CHANGE_NOTICE_ADMIN_TEMPLATE_ID,
+	}
+	return emailData
+}
@@ -29,3 +29,25 @@ func CreateDeleteUserAccountEmail(email, name string) *send_email.EmailMetaData
 	}
 	return emailData
 }
+
+// CreateUserInviteEmail creates the invitation sent to a new user with the link to set their password.
+//
+// Parameters:
+//   - invite: the pending invite
+//   - passwordSetupLink: the firebase link to set the password, see repositories.GeneratePasswordSetupLink
+//
+// Returns:
+//   - *send_email.EmailMetaData
+func CreateUserInviteEmail(invite *models.Invite, passwordSetupLink string) *send_email.EmailMetaData {
+	emailData := &send_email.EmailMetaData{
+		Recipients: map[string]string{invite.Email: invite.Name},
+		Data: map[string]any{
+			"name":       invite.Name,
+			"email":      invite.Email,
+			"link":       passwordSetupLink,
+			"expires_at": invite.ExpiresAt.Format("01/02/2006"),
+		},
+		TemplateID: send_email.USER_INVITE_TEMPLATE_ID,
+	}
+	return emailData
+}
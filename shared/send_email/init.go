@@ -14,6 +14,11 @@ import (
 var (
 	SENDGRID_API_KEY string
 	initSendGridOnce sync.Once
+
+	// Template IDs of the newer emails, loaded with the api key so each environment can use its own template.
+	// They are optional, the email that needs a missing template ID returns an error when it is sent.
+	PRICE_CHANGE_NOTICE_ADMIN_TEMPLATE_ID string
+	USER_INVITE_TEMPLATE_ID               string
 )
 
 // Send grid dynamic email template IDs.
@@ -38,6 +43,8 @@ const (
 func InitSendGridDebug() {
 	initSendGridOnce.Do(func() {
 		SENDGRID_API_KEY = os.Getenv("SENDGRID_API_KEY")
+		PRICE_CHANGE_NOTICE_ADMIN_TEMPLATE_ID = os.Getenv("PRICE_CHANGE_NOTICE_ADMIN_TEMPLATE_ID")
+		USER_INVITE_TEMPLATE_ID = os.Getenv("USER_INVITE_TEMPLATE_ID")
 		if SENDGRID_API_KEY == "" {
 			log.Fatal("SENDGRID_API_KEY is not set")
 		}
@@ -52,6 +59,8 @@ func InitSendGridFromSecrets(ctx context.Context) {
 			log.Fatalf("Error loading Google Cloud project ID: %v", err)
 		}
 		SENDGRID_API_KEY = gcp.LoadSecretsHelper(projectID, "SENDGRID_API_KEY")
+		PRICE_CHANGE_NOTICE_ADMIN_TEMPLATE_ID = gcp.LoadOptionalSecretHelper(projectID, "PRICE_CHANGE_NOTICE_ADMIN_TEMPLATE_ID")
+		USER_INVITE_TEMPLATE_ID = gcp.LoadOptionalSecretHelper(projectID, "USER_INVITE_TEMPLATE_ID")
 		if SENDGRID_API_KEY == "" {
 			log.Fatal("SENDGRID_API_KEY is not set")
 		}
@@ -0,0 +1,282 @@
+// Package services holds the business logic shared by the cloud functions. A request of a caller that is not
+// the super admin must go through the ForCaller functions of access.go, which check the customers and brands
+// assigned to the caller, since the repositories do not check any access.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/repositories"
+)
+
+// GetAccessScope builds the scope of the caller of a request from their account. The super admin does not
+// need an account since they can access everything.
+//
+// Parameters:
+//   - ctx: context for the firestore operations
+//   - uid: firebase uid of the caller
+//   - role: role of the caller from the custom claims of the token
+//
+// Returns:
+//   - *models.AccessScope: the customers and brands the caller can access
+//   - error: if the account of the caller could not be fetched
+func GetAccessScope(ctx context.Context, uid, role string) (*models.AccessScope, error) {
+	if role == constants.RoleSuperAdmin {
+		return models.NewAccessScope(uid, role, nil), nil
+	}
+	account, err := repositories.FetchUserAccountFromFirestore(ctx, uid)
+	if err != nil {
+		return nil, fmt.Errorf("Error while fetching your account, please try again: %s", err.Error())
+	}
+	return models.NewAccessScope(uid, role, account), nil
+}
+
+// FetchProductsForCaller returns the active products of the brands assigned to the caller.
+func FetchProductsForCaller(ctx context.Context, scope *models.AccessScope) ([]*models.Product, error) {
+	products, err := repositories.FetchAllProductsFromFirestore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return scope.FilterProducts(products), nil
+}
+
+// FetchProductForCaller returns a product if its brand is assigned to the caller.
+func FetchProductForCaller(ctx context.Context, scope *models.AccessScope, productID string) (*models.Product, error) {
+	product, err := repositories.FetchProductFromFirestore(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if err := scope.AuthorizeProduct(product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// UpdateProductForCaller updates a product if its brand, and the brand it is moved to, are assigned to the
+// caller.
+func UpdateProductForCaller(ctx context.Context, scope *models.AccessScope, productID string, details map[string]any) error {
+	if _, err := FetchProductForCaller(ctx, scope, productID); err != nil {
+		return err
+	}
+	if brand, ok := details[models.ProductAttributeBrand].(string); ok && !scope.CanAccessBrand(brand) {
+		return fmt.Errorf("%w, the brand %s is not assigned to your account", models.ErrAccessDenied, brand)
+	}
+	return repositories.UpdateProductInFirestore(ctx, productID, details)
+}
+
+// FetchPriceListForCaller returns the price list of a customer assigned to the caller with the products of
+// the brands assigned to the caller, see repositories.GetProductPricesFromCustomerID.
+func FetchPriceListForCaller(ctx context.Context, scope *models.AccessScope, customerID string) (*models.PriceList, error) {
+	if err := scope.AuthorizeCustomer(customerID); err != nil {
+		return nil, err
+	}
+	priceList, err := repositories.GetProductPricesFromCustomerID(customerID, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return scope.FilterPriceList(priceList), nil
+}
+
+// FetchCustomersForCaller returns the active customers assigned to the caller.
+func FetchCustomersForCaller(ctx context.Context, scope *models.AccessScope) ([]*models.Customer, error) {
+	customers, err := repositories.FetchAllCustomersFromFirestore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return scope.FilterCustomers(customers), nil
+}
+
+// FetchCustomerForCaller returns a customer assigned to the caller.
+func FetchCustomerForCaller(ctx context.Context, scope *models.AccessScope, customerID string) (*models.Customer, error) {
+	if err := scope.AuthorizeCustomer(customerID); err != nil {
+		return nil, err
+	}
+	return repositories.FetchCustomerFromFirestore(customerID, ctx)
+}
+
+// UpdateCustomerForCaller updates a customer assigned to the caller.
+func UpdateCustomerForCaller(ctx context.Context, scope *models.AccessScope, customerID string, details map[string]any) error {
+	if err := scope.AuthorizeCustomer(customerID); err != nil {
+		return err
+	}
+	return repositories.UpdateCustomerInFirestore(ctx, customerID, details)
+}
+
+// FetchOrderForCaller returns the detailed order if its customer and the brands of its items are assigned to
+// the caller.
+func FetchOrderForCaller(ctx context.Context, scope *models.AccessScope, orderID string) (*models.Order, error) {
+	order, err := repositories.FetchDetailedOrderFromFirestore(orderID, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := scope.AuthorizeOrder(order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// FetchOrdersForCaller returns the detailed orders the caller can access, see
+// repositories.FetchDetailedOrdersByStatusFromFirestore.
+func FetchOrdersForCaller(ctx context.Context, scope *models.AccessScope, statuses []string, from, to time.Time) ([]*models.Order, error) {
+	orders, err := repositories.FetchDetailedOrdersByStatusFromFirestore(ctx, statuses, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return scope.FilterOrders(orders), nil
+}
+
+// CreateOrderForCaller creates an order placed by the caller. The brands are checked against the stored
+// products and the items are priced from the price list of the customer since the items sent by the caller
+// cannot be trusted.
+//
+// Parameters:
+//   - ctx: context for the firestore operations
+//   - scope: scope of the caller
+//   - order: the complete order, see models.Order.CreateCompleteOrder
+//
+// Returns:
+//   - error: wrapping models.ErrAccessDenied if the customer or the brand of an item is not assigned to the
+//     caller, or if the order could not be created
+func CreateOrderForCaller(ctx context.Context, scope *models.AccessScope, order *models.Order) error {
+	if order.Customer == nil {
+		return errors.New("Customer of the order cannot be empty")
+	}
+	if err := scope.AuthorizeCustomer(order.Customer.ID); err != nil {
+		return err
+	}
+	products, err := repositories.FetchAllProductsByIDs(ctx, order.ToProductIDs())
+	if err != nil {
+		return err
+	}
+	for _, item := range order.Items {
+		product, ok := products[item.ID]
+		if !ok {
+			return fmt.Errorf("Product %s of the order does not exist", item.ID)
+		}
+		if err := scope.AuthorizeProduct(product); err != nil {
+			return err
+		}
+	}
+	priceList, err := repositories.GetProductPricesFromCustomerID(order.Customer.ID, ctx)
+	if err != nil {
+		return err
+	}
+	if err := order.SetItemPrices(priceList); err != nil {
+		return err
+	}
+	order.UpdateOrderBill()
+	return repositories.CreateOrderInFirestore(order, ctx)
+}
+
+// UpdateOrderForCaller updates an order the caller can access, see FetchOrderForCaller. A scoped caller can
+// only update the customer, the items and the special instructions, a new customer and the brands of new
+// items must also be assigned to the caller, see models.AccessScope.AuthorizeOrderUpdates. When the customer
+// or the items change, the items are priced again from the price list of the customer and the bill is
+// updated, the prices sent by the caller are ignored.
+func UpdateOrderForCaller(ctx context.Context, scope *models.AccessScope, orderID string, updates []firestore.Update) error {
+	order, err := FetchOrderForCaller(ctx, scope, orderID)
+	if err != nil {
+		return err
+	}
+	productIDs, err := models.GetOrderUpdateProductIDs(updates)
+	if err != nil {
+		return err
+	}
+	products := make(map[string]*models.Product)
+	if len(productIDs) > 0 {
+		if products, err = repositories.FetchAllProductsByIDs(ctx, productIDs); err != nil {
+			return err
+		}
+	}
+	if err := scope.AuthorizeOrderUpdates(updates, products); err != nil {
+		return err
+	}
+	if !scope.IsSuperAdmin() {
+		if updates, err = getPricedOrderUpdates(ctx, order, updates, products); err != nil {
+			return err
+		}
+	}
+	return repositories.UpdateOrderInFirestore(ctx, orderID, updates)
+}
+
+// getPricedOrderUpdates prices the items of the order again when the updates change its customer or items,
+// and replaces the items of the updates with the priced ones followed by the updated bill.
+func getPricedOrderUpdates(ctx context.Context, order *models.Order, updates []firestore.Update, products map[string]*models.Product) ([]firestore.Update, error) {
+	items, err := models.GetOrderUpdateItems(updates)
+	if err != nil {
+		return nil, err
+	}
+	customerID := order.Customer.ID
+	pricedUpdates := make([]firestore.Update, 0, len(updates)+5)
+	for _, update := range updates {
+		switch models.GetOrderUpdatePath(update) {
+		case "items":
+			continue
+		case "customerId":
+			customerID = update.Value.(string)
+		}
+		pricedUpdates = append(pricedUpdates, update)
+	}
+	if items == nil && customerID == order.Customer.ID {
+		return updates, nil
+	}
+
+	if customerID != order.Customer.ID {
+		customer, err := repositories.FetchCustomerFromFirestore(customerID, ctx)
+		if err != nil {
+			return nil, err
+		}
+		order.SetCustomer(customer)
+		pricedUpdates = append(pricedUpdates, firestore.Update{Path: "customerName", Value: strings.ToLower(customer.Name)})
+	}
+	if items != nil {
+		order.Items = items
+		order.ToCompleteOrderItemsFromMinimal(products)
+	}
+	priceList, err := repositories.GetProductPricesFromCustomerID(customerID, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := order.SetItemPrices(priceList); err != nil {
+		return nil, err
+	}
+	order.UpdateOrderBill()
+	return append(pricedUpdates,
+		firestore.Update{Path: "items", Value: order.ToMapItems()},
+		firestore.Update{Path: "subTotal", Value: order.SubTotal},
+		firestore.Update{Path: "taxAmount", Value: order.TaxAmount},
+		firestore.Update{Path: "total", Value: order.Total},
+		firestore.Update{Path: "updatedAt", Value: time.Now().UTC()},
+	), nil
+}
+
+// authorizePriceRule checks the rule, the product of a contract and the products of a category discount are
+// assigned to the caller.
+func authorizePriceRule(ctx context.Context, scope *models.AccessScope, rule *models.PriceRule) error {
+	if err := scope.AuthorizePriceRule(rule); err != nil {
+		return err
+	}
+	if scope.IsSuperAdmin() {
+		return nil
+	}
+	if rule.ProductID != "" {
+		if _, err := FetchProductForCaller(ctx, scope, rule.ProductID); err != nil {
+			return err
+		}
+	}
+	if rule.Type == constants.PriceRuleTypeCategoryDiscount {
+		products, err := repositories.FetchAllProductsFromFirestore(ctx)
+		if err != nil {
+			return err
+		}
+		return scope.AuthorizeCategory(rule.Category, products)
+	}
+	return nil
+}
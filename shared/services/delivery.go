@@ -49,6 +49,12 @@ func NewDelivery(deliveryInput *models.DeliveryInput, ctx context.Context) (*mod
 		return nil, err
 	}
 
+	//Deliveries of scheduled orders are credited to the driver of the route when the app did not send one
+	driverUID := deliveryInput.DriverUID
+	if driverUID == "" && order.Schedule != nil {
+		driverUID = order.Schedule.DriverUID
+	}
+
 	return &models.Delivery{
 		Order:          order,
 		DeliveredBy:    deliveryInput.DeliveredBy,
@@ -56,5 +62,46 @@ func NewDelivery(deliveryInput *models.DeliveryInput, ctx context.Context) (*mod
 		Signature:      signatureBytes,
 		DeliveryImages: deliveryImagesBytes,
 		DeliveredAt:    time.Now().UTC(),
+		DriverUID:      driverUID,
+		Location:       deliveryInput.Location,
+		CapturedAt:     deliveryInput.CapturedAt.UTC(),
 	}, nil
 }
+
+// StoreDeliveryImages uploads the full resolution originals of the delivery images under the order prefix in
+// Cloud Storage and sets their links on the delivery, so the shipping manifest only embeds thumbnails.
+//
+// Parameters:
+//   - ctx: context for the upload
+//   - delivery: the delivery whose images are uploaded
+//
+// Returns:
+//   - error: if any image fails to upload
+func StoreDeliveryImages(ctx context.Context, delivery *models.Delivery) error {
+	urls, err := repositories.UploadDeliveryImagesToStorage(ctx, delivery.Order.ID, delivery.DeliveryImages)
+	if err != nil {
+		return fmt.Errorf("Error while storing the delivery images, please try again: %s", err.Error())
+	}
+	delivery.ImageURLs = urls
+	return nil
+}
+
+// RecordProofOfDelivery builds the proof of delivery of a delivery, checks its capture location against the
+// customer address, saves it and sets it on the delivery so the shipping manifest shows the result.
+//
+// Parameters:
+//   - ctx: context for the firestore write
+//   - delivery: the delivery, call StoreDeliveryImages first so the photos link to their originals
+//   - radiusMeters: max distance allowed from the customer address, e.g. constants.DefaultDeliveryRadiusMeters
+//
+// Returns:
+//   - *models.ProofOfDelivery: the saved proof of delivery
+//   - error: if the proof of delivery can not be saved
+func RecordProofOfDelivery(ctx context.Context, delivery *models.Delivery, radiusMeters float64) (*models.ProofOfDelivery, error) {
+	proof := models.NewProofOfDelivery(delivery, radiusMeters)
+	if err := repositories.CreateProofOfDeliveryInFirestore(ctx, proof); err != nil {
+		return nil, fmt.Errorf("Error while saving the proof of delivery, please try again: %s", err.Error())
+	}
+	delivery.Proof = proof
+	return proof, nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/repositories"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/utils"
+)
+
+// PlanDeliveryRoute assigns approved orders to a driver, a vehicle and a delivery date, orders them into a
+// route and saves it. The orders are checked with the other routes of the vehicle on the day against the
+// limits of the vehicle, the warnings of the check are kept on the route.
+//
+// Parameters:
+//   - ctx: context for the firestore operations
+//   - driverUID: uid of the driver, a user with the driver or an admin role
+//   - vehicleID: id of the vehicle used for the route
+//   - deliveryDate: the day of the deliveries
+//   - depot: where the route starts, nil to start at the first stop
+//   - orderIDs: the approved orders to deliver
+//
+// Returns:
+//   - *models.DeliveryRoute: the saved route with its detailed orders
+//   - error: if the driver can not deliver, an order can not be scheduled, the vehicle can not take the load
+//     or the route can not be saved
+func PlanDeliveryRoute(ctx context.Context, driverUID, vehicleID string, deliveryDate time.Time, depot *models.GeoLocation, orderIDs []string) (*models.DeliveryRoute, error) {
+	driver, err := repositories.FetchUserAccountFromFirestore(ctx, driverUID)
+	if err != nil {
+		return nil, fmt.Errorf("Error while fetching the driver, please try again: %s", err.Error())
+	}
+	if driver.Role != constants.RoleDriver && driver.Role != constants.RoleAdmin && driver.Role != constants.RoleSuperAdmin {
+		return nil, fmt.Errorf("%s can not be assigned deliveries", driver.Name)
+	}
+	vehicle, err := repositories.FetchVehicleFromFirestore(ctx, vehicleID)
+	if err != nil {
+		return nil, fmt.Errorf("Error while fetching the vehicle, please try again: %s", err.Error())
+	}
+
+	orders := make([]*models.Order, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		order, err := repositories.FetchDetailedOrderFromFirestore(orderID, ctx)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	routeID, err := utils.GenerateRandomID(20)
+	if err != nil {
+		return nil, err
+	}
+	route, err := models.NewDeliveryRoute(routeID, driverUID, driver.Name, vehicle, deliveryDate, depot, orders)
+	if err != nil {
+		return nil, err
+	}
+
+	load, err := checkVehicleLoad(ctx, vehicle, route.DeliveryDate, orders)
+	if err != nil {
+		return nil, err
+	}
+	if err := load.Validate(); err != nil {
+		return nil, err
+	}
+	route.LoadWarnings = load.GetWarnings()
+	if err := repositories.CreateDeliveryRouteInFirestore(ctx, route); err != nil {
+		return nil, err
+	}
+	return route, nil
+}
+
+// CheckVehicleLoad checks the combined load of the routes of a vehicle on a day against the limits of the
+// vehicle.
+//
+// Parameters:
+//   - ctx: context for the firestore operations
+//   - vehicleID: id of the vehicle
+//   - deliveryDate: the day of the deliveries
+//
+// Returns:
+//   - *models.VehicleLoad: the load with the issues found
+//   - error: if the vehicle or its routes can not be fetched
+func CheckVehicleLoad(ctx context.Context, vehicleID string, deliveryDate time.Time) (*models.VehicleLoad, error) {
+	vehicle, err := repositories.FetchVehicleFromFirestore(ctx, vehicleID)
+	if err != nil {
+		return nil, fmt.Errorf("Error while fetching the vehicle, please try again: %s", err.Error())
+	}
+	return checkVehicleLoad(ctx, vehicle, deliveryDate, nil)
+}
+
+// checkVehicleLoad checks the orders already on the routes of the vehicle on the day with the new orders.
+//
+// Note: routes planned at the same time for the same vehicle are not seen by each other.
+func checkVehicleLoad(ctx context.Context, vehicle *models.Vehicle, deliveryDate time.Time, newOrders []*models.Order) (*models.VehicleLoad, error) {
+	routes, err := repositories.FetchDeliveryRoutesForVehicleFromFirestore(ctx, vehicle.ID, deliveryDate)
+	if err != nil {
+		return nil, fmt.Errorf("Error while fetching the routes of %s, please try again: %s", vehicle.Name, err.Error())
+	}
+	orders := make([]*models.Order, 0, len(newOrders))
+	for _, route := range routes {
+		orders = append(orders, route.GetOrders()...)
+	}
+	orders = append(orders, newOrders...)
+	return models.NewVehicleLoad(vehicle, deliveryDate, orders), nil
+}
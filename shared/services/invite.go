@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/compute/metadata"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/gcp"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/repositories"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/send_email"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/send_email/create_email"
+)
+
+var (
+	INVITE_TOKEN_SECRET string // Signs the invite tokens, see models.Invite.GetToken
+	INVITE_ACCEPT_URL   string // Page opened after the password is set, it accepts the invite with the token
+	initInvitesOnce     sync.Once
+)
+
+func InitInvitesDebug() {
+	initInvitesOnce.Do(func() {
+		INVITE_TOKEN_SECRET = os.Getenv("INVITE_TOKEN_SECRET")
+		INVITE_ACCEPT_URL = os.Getenv("INVITE_ACCEPT_URL")
+		if INVITE_TOKEN_SECRET == "" || INVITE_ACCEPT_URL == "" {
+			log.Fatal("INVITE_TOKEN_SECRET or INVITE_ACCEPT_URL is not set")
+		}
+		log.Println("Initialized invite credentials in debug mode")
+	})
+}
+
+func InitInvitesFromSecrets(ctx context.Context) {
+	initInvitesOnce.Do(func() {
+		projectID, err := metadata.ProjectIDWithContext(ctx)
+		if err != nil {
+			log.Fatalf("Error loading Google Cloud project ID: %v", err)
+		}
+		INVITE_TOKEN_SECRET = gcp.LoadSecretsHelper(projectID, "INVITE_TOKEN_SECRET")
+		INVITE_ACCEPT_URL = gcp.LoadSecretsHelper(projectID, "INVITE_ACCEPT_URL")
+		if INVITE_TOKEN_SECRET == "" || INVITE_ACCEPT_URL == "" {
+			log.Fatal("INVITE_TOKEN_SECRET or INVITE_ACCEPT_URL is not set")
+		}
+		log.Println("Initialized invite credentials in production mode")
+	})
+}
+
+// CreateInvite invites a new user: the firebase user is created without a password, the invite is stored
+// and the user is emailed the link to set their password.
+//
+// Parameters:
+//   - ctx: context for the firestore, auth and email operations
+//   - invite: the name, email, customers, brands, role and permission overrides of the new user
+//   - invitedBy: uid of the super admin sending the invite
+//
+// Returns:
+//   - *models.Invite: the stored invite
+//   - error: if the invite is not valid, the email is already used or the invite could not be sent
+func CreateInvite(ctx context.Context, invite *models.Invite, invitedBy string) (*models.Invite, error) {
+	invite.Email = strings.ToLower(strings.TrimSpace(invite.Email))
+	if err := invite.Validate(); err != nil {
+		return nil, err
+	}
+	brands, err := repositories.FetchBrandCatalogFromFirestore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Error while fetching the brands, please try again: %s", err.Error())
+	}
+	if err := invite.ValidateBrands(brands); err != nil {
+		return nil, err
+	}
+	pending, err := repositories.FetchPendingInviteForEmailFromFirestore(ctx, invite.Email)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, fmt.Errorf("%s already has a pending invite, resend it instead", invite.Email)
+	}
+
+	invite.UID, err = repositories.CreateInvitedUserInFirebase(ctx, invite)
+	if err != nil {
+		return nil, fmt.Errorf("Error while creating the user %s: %s", invite.Email, err.Error())
+	}
+	//The status fields are set here, not by the super admin
+	invite.InvitedBy = invitedBy
+	invite.SentCount = 0
+	invite.CreatedAt, invite.AcceptedAt = time.Time{}, time.Time{}
+	invite.SetPending(time.Now().UTC())
+	if err := repositories.CreateInviteInFirestore(ctx, invite); err != nil {
+		return nil, errors.Join(err, repositories.DeleteUserInFirebase(ctx, invite.UID))
+	}
+	if err := sendInviteEmail(ctx, invite); err != nil {
+		return nil, fmt.Errorf("Invite was created but the email could not be sent, please resend it: %s", err.Error())
+	}
+	return invite, nil
+}
+
+// ResendInvite emails a pending invite again with a new expiry, the links of the previous emails stop
+// working.
+func ResendInvite(ctx context.Context, inviteID string) (*models.Invite, error) {
+	invite, err := repositories.FetchInviteFromFirestore(ctx, inviteID)
+	if err != nil {
+		return nil, err
+	}
+	if err := invite.CanResend(); err != nil {
+		return nil, err
+	}
+	invite.SetPending(time.Now().UTC())
+	if err := repositories.UpdateInviteInFirestore(ctx, invite); err != nil {
+		return nil, err
+	}
+	if err := sendInviteEmail(ctx, invite); err != nil {
+		return nil, err
+	}
+	return invite, nil
+}
+
+// RevokeInvite revokes a pending invite and deletes its firebase user so the email can be invited again. The
+// user is deleted first so a failed revoke leaves the invite pending and can be retried.
+func RevokeInvite(ctx context.Context, inviteID string) error {
+	invite, err := repositories.FetchInviteFromFirestore(ctx, inviteID)
+	if err != nil {
+		return err
+	}
+	if err := invite.CanRevoke(); err != nil {
+		return err
+	}
+	if err := repositories.DeleteUserInFirebase(ctx, invite.UID); err != nil {
+		return fmt.Errorf("Error while deleting the user %s, please try again: %s", invite.Email, err.Error())
+	}
+	invite.Status = constants.InviteStatusRevoked
+	invite.UpdatedAt = time.Now().UTC()
+	return repositories.UpdateInviteInFirestore(ctx, invite)
+}
+
+// AcceptInvite is called when the invited user first signs in. It sets their custom claims and then creates
+// their account from the invite. Calling it again once the invite was accepted only syncs the claims, so a
+// user whose acceptance failed half way can retry it.
+//
+// Parameters:
+//   - ctx: context for the firestore and auth operations
+//   - uid: firebase uid of the signed in user
+//   - token: the token of the invite link
+//
+// Returns:
+//   - *models.UserAccount: the created account
+//   - error: if the token is not valid or the invite cannot be accepted
+func AcceptInvite(ctx context.Context, uid, token string) (*models.UserAccount, error) {
+	now := time.Now().UTC()
+	inviteID, expiresAt, err := models.ParseInviteToken(token, []byte(INVITE_TOKEN_SECRET), now)
+	if err != nil {
+		return nil, err
+	}
+	invite, err := repositories.FetchInviteFromFirestore(ctx, inviteID)
+	if err != nil {
+		return nil, err
+	}
+	if invite.Status == constants.InviteStatusAccepted && invite.UID == uid {
+		account, err := repositories.FetchUserAccountFromFirestore(ctx, uid)
+		if err != nil {
+			return nil, err
+		}
+		return account, repositories.SetUserClaimsInFirebase(ctx, uid, account)
+	}
+	if err := invite.CanAccept(uid, now); err != nil {
+		return nil, err
+	}
+	if !invite.MatchesToken(expiresAt) {
+		return nil, errors.New("Invite link was replaced by a newer one, please use the link of the latest email")
+	}
+
+	//The claims are set before the invite is accepted so an accepted invite always has them, the
+	//transaction checks the invite again and the claims are removed if it fails
+	if err := repositories.SetUserClaimsInFirebase(ctx, uid, invite.ToUserAccount()); err != nil {
+		return nil, fmt.Errorf("Error while setting up your account, please try again: %s", err.Error())
+	}
+	account, err := repositories.AcceptInviteInFirestore(ctx, inviteID, uid, expiresAt, now)
+	if err != nil {
+		//Keep the claims if the same user accepted the invite from another request in the meantime
+		if accepted, fetchErr := repositories.FetchInviteFromFirestore(ctx, inviteID); fetchErr == nil && accepted.Status == constants.InviteStatusAccepted && accepted.UID == uid {
+			return nil, err
+		}
+		return nil, errors.Join(err, repositories.RemoveUserClaimsInFirebase(ctx, uid))
+	}
+	return account, nil
+}
+
+// sendInviteEmail emails the invite with the firebase password setup link, the invite token is passed to the
+// accept page through the continue url.
+func sendInviteEmail(ctx context.Context, invite *models.Invite) error {
+	if send_email.USER_INVITE_TEMPLATE_ID == "" {
+		return errors.New("USER_INVITE_TEMPLATE_ID is not set, invites cannot be emailed")
+	}
+	continueURL, err := url.Parse(INVITE_ACCEPT_URL)
+	if err != nil {
+		return err
+	}
+	query := continueURL.Query()
+	query.Set("token", invite.GetToken([]byte(INVITE_TOKEN_SECRET)))
+	continueURL.RawQuery = query.Encode()
+
+	link, err := repositories.GeneratePasswordSetupLink(ctx, invite.Email, continueURL.String())
+	if err != nil {
+		return err
+	}
+	resp, err := send_email.SendMail(create_email.CreateUserInviteEmail(invite, link))
+	if err == nil && resp.StatusCode != http.StatusAccepted {
+		err = errors.New("Email not accepted: " + resp.Body)
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/repositories"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/send_email"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/send_email/create_email"
+)
+
+// priceChangeNotice collects the price changes of all the customers assigned to one admin.
+type priceChangeNotice struct {
+	admin         *models.UserAccount
+	priceChanges  []*models.PriceChange
+	latestChanges map[string]time.Time // Latest price change of each customer included in the notice
+}
+
+// SendPriceChangeNotices emails every assigned admin that opted in to price change notices
+// (UserAccount.PriceChangeNotices) the price changes of their customers since their last notice. Each admin
+// receives a single email for all of their customers.
+//
+// The last notice time is saved per admin and customer once the email of the admin was sent, so a failed
+// email is retried with the next run without the other admins receiving the same changes again.
+//
+// Returns:
+//   - error: joined errors of all the failed customers and emails, or an error if the template of the notice
+//     is not configured
+func SendPriceChangeNotices(ctx context.Context) error {
+	if send_email.PRICE_CHANGE_NOTICE_ADMIN_TEMPLATE_ID == "" {
+		return errors.New("PRICE_CHANGE_NOTICE_ADMIN_TEMPLATE_ID is not set, price change notices cannot be sent")
+	}
+	customers, err := repositories.FetchAllCustomersFromFirestore(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	notices := make(map[string]*priceChangeNotice) // Keyed by the admin email
+	customerNames := make(map[string]string)
+
+	for _, customer := range customers {
+		customerNames[customer.ID] = customer.Name
+
+		admins, err := repositories.FetchAssignedAdminsForCustomer(ctx, customer.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("customer %s: %w", customer.ID, err))
+			continue
+		}
+		//Last notice of each admin, the changes are fetched once from the oldest one
+		sinceByAdmin := make(map[string]time.Time)
+		var oldest time.Time
+		for _, admin := range admins {
+			if !admin.PriceChangeNotices {
+				continue
+			}
+			since, err := repositories.FetchLastPriceChangeNoticeTime(ctx, customer.ID, admin.Email)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("customer %s, admin %s: %w", customer.ID, admin.Email, err))
+				continue
+			}
+			if len(sinceByAdmin) == 0 || since.Before(oldest) {
+				oldest = since
+			}
+			sinceByAdmin[admin.Email] = since
+		}
+		if len(sinceByAdmin) == 0 {
+			continue
+		}
+		priceChanges, err := repositories.FetchPriceChangesForCustomerSince(ctx, customer.ID, oldest)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("customer %s: %w", customer.ID, err))
+			continue
+		}
+
+		for _, admin := range admins {
+			since, ok := sinceByAdmin[admin.Email]
+			if !ok {
+				continue
+			}
+			adminChanges := make([]*models.PriceChange, 0)
+			for _, priceChange := range priceChanges {
+				if priceChange.ChangedAt.After(since) {
+					adminChanges = append(adminChanges, priceChange)
+				}
+			}
+			if len(adminChanges) == 0 {
+				continue
+			}
+			notice, ok := notices[admin.Email]
+			if !ok {
+				notice = &priceChangeNotice{admin: admin, latestChanges: make(map[string]time.Time)}
+				notices[admin.Email] = notice
+			}
+			notice.priceChanges = append(notice.priceChanges, adminChanges...)
+			notice.latestChanges[customer.ID] = adminChanges[len(adminChanges)-1].ChangedAt
+		}
+	}
+
+	for _, notice := range notices {
+		email := create_email.CreatePriceChangeNoticeAdminEmail(notice.admin, notice.priceChanges, customerNames)
+		resp, err := send_email.SendMail(email)
+		if err == nil && resp.StatusCode != http.StatusAccepted {
+			err = errors.New("Email not accepted: " + resp.Body)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("price change notice to %s: %w", notice.admin.Email, err))
+			continue
+		}
+		for customerID, changedAt := range notice.latestChanges {
+			if err := repositories.SavePriceChangeNoticeTime(ctx, customerID, notice.admin.Email, changedAt); err != nil {
+				errs = append(errs, fmt.Errorf("customer %s, admin %s: %w", customerID, notice.admin.Email, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/repositories"
+)
+
+// CreatePriceRule creates a price rule and recomputes only the per customer prices it affects.
+//
+// Parameters:
+//   - ctx: context
+//   - scope: scope of the caller, see GetAccessScope
+//   - rule: the price rule to create
+//   - dryRun: only report the prices that would change, the rule is not created
+//
+// Returns:
+//   - *models.PriceRecomputeReport: the prices that changed, or would change
+//   - error: An error object if the rule is invalid or the recompute failed
+func CreatePriceRule(ctx context.Context, scope *models.AccessScope, rule *models.PriceRule, dryRun bool) (*models.PriceRecomputeReport, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	if err := authorizePriceRule(ctx, scope, rule); err != nil {
+		return nil, err
+	}
+	if !dryRun {
+		if err := repositories.CreatePriceRuleInFirestore(ctx, rule); err != nil {
+			return nil, err
+		}
+	}
+	options := models.NewPriceRecomputeOptionsForRules(constants.PriceChangeSourceManual, rule)
+	options.DryRun = dryRun
+	if dryRun {
+		//The rule is not stored so it is passed to the recompute
+		options.Rules = []*models.PriceRule{rule}
+	}
+	return repositories.RecomputeProductsPricesPerCustomer(ctx, options)
+}
+
+// UpdatePriceRule updates a price rule and recomputes the per customer prices affected by both the old and
+// the updated rule. Both the old and the updated rule must be in the scope of the caller.
+//
+// Parameters:
+//   - ctx: context
+//   - scope: scope of the caller, see GetAccessScope
+//   - ruleID: id of the rule
+//   - details: fields to update
+//
+// Returns:
+//   - *models.PriceRecomputeReport: the prices that changed
+//   - error: An error object if the updated rule is invalid or the update or the recompute failed
+func UpdatePriceRule(ctx context.Context, scope *models.AccessScope, ruleID string, details map[string]any) (*models.PriceRecomputeReport, error) {
+	oldRule, err := repositories.FetchPriceRuleFromFirestore(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizePriceRule(ctx, scope, oldRule); err != nil {
+		return nil, err
+	}
+	//Check what the updated rule applies to before writing it
+	updatedRule, err := oldRule.WithDetails(details)
+	if err != nil {
+		return nil, err
+	}
+	updatedRule.ID = oldRule.ID
+	if err := updatedRule.Validate(); err != nil {
+		return nil, err
+	}
+	if err := authorizePriceRule(ctx, scope, updatedRule); err != nil {
+		return nil, err
+	}
+	//The typed fields of the updated rule are written, not the details decoded from json
+	if err := repositories.UpdatePriceRuleInFirestore(ctx, updatedRule); err != nil {
+		return nil, err
+	}
+	options := models.NewPriceRecomputeOptionsForRules(constants.PriceChangeSourceManual, oldRule, updatedRule)
+	return repositories.RecomputeProductsPricesPerCustomer(ctx, options)
+}
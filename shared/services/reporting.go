@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/constants"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/quickbooks/qbmodels"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/reporting"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/repositories"
+)
+
+// GenerateSalesReport aggregates the delivered orders of the period by the dimension.
+//
+// Parameters:
+//   - ctx: context
+//   - dimension: what the orders are grouped by
+//   - period: the period of the report, usually a month (see reporting.NewMonthPeriod)
+//   - compareWithPreviousMonth: adds the sales of the month before the period to every row
+//
+// Returns:
+//   - *reporting.SalesReport: call ToTable to export it to csv, xlsx or pdf
+//   - error: if fetching the orders fails or the dimension is invalid
+func GenerateSalesReport(ctx context.Context, dimension reporting.Dimension, period reporting.Period, compareWithPreviousMonth bool) (*reporting.SalesReport, error) {
+	orders, err := repositories.FetchDeliveredOrdersFromFirestore(ctx, period.From, period.To)
+	if err != nil {
+		return nil, err
+	}
+	report, err := reporting.NewSalesReport(orders, dimension, period)
+	if err != nil {
+		return nil, err
+	}
+	if !compareWithPreviousMonth {
+		return report, nil
+	}
+
+	previousPeriod := period.GetPreviousMonth()
+	previousOrders, err := repositories.FetchDeliveredOrdersFromFirestore(ctx, previousPeriod.From, previousPeriod.To)
+	if err != nil {
+		return nil, err
+	}
+	previousReport, err := reporting.NewSalesReport(previousOrders, dimension, previousPeriod)
+	if err != nil {
+		return nil, err
+	}
+	report.CompareWith(previousReport)
+	return report, nil
+}
+
+// GenerateSalesTaxReport aggregates the delivered and invoiced orders of the filing period by jurisdiction and
+// reconciles their tax against the quickbooks invoices.
+//
+// Parameters:
+//   - ctx: context
+//   - period: the filing period
+//   - invoices: the quickbooks invoices of the period. Orders are matched to them by their QBInvoiceID
+//
+// Returns:
+//   - *reporting.SalesTaxReport: call ToTable to export it to csv or pdf
+//   - error: if fetching the orders fails
+func GenerateSalesTaxReport(ctx context.Context, period reporting.Period, invoices []*qbmodels.Invoice) (*reporting.SalesTaxReport, error) {
+	// Approved orders are only included by the report once they are invoiced
+	statuses := []string{constants.OrderStatusApproved, constants.OrderStatusDelivered}
+	orders, err := repositories.FetchDetailedOrdersByStatusFromFirestore(ctx, statuses, period.From, period.To)
+	if err != nil {
+		return nil, err
+	}
+	quickBooksTaxes := make(map[string]float64, len(invoices))
+	for _, invoice := range invoices {
+		quickBooksTaxes[invoice.ID] = invoice.GetTotalTax()
+	}
+	return reporting.NewSalesTaxReport(orders, quickBooksTaxes, period), nil
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/models"
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/repositories"
+)
+
+// ValidateUserAccountCreate validates a new user account and checks its brands against the brand catalog.
+// The brands of the account are replaced by the display names of the matching brands.
+//
+// Parameters:
+//   - ctx: context for the firestore operations
+//   - account: the account to create
+//
+// Returns:
+//   - error: if the account is not valid or a brand is not an active brand of the catalog
+func ValidateUserAccountCreate(ctx context.Context, account *models.UserAccountCreate) error {
+	if err := account.Validate(); err != nil {
+		return err
+	}
+	brands, err := repositories.FetchBrandCatalogFromFirestore(ctx)
+	if err != nil {
+		return fmt.Errorf("Error while fetching the brands, please try again: %s", err.Error())
+	}
+	return account.ValidateBrands(brands)
+}
+
+// SetNewUserClaims sets the custom claims of a user created from a UserAccountCreate, so the role and the
+// permissions of the new user are in their first token.
+//
+// Parameters:
+//   - ctx: context for the auth operations
+//   - uid: firebase uid of the created user
+//   - account: the validated account, see ValidateUserAccountCreate
+//
+// Returns:
+//   - error: if the claims could not be set
+func SetNewUserClaims(ctx context.Context, uid string, account *models.UserAccountCreate) error {
+	if err := repositories.SetUserClaimsInFirebase(ctx, uid, account.ToUserAccount()); err != nil {
+		return fmt.Errorf("User was created but their permissions could not be set, please try again: %s", err.Error())
+	}
+	return nil
+}
+
+// UpdateUserPermissions replaces the permission overrides of a user and syncs their custom claims.
+//
+// Parameters:
+//   - ctx: context for the firestore and auth operations
+//   - uid: firebase uid of the user
+//   - granted: permissions added to the role of the user
+//   - revoked: permissions removed from the role of the user
+//
+// Returns:
+//   - *models.UserAccount: the updated account
+//   - error: if an override is not valid or the update failed
+func UpdateUserPermissions(ctx context.Context, uid string, granted, revoked []string) (*models.UserAccount, error) {
+	if err := models.ValidatePermissionOverrides(granted, revoked); err != nil {
+		return nil, err
+	}
+	if err := repositories.UpdateUserPermissionsInFirestore(ctx, uid, granted, revoked); err != nil {
+		return nil, err
+	}
+	account, err := repositories.FetchUserAccountFromFirestore(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if err := repositories.SetUserClaimsInFirebase(ctx, uid, account); err != nil {
+		return nil, fmt.Errorf("Permissions were saved but could not be applied, please try again: %s", err.Error())
+	}
+	return account, nil
+}
+
+// SyncUserClaims sets the custom claims of a user from their stored account. Used after the role of the user
+// changes and to backfill the permissions of existing users.
+func SyncUserClaims(ctx context.Context, uid string) error {
+	account, err := repositories.FetchUserAccountFromFirestore(ctx, uid)
+	if err != nil {
+		return err
+	}
+	return repositories.SetUserClaimsInFirebase(ctx, uid, account)
+}
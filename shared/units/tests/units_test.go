@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/units"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected units.Unit
+	}{
+		{"GAL", units.Gallon},
+		{"gallons", units.Gallon},
+		{" Lbs ", units.Pound},
+		{"OZ", units.Ounce},
+		{"fl. oz", units.FluidOunce},
+		{"FL  OZ", units.FluidOunce},
+		{"ml", units.Milliliter},
+		{"SHEETS", units.Sheet},
+	}
+	for _, test := range tests {
+		unit, err := units.Parse(test.name)
+		if err != nil || unit != test.expected {
+			t.Errorf("%q: expected %s, got %s (%v)", test.name, test.expected.Symbol, unit.Symbol, err)
+		}
+	}
+	if _, err := units.Parse("BUCKET"); err == nil {
+		t.Error("expected an error for an unknown unit")
+	}
+	//The stored name of every unit parses back to the unit
+	for _, unit := range []units.Unit{units.Gallon, units.Quart, units.Pint, units.FluidOunce, units.Liter, units.Milliliter, units.Pound, units.Ounce, units.Kilogram, units.Gram, units.Sheet, units.Each} {
+		if parsed, err := units.Parse(unit.Name); err != nil || parsed != unit {
+			t.Errorf("expected %q to parse to %s, got %s (%v)", unit.Name, unit.Symbol, parsed.Symbol, err)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		from, to units.Unit
+		density  float64
+		expected float64
+	}{
+		{"quarts to gallons", 8, units.Quart, units.Gallon, 0, 2},
+		{"fluid ounces to gallons", 64, units.FluidOunce, units.Gallon, 0, 0.5},
+		{"liters to milliliters", 2, units.Liter, units.Milliliter, 0, 2000},
+		{"ounces to pounds", 8, units.Ounce, units.Pound, 0, 0.5},
+		{"kilograms to pounds", 1, units.Kilogram, units.Pound, 0, 2.20462262},
+		{"gallons to pounds", 2, units.Gallon, units.Pound, 8.34, 16.68},
+		{"pounds to gallons", 25, units.Pound, units.Gallon, 12.5, 2},
+		{"ounces to fluid ounces", 16, units.Ounce, units.FluidOunce, 8, 16},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := units.Convert(test.value, test.from, test.to, test.density)
+			if err != nil || math.Abs(value-test.expected) > 1e-9 {
+				t.Errorf("expected %f, got %f (%v)", test.expected, value, err)
+			}
+		})
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	if _, err := units.Convert(1, units.Sheet, units.Gallon, 8.34); err == nil {
+		t.Error("expected an error converting a count to a volume")
+	}
+	if _, err := units.Convert(1, units.Pound, units.Each, 8.34); err == nil {
+		t.Error("expected an error converting a mass to a count")
+	}
+	if _, err := units.Convert(1, units.Gallon, units.Pound, 0); err == nil {
+		t.Error("expected an error converting a volume to a mass without a density")
+	}
+}
@@ -0,0 +1,106 @@
+// Package units converts the sizes of the products between units of volume and mass. Conversions between the
+// two dimensions go through the density of the product in pounds per gallon.
+package units
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dimension is what a unit measures. Only units of the same dimension convert to each other, volume and mass
+// convert with a density.
+type Dimension string
+
+const (
+	DimensionVolume Dimension = "VOLUME"
+	DimensionMass   Dimension = "MASS"
+	DimensionCount  Dimension = "COUNT" // Counted items like sheets, they do not convert to volume or mass
+)
+
+// Unit is a unit of measure. Factor is the size of the unit in the base unit of its dimension: gallons for
+// volume, pounds for mass and single items for count.
+type Unit struct {
+	Name      string // Upper case name the unit is stored with in firestore, e.g. "GAL"
+	Symbol    string // Lower case symbol the unit is displayed with, e.g. "gal"
+	Dimension Dimension
+	Factor    float64
+}
+
+var (
+	Gallon     = Unit{Name: "GAL", Symbol: "gal", Dimension: DimensionVolume, Factor: 1}
+	Quart      = Unit{Name: "QT", Symbol: "qt", Dimension: DimensionVolume, Factor: 0.25}
+	Pint       = Unit{Name: "PT", Symbol: "pt", Dimension: DimensionVolume, Factor: 0.125}
+	FluidOunce = Unit{Name: "FL OZ", Symbol: "fl oz", Dimension: DimensionVolume, Factor: 1.0 / 128}
+	Liter      = Unit{Name: "L", Symbol: "l", Dimension: DimensionVolume, Factor: 0.264172052}
+	Milliliter = Unit{Name: "ML", Symbol: "ml", Dimension: DimensionVolume, Factor: 0.000264172052}
+
+	Pound    = Unit{Name: "LB", Symbol: "lb", Dimension: DimensionMass, Factor: 1}
+	Ounce    = Unit{Name: "OZ", Symbol: "oz", Dimension: DimensionMass, Factor: 1.0 / 16} // Avoirdupois, see FluidOunce for volume
+	Kilogram = Unit{Name: "KG", Symbol: "kg", Dimension: DimensionMass, Factor: 2.20462262}
+	Gram     = Unit{Name: "G", Symbol: "g", Dimension: DimensionMass, Factor: 0.00220462262}
+
+	Sheet = Unit{Name: "SHEETS", Symbol: "sheets", Dimension: DimensionCount, Factor: 1}
+	Each  = Unit{Name: "EA", Symbol: "ea", Dimension: DimensionCount, Factor: 1}
+)
+
+// Names the units are written with in quickbooks and firestore, upper case without dots
+var unitNames = map[string]Unit{
+	"GAL": Gallon, "GALS": Gallon, "GALLON": Gallon, "GALLONS": Gallon,
+	"QT": Quart, "QTS": Quart, "QUART": Quart, "QUARTS": Quart,
+	"PT": Pint, "PTS": Pint, "PINT": Pint, "PINTS": Pint,
+	"FL OZ": FluidOunce, "FLOZ": FluidOunce, "FLUID OUNCE": FluidOunce, "FLUID OUNCES": FluidOunce,
+	"L": Liter, "LTR": Liter, "LITER": Liter, "LITERS": Liter, "LITRE": Liter, "LITRES": Liter,
+	"ML": Milliliter, "MILLILITER": Milliliter, "MILLILITERS": Milliliter, "MILLILITRE": Milliliter, "MILLILITRES": Milliliter,
+	"LB": Pound, "LBS": Pound, "POUND": Pound, "POUNDS": Pound,
+	"OZ": Ounce, "OUNCE": Ounce, "OUNCES": Ounce,
+	"KG": Kilogram, "KGS": Kilogram, "KILOGRAM": Kilogram, "KILOGRAMS": Kilogram,
+	"G": Gram, "GRAM": Gram, "GRAMS": Gram,
+	"SHEET": Sheet, "SHEETS": Sheet,
+	"EA": Each, "EACH": Each, "CT": Each, "COUNT": Each,
+}
+
+// Parse returns the unit written as name, e.g. "GAL", "Lbs" or "fl. oz". The case, the dots and the extra
+// spaces are ignored.
+//
+// Returns:
+//   - Unit: the parsed unit
+//   - error: if the unit is not known
+func Parse(name string) (Unit, error) {
+	normalized := strings.Join(strings.Fields(strings.ToUpper(strings.ReplaceAll(name, ".", ""))), " ")
+	unit, ok := unitNames[normalized]
+	if !ok {
+		return Unit{}, fmt.Errorf("Unknown unit of measure %q", name)
+	}
+	return unit, nil
+}
+
+// Convert converts a value between two units. Volume and mass convert to each other with the density, the
+// density is not used between units of the same dimension.
+//
+// Parameters:
+//   - value: the value in the from unit
+//   - from, to: the units to convert between
+//   - densityLbsPerGallon: density of the measured product in pounds per gallon
+//
+// Returns:
+//   - float64: the value in the to unit
+//   - error: if the units can not be converted to each other or the density is needed and is not positive
+func Convert(value float64, from, to Unit, densityLbsPerGallon float64) (float64, error) {
+	base := value * from.Factor
+	switch {
+	case from.Dimension == to.Dimension:
+	case from.Dimension == DimensionVolume && to.Dimension == DimensionMass:
+		if densityLbsPerGallon <= 0 {
+			return 0, fmt.Errorf("A positive density is needed to convert %s to %s", from.Symbol, to.Symbol)
+		}
+		base *= densityLbsPerGallon
+	case from.Dimension == DimensionMass && to.Dimension == DimensionVolume:
+		if densityLbsPerGallon <= 0 {
+			return 0, fmt.Errorf("A positive density is needed to convert %s to %s", from.Symbol, to.Symbol)
+		}
+		base /= densityLbsPerGallon
+	default:
+		return 0, fmt.Errorf("Can not convert %s (%s) to %s (%s)", from.Symbol, strings.ToLower(string(from.Dimension)), to.Symbol, strings.ToLower(string(to.Dimension)))
+	}
+	return base / to.Factor, nil
+}
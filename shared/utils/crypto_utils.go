@@ -2,9 +2,13 @@
 package utils
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"math/big"
+	"strings"
 )
 
 const letters = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
@@ -40,4 +44,37 @@ func GenerateRandomID(length int) (string, error) {
 		id[i] = letters[n.Int64()]
 	}
 	return string(id), nil
-}
\ No newline at end of file
+}
+
+// SignToken signs a payload with HMAC-SHA256 so it can be handed to a client and trusted when it comes back.
+// The payload is not encrypted, it must not contain secrets.
+//
+// Returns:
+//   - string: "<payload>.<signature>", both URL-safe base64 encoded
+func SignToken(payload string, secret []byte) string {
+	encodedPayload := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encodedPayload + "." + signPayload(encodedPayload, secret)
+}
+
+// VerifySignedToken returns the payload of a token created with SignToken.
+//
+// Returns:
+//   - string: the payload
+//   - error: if the token is malformed or the signature does not match
+func VerifySignedToken(token string, secret []byte) (string, error) {
+	encodedPayload, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(signPayload(encodedPayload, secret))) {
+		return "", errors.New("Token is not valid")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return "", errors.New("Token is not valid")
+	}
+	return string(payload), nil
+}
+
+func signPayload(encodedPayload string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encodedPayload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -3,6 +3,8 @@ package utils
 import (
 	"bytes"
 	"image"
+	"image/color"
+	"image/jpeg"
 	"io"
 	"math"
 	"mime/multipart"
@@ -138,3 +140,48 @@ func FixImageOrientation(img image.Image, orientation int) image.Image {
     }
     return img
 }
+
+//CompressImage downscales an image to fit in a square of maxDimension pixels, keeping its aspect ratio, and
+//encodes it as a JPEG. Transparent pixels are flattened on white since JPEG has no alpha channel.
+//
+//Parameters:
+// - img: The image to compress
+// - maxDimension: The max width and height in pixels. 0 or a larger value than the image keeps its size
+// - quality: The JPEG quality between 1 and 100
+//
+//Returns:
+// - []byte: The bytes of the JPEG
+// - error: The error if any
+func CompressImage(img image.Image, maxDimension int, quality int) ([]byte, error) {
+	bounds := img.Bounds()
+	if maxDimension > 0 && (bounds.Dx() > maxDimension || bounds.Dy() > maxDimension) {
+		img = imaging.Fit(img, maxDimension, maxDimension, imaging.Lanczos)
+	}
+	background := imaging.New(img.Bounds().Dx(), img.Bounds().Dy(), color.White)
+	img = imaging.Overlay(background, img, image.Point{}, 1)
+
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+//GetDistanceInMeters returns the great circle distance between two coordinates with the haversine formula.
+//
+//Parameters:
+// - latitude1, longitude1: The first coordinate in degrees
+// - latitude2, longitude2: The second coordinate in degrees
+//
+//Returns:
+// - float64: The distance in meters
+func GetDistanceInMeters(latitude1, longitude1, latitude2, longitude2 float64) float64 {
+	const earthRadius = 6371000 // Mean radius in meters
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+
+	deltaLatitude := toRadians(latitude2 - latitude1)
+	deltaLongitude := toRadians(longitude2 - longitude1)
+	a := math.Sin(deltaLatitude/2)*math.Sin(deltaLatitude/2) +
+		math.Cos(toRadians(latitude1))*math.Cos(toRadians(latitude2))*math.Sin(deltaLongitude/2)*math.Sin(deltaLongitude/2)
+	return 2 * earthRadius * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/HarshMohanSason/AHSChemicalsGCShared/shared/utils"
+)
+
+var tokenTestSecret = []byte("secret")
+
+func TestSignToken(t *testing.T) {
+	token := utils.SignToken("invite-1.1700000000", tokenTestSecret)
+	payload, err := utils.VerifySignedToken(token, tokenTestSecret)
+	if err != nil || payload != "invite-1.1700000000" {
+		t.Errorf("expected the signed payload, got %q (%v)", payload, err)
+	}
+}
+
+func TestVerifySignedTokenErrors(t *testing.T) {
+	token := utils.SignToken("invite-1.1700000000", tokenTestSecret)
+	encodedPayload, signature, _ := strings.Cut(token, ".")
+	otherPayload := base64.RawURLEncoding.EncodeToString([]byte("invite-2.1700000000"))
+
+	tests := []struct {
+		name   string
+		token  string
+		secret []byte
+	}{
+		{"tampered payload", otherPayload + "." + signature, tokenTestSecret},
+		{"tampered signature", encodedPayload + "." + strings.ToUpper(signature), tokenTestSecret},
+		{"wrong secret", token, []byte("other secret")},
+		{"missing dot", encodedPayload + signature, tokenTestSecret},
+		{"empty token", "", tokenTestSecret},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if payload, err := utils.VerifySignedToken(test.token, test.secret); err == nil {
+				t.Errorf("expected an error, got the payload %q", payload)
+			}
+		})
+	}
+}